@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	imagepromoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+	promoteropts "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
+)
+
+func Add(parent *cobra.Command) {
+	opts := &promoteropts.Options{
+		Threads: promoteropts.DefaultOptions.Threads,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Find images in the registries that no manifest accounts for",
+		Long: `audit - Find images in the registries that no manifest accounts for
+
+This subcommand reads every destination registry declared in the promoter
+manifests passed with --manifest or --thin-manifest-dir, walking them
+recursively, and compares their images with the ones the manifests promote
+to them. This catches images that did not reach production through the
+promoter, like manual pushes or compromised writes:
+
+   kpromo audit --thin-manifest-dir=path/to/registry.k8s.io
+
+The report lists three kinds of findings:
+
+ unknown-digest  a digest no manifest promotes to the image
+ unknown-tag     a tag no manifest declares on a promoted digest
+ tag-mismatch    a tag pointing to another digest than the declared one
+
+The children of image indexes, cosign signatures and attestations and the
+artifacts referring to promoted images are accounted for along with them.
+
+The report is written to stdout or to --output-file as json or yaml, and
+kpromo exits with a non-zero status when there are findings.
+`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return imagepromoter.New(opts).Audit(context.Background(), opts)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(
+		&opts.Manifest,
+		"manifest",
+		"",
+		"the promoter manifest declaring the registries to audit",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.ThinManifestDir,
+		"thin-manifest-dir",
+		"",
+		"directory of thin manifests declaring the registries to audit, read recursively",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.AuditOutput,
+		"output",
+		report.FormatJSON,
+		fmt.Sprintf(
+			"format of the audit report, one of: %s",
+			strings.Join(report.SupportedFormats(false), ", "),
+		),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.OutputFile,
+		"output-file",
+		"",
+		"write the audit report to this file instead of stdout",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&opts.Threads,
+		"threads",
+		promoteropts.DefaultOptions.Threads,
+		"number of concurrent manifest reads",
+	)
+
+	parent.AddCommand(cmd)
+}
@@ -46,6 +46,13 @@ Promote images from a staging registry to production
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(_ *cobra.Command, _ []string) error {
+		rules, err := options.ParseIdentityRules(identityRules)
+		if err != nil {
+			return fmt.Errorf("parsing signature identity rules: %w", err)
+		}
+
+		runOpts.SignIdentityRules = rules
+
 		if err := runPromoteCmd(runOpts); err != nil {
 			return fmt.Errorf("run `cip run`: %w", err)
 		}
@@ -58,6 +65,9 @@ var runOpts = &options.Options{
 	Threads: options.DefaultOptions.Threads,
 }
 
+// identityRules holds the raw prefix=identity signature identity rules.
+var identityRules []string
+
 func init() {
 	CipCmd.PersistentFlags().BoolVar(
 		&runOpts.Confirm,
@@ -98,6 +108,27 @@ the 'images: ...' contents`,
 		`only promote digests from manifests changed within this duration (uses git date format, e.g. "7 days", "24 hours", "1 week")`,
 	)
 
+	CipCmd.PersistentFlags().BoolVar(
+		&runOpts.UseServiceAccount,
+		"use-service-account",
+		runOpts.UseServiceAccount,
+		"impersonate the service-account of each registry of the manifests to access it",
+	)
+
+	CipCmd.PersistentFlags().StringSliceVar(
+		&runOpts.InsecureRegistries,
+		"insecure-registry",
+		runOpts.InsecureRegistries,
+		"registry host whose TLS certificate is not verified (can be repeated)",
+	)
+
+	CipCmd.PersistentFlags().StringSliceVar(
+		&runOpts.PlainHTTPRegistries,
+		"plain-http-registry",
+		runOpts.PlainHTTPRegistries,
+		"registry host talked to over plain HTTP instead of HTTPS (can be repeated)",
+	)
+
 	CipCmd.PersistentFlags().BoolVar(
 		&runOpts.ParseOnly,
 		"parse-only",
@@ -142,6 +173,42 @@ format of the snapshot (allowed values: %q)`,
 		),
 	)
 
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.OutputFile,
+		"output-file",
+		runOpts.OutputFile,
+		fmt.Sprintf(`(only works with '--%s' or '--%s') write the snapshot
+to this file instead of stdout`,
+			snapshotFlag,
+			manifestBasedSnapshotOfFlag,
+		),
+	)
+
+	CipCmd.PersistentFlags().BoolVar(
+		&runOpts.SnapshotDetails,
+		"snapshot-details",
+		runOpts.SnapshotDetails,
+		fmt.Sprintf(`(only works with '--%s' or '--%s' and json or yaml output)
+record the media type, size, creation and upload time of every digest and the
+platforms of image indexes`,
+			snapshotFlag,
+			manifestBasedSnapshotOfFlag,
+		),
+	)
+
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.SnapshotThinManifestDir,
+		"snapshot-thin-manifest-dir",
+		runOpts.SnapshotThinManifestDir,
+		fmt.Sprintf(`(only works with '--%s' or '--%s') write the snapshot
+as images/<project>/images.yaml files into this thin manifest directory, split
+by the first path element of the image names; images without one are written
+to the project named after the last path element of the snapshotted registry`,
+			snapshotFlag,
+			manifestBasedSnapshotOfFlag,
+		),
+	)
+
 	CipCmd.PersistentFlags().StringVar(
 		&runOpts.ManifestBasedSnapshotOf,
 		manifestBasedSnapshotOfFlag,
@@ -212,6 +279,22 @@ network from a registry, it reads from the local manifests only`,
 		"when true, sign promoted images",
 	)
 
+	CipCmd.PersistentFlags().StringSliceVar(
+		&identityRules,
+		"signature-identity-rule",
+		options.FormatIdentityRules(options.DefaultOptions.SignIdentityRules),
+		`rewrite rule in prefix=identity form mapping destination registries to the
+public reference recorded in image signatures; a prefix without a host matches
+that path on any registry (can be repeated, the longest matching prefix wins)`,
+	)
+
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.SignCanonicalRegistry,
+		"canonical-registry",
+		options.DefaultOptions.SignCanonicalRegistry,
+		"destination registry that receives the signatures of promoted images",
+	)
+
 	CipCmd.PersistentFlags().IntVar(
 		&runOpts.MaxSignatureOps,
 		"max-signature-ops",
@@ -224,10 +307,88 @@ network from a registry, it reads from the local manifests only`,
 		"vuln-severity-threshold",
 		options.DefaultOptions.SeverityThreshold,
 		`Using this flag will cause the promoter to only run the vulnerability
-check. Found vulnerabilities at or above this threshold will result in the
+check, unless --vuln-scan-policy is set. Found vulnerabilities at or above this threshold will result in the
 vulnerability check failing [severity levels between 0 and 5; 0 - UNSPECIFIED,
 1 - MINIMAL, 2 - LOW, 3 - MEDIUM, 4 - HIGH, 5 - CRITICAL]`,
 	)
+
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.VulnScanPolicy,
+		"vuln-scan-policy",
+		options.DefaultOptions.VulnScanPolicy,
+		`vulnerability gate of the promotion, using --vuln-severity-threshold: "none" skips it,
+"block-edges" promotes only the images passing the check, "block-run" fails the
+promotion if any image fails the check`,
+	)
+
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.VulnScanner,
+		"vuln-scanner",
+		options.DefaultOptions.VulnScanner,
+		`vulnerability scanner used with --vuln-severity-threshold: "grafeas" queries
+GCP Container Analysis, "report" reads pre-generated Trivy or Grype JSON reports,
+"exec" runs the command set with --vuln-scanner-command`,
+	)
+
+	CipCmd.PersistentFlags().StringSliceVar(
+		&runOpts.VulnExceptions,
+		"vuln-exceptions",
+		nil,
+		`files listing accepted vulnerabilities that the vulnerability check ignores
+until they expire, either exceptions files or OpenVEX documents (can be repeated)`,
+	)
+
+	CipCmd.PersistentFlags().StringSliceVar(
+		&runOpts.VulnScannerCommand,
+		"vuln-scanner-command",
+		nil,
+		`command and arguments run by the exec scanner for each image, which receives
+the image reference as its last argument and writes a JSON scan result to stdout
+(can be repeated or comma separated, e.g. --vuln-scanner-command=my-scanner,--quiet)`,
+	)
+
+	CipCmd.PersistentFlags().DurationVar(
+		&runOpts.VulnScannerTimeout,
+		"vuln-scanner-timeout",
+		options.DefaultOptions.VulnScannerTimeout,
+		"maximum duration of a scan by the exec scanner",
+	)
+
+	CipCmd.PersistentFlags().IntVar(
+		&runOpts.VulnScannerMaxConcurrent,
+		"vuln-scanner-max-concurrent",
+		options.DefaultOptions.VulnScannerMaxConcurrent,
+		"maximum number of exec scanner processes running at the same time",
+	)
+
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.VulnReportDir,
+		"vuln-report-dir",
+		"",
+		`directory with the Trivy or Grype JSON reports of the staging images, named
+after their digests (e.g. sha256-<hex>.json), used by the report scanner`,
+	)
+
+	CipCmd.PersistentFlags().BoolVar(
+		&runOpts.VulnReportReferrers,
+		"vuln-report-referrers",
+		false,
+		"look for vulnerability reports attached to the staging images as OCI referrers, used by the report scanner",
+	)
+
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.VulnScanReportJSON,
+		"vuln-scan-report-json",
+		"",
+		"file to write the full vulnerability scan report to as JSON",
+	)
+
+	CipCmd.PersistentFlags().StringVar(
+		&runOpts.VulnScanReportSARIF,
+		"vuln-scan-report-sarif",
+		"",
+		"file to write the full vulnerability scan report to as SARIF, e.g. for GitHub code scanning",
+	)
 }
 
 func runPromoteCmd(opts *options.Options) error {
@@ -254,8 +415,8 @@ func runPromoteCmd(opts *options.Options) error {
 		return nil
 	}
 
-	// Security scan
-	if opts.SeverityThreshold >= 0 {
+	// Security scan, unless the promotion enforces the vulnerability gate
+	if opts.SeverityThreshold >= 0 && (opts.VulnScanPolicy == "" || opts.VulnScanPolicy == options.VulnScanPolicyNone) {
 		if err := cip.SecurityScan(ctx, opts); err != nil {
 			return fmt.Errorf("security scan: %w", err)
 		}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	promoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+)
+
+// Output formats of the diff command.
+const (
+	diffFormatText = "text"
+	diffFormatJSON = "json"
+)
+
+type diffOptions struct {
+	format   string
+	exitCode bool
+}
+
+var diffOpts = &diffOptions{}
+
+// diffCmd compares the images of two registries, manifests or snapshots.
+var diffCmd = &cobra.Command{
+	Use:   "diff <left> <right>",
+	Short: "Compare the images of two registries, manifests or snapshots",
+	Long: `diff - Compare the images of two sources
+
+Each source is one of:
+
+ registry:<registry>   the images in a registry, e.g. registry:gcr.io/foo
+                       (the prefix can be omitted)
+ manifests:<registry>  the images the manifests set with --manifest or
+                       --thin-manifest-dir promote to a registry
+ file:<path>           a YAML or JSON snapshot written by 'kpromo cip --snapshot'
+
+Digests only found in <right> are reported as added (+), digests only found in
+<left> as removed (-), and digests found in both with different tags as
+retagged (~). For example, to list what production holds but the manifests do
+not declare:
+
+ kpromo cip diff --thin-manifest-dir=<dir> \
+   manifests:us.gcr.io/k8s-artifacts-prod registry:us.gcr.io/k8s-artifacts-prod
+`,
+	Args:          cobra.ExactArgs(2),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := runDiffCmd(cmd, args[0], args[1]); err != nil {
+			return fmt.Errorf("run `cip diff`: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVar(
+		&diffOpts.format,
+		"format",
+		diffFormatText,
+		fmt.Sprintf("output format of the differences (%s or %s)", diffFormatText, diffFormatJSON),
+	)
+
+	diffCmd.Flags().BoolVar(
+		&diffOpts.exitCode,
+		"exit-code",
+		false,
+		"exit with a non-zero status if the sources differ",
+	)
+
+	CipCmd.AddCommand(diffCmd)
+}
+
+func runDiffCmd(cmd *cobra.Command, left, right string) error {
+	diff, err := promoter.New(runOpts).Diff(context.Background(), runOpts, left, right)
+	if err != nil {
+		return fmt.Errorf("comparing images: %w", err)
+	}
+
+	var out string
+
+	switch strings.ToLower(diffOpts.format) {
+	case diffFormatText:
+		out = diff.ToText()
+	case diffFormatJSON:
+		out, err = diff.ToJSON()
+		if err != nil {
+			return err
+		}
+
+		out += "\n"
+	default:
+		return fmt.Errorf("invalid diff output format: %s", diffOpts.format)
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), out)
+
+	if diffOpts.exitCode && !diff.Empty() {
+		return errors.New("the sources differ")
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cip
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	promoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+)
+
+// exportCmd writes the images of the manifests into an air-gapped bundle.
+var exportCmd = &cobra.Command{
+	Use:   "export --out <bundle.tar>",
+	Short: "Export the images of the manifests into an OCI image layout archive",
+	Long: `export - Export promoted images into an air-gapped bundle
+
+Writes the images the manifests set with --manifest or --thin-manifest-dir
+promote, along with their signatures and attestations, into a single OCI image
+layout archive. The images are read from the first destination registry of
+each manifest and can be restricted with --images and --tags. The bundle is
+pushed into another registry with 'kpromo cip import'.
+`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		if err := promoter.New(runOpts).Export(context.Background(), runOpts); err != nil {
+			return fmt.Errorf("run `cip export`: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(
+		&runOpts.BundleFile,
+		"out",
+		"",
+		"path of the OCI image layout archive to write",
+	)
+
+	exportCmd.Flags().StringSliceVar(
+		&runOpts.BundleImages,
+		"images",
+		nil,
+		"only export the images with these names (can be repeated)",
+	)
+
+	exportCmd.Flags().StringSliceVar(
+		&runOpts.BundleTags,
+		"tags",
+		nil,
+		"only export the digests with these tags (can be repeated)",
+	)
+
+	CipCmd.AddCommand(exportCmd)
+}
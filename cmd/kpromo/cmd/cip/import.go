@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cip
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	promoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+)
+
+// importCmd pushes an air-gapped bundle into a registry.
+var importCmd = &cobra.Command{
+	Use:   "import --bundle <bundle.tar> --registry <registry>",
+	Short: "Import an OCI image layout archive into a registry",
+	Long: `import - Import an air-gapped bundle into a registry
+
+Pushes the images of a bundle written by 'kpromo cip export', along with their
+signatures and attestations, into a registry. Digests are preserved. Once
+pushed, the images are verified to be signed by the identity set with the
+signature check flags, unless --verify-signatures=false is set.
+
+Without --confirm, the copies are only logged.
+`,
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		if err := promoter.New(runOpts).Import(context.Background(), runOpts); err != nil {
+			return fmt.Errorf("run `cip import`: %w", err)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(
+		&runOpts.BundleFile,
+		"bundle",
+		"",
+		"path of the OCI image layout archive to import",
+	)
+
+	importCmd.Flags().StringVar(
+		&runOpts.ImportRegistry,
+		"registry",
+		"",
+		"registry to push the images into, e.g. registry.example.com/mirror",
+	)
+
+	importCmd.Flags().BoolVar(
+		&runOpts.ImportVerifySignatures,
+		"verify-signatures",
+		options.DefaultOptions.ImportVerifySignatures,
+		"when true, verify the signatures of the imported images",
+	)
+
+	for _, flagName := range []string{"bundle", "registry"} {
+		//nolint:errcheck // flag is required, error handled by cobra at runtime
+		importCmd.MarkFlagRequired(flagName)
+	}
+
+	CipCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirrorcheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	imagepromoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+	promoteropts "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
+)
+
+func Add(parent *cobra.Command) {
+	opts := &promoteropts.Options{
+		Threads: promoteropts.DefaultOptions.Threads,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "mirrorcheck",
+		Short: "Check image consistency across the registry mirrors",
+		Long: `mirrorcheck - Check image consistency across the registry mirrors
+
+This subcommand reads the images of the promoter manifests passed with
+--manifest or --thin-manifest-dir from every destination registry of each
+manifest and cross-compares the mirrors. A partial promotion failure can leave
+some mirrors without a digest or a tag the others have:
+
+   kpromo mirrorcheck --thin-manifest-dir=path/to/registry.k8s.io
+
+The report lists three kinds of findings:
+
+ missing-digest  a digest other mirrors of the image have
+ missing-tag     a tag other mirrors have on a digest this mirror has
+ tag-conflict    a tag pointing to different digests in different mirrors
+
+With --fix, missing digests and tags are copied from a mirror having them. Tag
+conflicts are only reported, as there is no way to tell the right digest.
+
+The report is written to stdout or to --output-file as json or yaml, and
+kpromo exits with a non-zero status when inconsistencies are left.
+`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return imagepromoter.New(opts).CheckMirrors(context.Background(), opts)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(
+		&opts.Manifest,
+		"manifest",
+		"",
+		"the promoter manifest declaring the mirrors to check",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.ThinManifestDir,
+		"thin-manifest-dir",
+		"",
+		"directory of thin manifests declaring the mirrors to check, read recursively",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&opts.MirrorCheckFix,
+		"fix",
+		false,
+		"copy the digests and tags missing from some mirrors from a mirror having them",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.MirrorCheckOutput,
+		"output",
+		report.FormatJSON,
+		fmt.Sprintf(
+			"format of the mirror check report, one of: %s",
+			strings.Join(report.SupportedFormats(false), ", "),
+		),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.OutputFile,
+		"output-file",
+		"",
+		"write the mirror check report to this file instead of stdout",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&opts.Threads,
+		"threads",
+		promoteropts.DefaultOptions.Threads,
+		"number of concurrent copies when fixing mirrors",
+	)
+
+	parent.AddCommand(cmd)
+}
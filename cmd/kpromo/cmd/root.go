@@ -24,12 +24,15 @@ import (
 	"sigs.k8s.io/release-utils/log"
 	"sigs.k8s.io/release-utils/version"
 
+	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/audit"
 	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/cip"
 	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/gh"
 	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/manifest"
+	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/mirrorcheck"
 	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/pr"
 	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/run"
 	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/sigcheck"
+	"sigs.k8s.io/promo-tools/v4/cmd/kpromo/cmd/staging"
 )
 
 // rootCmd represents the base command when called without any subcommands.
@@ -82,6 +85,9 @@ func init() {
 	rootCmd.AddCommand(pr.PRCmd)
 	rootCmd.AddCommand(version.Version())
 	sigcheck.Add(rootCmd)
+	audit.Add(rootCmd)
+	mirrorcheck.Add(rootCmd)
+	staging.Add(rootCmd)
 }
 
 func initLogging(*cobra.Command, []string) error {
@@ -18,36 +18,59 @@ package sigcheck
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	imagepromoter "sigs.k8s.io/promo-tools/v4/promoter/image"
 	promoteropts "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
 )
 
 func Add(parent *cobra.Command) {
 	opts := &promoteropts.Options{}
+
+	var identityRules []string
+
 	cmd := &cobra.Command{
 		Use:   "sigcheck",
 		Short: "Check image signature consistency",
-		Long: `sigcheck - Check signature consistency across the K8s mirrors
+		Long: `sigcheck - Check signature consistency across the registry mirrors
 
 This subcommand checks the signature consistency across promoted images
 to ensure copies in all mirrors have their signatures attached.
 
+The mirrors are the destination registries declared in the promoter manifests
+passed with --manifest or --thin-manifest-dir. Every destination registry of
+every manifest serving an image is checked:
+
+   kpromo sigcheck --thin-manifest-dir=path/to/registry.k8s.io
+
 By default, kpromo sigcheck will look at all images promoted during the last
 %d days. You can change the default using --from-days and determine a range
 using --to-days. For example, to verify all images promoted in an interval
 between 10 and 5 days ago run:
 
-   kpromo sigcheck --from-days=10 --to-days=5
+   kpromo sigcheck --thin-manifest-dir=path/to/registry.k8s.io --from-days=10 --to-days=5
 
 To debug the signature checker, you can limit the number of images kpromo
 verifies using --limit. When no limit is specified, kpromo will check the
 signatures of all images in the specified date range. As an example, to limit
 kpromo to the first three images it finds run:
 
-   kpromo sigcheck --limit=3
+   kpromo sigcheck --thin-manifest-dir=path/to/registry.k8s.io --limit=3
+
+With --attestations, sigcheck also checks that the image in every mirror has
+the promotion record attestation written when it was promoted. Missing
+attestations are replicated from another mirror or, when no mirror has one,
+generated again from the promotion declared in the manifests.
+
+To publish the results, use --output to write a json, yaml or markdown report
+to stdout. It lists the status of the signature in every mirror along with the
+fixes applied, or the ones that would be applied without --confirm:
+
+   kpromo sigcheck --thin-manifest-dir=path/to/registry.k8s.io --output=json
 
     `,
 		SilenceUsage:  true,
@@ -57,12 +80,33 @@ kpromo to the first three images it finds run:
 				opts.SignCheckReferences = args
 			}
 
+			rules, err := promoteropts.ParseIdentityRules(identityRules)
+			if err != nil {
+				return fmt.Errorf("parsing signature identity rules: %w", err)
+			}
+
+			opts.SignIdentityRules = rules
+
 			p := imagepromoter.New(opts)
 
 			return p.CheckSignatures(context.Background(), opts)
 		},
 	}
 
+	cmd.PersistentFlags().StringVar(
+		&opts.Manifest,
+		"manifest",
+		"",
+		"the promoter manifest declaring the mirrors to check",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.ThinManifestDir,
+		"thin-manifest-dir",
+		"",
+		"directory of thin manifests declaring the mirrors to check, read recursively",
+	)
+
 	cmd.PersistentFlags().BoolVar(
 		&opts.SignCheckFix,
 		"confirm",
@@ -70,6 +114,23 @@ kpromo to the first three images it finds run:
 		"when true, kpromo will sign and propagate missing signatures in images",
 	)
 
+	cmd.PersistentFlags().BoolVar(
+		&opts.SignCheckAttestations,
+		"attestations",
+		false,
+		"also check that every mirror has a promotion record attestation; with --confirm, missing ones are replicated or pushed",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.SignCheckOutput,
+		"output",
+		"",
+		fmt.Sprintf(
+			"write a report of the results to stdout in one of these formats: %s",
+			strings.Join(report.SupportedFormats(true), ", "),
+		),
+	)
+
 	cmd.PersistentFlags().IntVar(
 		&opts.SignCheckFromDays,
 		"from-days",
@@ -119,5 +180,21 @@ kpromo to the first three images it finds run:
 		"A regular expression alternative to --certificate-oidc-issuer. Accepts the Go regular expression syntax described at https://golang.org/s/re2syntax.",
 	)
 
+	cmd.PersistentFlags().StringSliceVar(
+		&identityRules,
+		"signature-identity-rule",
+		promoteropts.FormatIdentityRules(promoteropts.DefaultOptions.SignIdentityRules),
+		`rewrite rule in prefix=identity form mapping mirror registries to the public
+reference of their images; a prefix without a host matches that path on any
+registry (can be repeated, the longest matching prefix wins)`,
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.SignCanonicalRegistry,
+		"canonical-registry",
+		promoteropts.DefaultOptions.SignCanonicalRegistry,
+		"registry indexed to find the latest promoted images",
+	)
+
 	parent.AddCommand(cmd)
 }
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package staging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	imagepromoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+	promoteropts "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
+)
+
+func Add(parent *cobra.Command) {
+	cmd := &cobra.Command{
+		Use:   "staging",
+		Short: "Manage staging registries",
+	}
+
+	addGC(cmd)
+
+	parent.AddCommand(cmd)
+}
+
+func addGC(parent *cobra.Command) {
+	opts := &promoteropts.Options{
+		Threads: promoteropts.DefaultOptions.Threads,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "gc <registry>",
+		Short: "Garbage collect the images of a staging registry",
+		Long: fmt.Sprintf(`gc - Garbage collect the images of a staging registry
+
+This subcommand reads a staging registry recursively and applies a retention
+policy to its images. A digest is kept when:
+
+ - it is declared in the promoter manifests passed with --manifest or
+   --thin-manifest-dir, so it was promoted,
+ - it has one of the --keep-tags most recently uploaded tags of its image
+   (%d by default),
+ - it was uploaded less than --keep-days days ago (%d by default), or the
+   registry does not report its upload time.
+
+The children of kept image indexes and the cosign signatures, attestations and
+SBOMs of kept digests are kept along with them. The other digests are listed
+as deletable:
+
+   kpromo staging gc --thin-manifest-dir=path/to/registry.k8s.io gcr.io/k8s-staging-foo
+
+The report is written to stdout or to --output-file as json or yaml. Nothing
+is deleted unless --confirm is set, in which case the tags and manifests of
+the deletable digests are deleted and kpromo exits with a non-zero status if
+any deletion fails.
+`, promoteropts.DefaultOptions.StagingGCKeepTags, promoteropts.DefaultOptions.StagingGCKeepDays),
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			opts.StagingGCRegistry = args[0]
+
+			return imagepromoter.New(opts).StagingGC(context.Background(), opts)
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(
+		&opts.Manifest,
+		"manifest",
+		"",
+		"the promoter manifest declaring the promoted images",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.ThinManifestDir,
+		"thin-manifest-dir",
+		"",
+		"directory of thin manifests declaring the promoted images, read recursively",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&opts.StagingGCKeepTags,
+		"keep-tags",
+		promoteropts.DefaultOptions.StagingGCKeepTags,
+		"number of most recently uploaded tags to keep per image",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&opts.StagingGCKeepDays,
+		"keep-days",
+		promoteropts.DefaultOptions.StagingGCKeepDays,
+		"keep the digests uploaded less than this many days ago",
+	)
+
+	cmd.PersistentFlags().BoolVar(
+		&opts.Confirm,
+		"confirm",
+		false,
+		"delete the digests the retention policy does not keep",
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.StagingGCOutput,
+		"output",
+		report.FormatJSON,
+		fmt.Sprintf(
+			"format of the staging gc report, one of: %s",
+			strings.Join(report.SupportedFormats(false), ", "),
+		),
+	)
+
+	cmd.PersistentFlags().StringVar(
+		&opts.OutputFile,
+		"output-file",
+		"",
+		"write the staging gc report to this file instead of stdout",
+	)
+
+	cmd.PersistentFlags().IntVar(
+		&opts.Threads,
+		"threads",
+		promoteropts.DefaultOptions.Threads,
+		"number of concurrent registry reads and deletions",
+	)
+
+	parent.AddCommand(cmd)
+}
@@ -26,13 +26,13 @@ import (
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 )
 
-// statementSigner signs an in-toto statement and returns a sigstore bundle
+// StatementSigner signs an in-toto statement and returns a sigstore bundle
 // that can be attached to an image.
-type statementSigner interface {
+type StatementSigner interface {
 	SignStatement(statement []byte) ([]byte, error)
 }
 
-// carabinerSigner implements statementSigner using the carabiner-dev
+// carabinerSigner implements StatementSigner using the carabiner-dev
 // signer with sigstore keyless signing. The underlying signer is safe
 // for concurrent use, so signing parallelizes up to MaxSignatureOps.
 // The Fulcio cert is fetched once and reused by every signature  until
@@ -0,0 +1,280 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	checkresults "sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/provenance"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
+)
+
+// CheckAttestations checks in parallel which of a list of digest references
+// have a promotion record attestation attached, and records them in list.
+// The references whose attestations could not be looked up are recorded
+// with their error, so they are not fixed.
+func (di *DefaultPromoterImplementation) CheckAttestations(
+	ctx context.Context, list *checkresults.CheckList, refs []string,
+) error {
+	found := make([]bool, len(refs))
+	errs := make([]error, len(refs))
+
+	g := new(errgroup.Group)
+	g.SetLimit(10)
+
+	for i, ref := range refs {
+		digest, err := name.NewDigest(ref)
+		if err != nil {
+			return fmt.Errorf("parsing digest reference %s: %w", ref, err)
+		}
+
+		g.Go(func() error {
+			found[i], errs[i] = di.hasBundleForPredicate(ctx, digest, provenance.PredicateType)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("checking attestations: %w", err)
+	}
+
+	for i, ref := range refs {
+		switch {
+		case errs[i] != nil:
+			logrus.WithField("image", ref).Warnf("Unable to check the promotion record attestation: %v", errs[i])
+
+			if list.AttestationErrors == nil {
+				list.AttestationErrors = map[string]string{}
+			}
+
+			list.AttestationErrors[ref] = errs[i].Error()
+		case found[i]:
+			list.Attested = append(list.Attested, ref)
+		default:
+			logrus.WithField("image", ref).Info("No promotion record attestation found")
+
+			list.MissingAttestation = append(list.MissingAttestation, ref)
+		}
+	}
+
+	return nil
+}
+
+// FixMissingAttestations attaches a promotion record attestation to every
+// mirror missing one. When another mirror has the attestation, it is
+// replicated. Otherwise, a new attestation is generated for each mirror
+// from the promotion edge declared in the manifests.
+func (di *DefaultPromoterImplementation) FixMissingAttestations(
+	ctx context.Context,
+	opts *options.Options,
+	results checkresults.Signature,
+	generator provenance.Generator,
+) error {
+	var edges map[string]promotion.Edge
+
+	for mainImg, res := range results {
+		if len(res.MissingAttestation) == 0 {
+			continue
+		}
+
+		if len(res.Attested) > 0 {
+			logrus.Infof("Replicating the attestation of %s to %d mirrors", mainImg, len(res.MissingAttestation))
+
+			for _, targetRef := range res.MissingAttestation {
+				if err := di.replicateAttestation(ctx, opts, res.Attested[0], targetRef); err != nil {
+					return fmt.Errorf("replicating attestation: %w", err)
+				}
+
+				res.Actions = append(res.Actions, checkresults.Action{
+					Type:    checkresults.ActionReplicateAttestation,
+					Source:  res.Attested[0],
+					Target:  targetRef,
+					Applied: opts.SignCheckFix,
+				})
+			}
+
+			results[mainImg] = res
+
+			continue
+		}
+
+		if edges == nil {
+			var err error
+
+			edges, err = di.attestationEdges(opts)
+			if err != nil {
+				return fmt.Errorf("reading promotion edges: %w", err)
+			}
+		}
+
+		logrus.Infof("Attesting %s in %d mirrors", mainImg, len(res.MissingAttestation))
+
+		for _, targetRef := range res.MissingAttestation {
+			edge, ok := edges[targetRef]
+			if !ok {
+				logrus.Warnf("No promotion of %s found in the manifests, not attesting", targetRef)
+
+				continue
+			}
+
+			if err := di.attestReference(ctx, opts, generator, &edge); err != nil {
+				return fmt.Errorf("attesting %s: %w", targetRef, err)
+			}
+
+			res.Actions = append(res.Actions, checkresults.Action{
+				Type:    checkresults.ActionAttest,
+				Target:  targetRef,
+				Applied: opts.SignCheckFix,
+			})
+		}
+
+		results[mainImg] = res
+	}
+
+	return nil
+}
+
+// attestationEdges indexes the promotion edges declared in the manifests by
+// the digest reference of their destination image.
+func (di *DefaultPromoterImplementation) attestationEdges(opts *options.Options) (map[string]promotion.Edge, error) {
+	mfests, err := di.ParseManifests(opts)
+	if err != nil {
+		return nil, fmt.Errorf("parsing manifests: %w", err)
+	}
+
+	edges := map[string]promotion.Edge{}
+
+	for _, mfest := range mfests {
+		if mfest.SrcRegistry == nil {
+			continue
+		}
+
+		for _, rc := range mfest.Registries {
+			if rc.Src {
+				continue
+			}
+
+			for _, img := range mfest.Images {
+				for digest, tags := range img.Dmap {
+					edge := promotion.Edge{
+						SrcRegistry: *mfest.SrcRegistry,
+						SrcImageTag: promotion.ImageTag{Name: img.Name},
+						Digest:      digest,
+						DstRegistry: rc,
+						DstImageTag: promotion.ImageTag{Name: img.Name},
+					}
+
+					if len(tags) > 0 {
+						edge.SrcImageTag.Tag = tags[0]
+						edge.DstImageTag.Tag = tags[0]
+					}
+
+					ref := fmt.Sprintf("%s/%s@%s", strings.TrimRight(string(rc.Name), "/"), img.Name, digest)
+					edges[ref] = edge
+				}
+			}
+		}
+	}
+
+	return edges, nil
+}
+
+// attestReference generates and pushes a promotion record attestation for
+// the destination image of an edge.
+func (di *DefaultPromoterImplementation) attestReference(
+	ctx context.Context, opts *options.Options, generator provenance.Generator, edge *promotion.Edge,
+) error {
+	if !opts.SignCheckFix {
+		logrus.Infof(" (NOOP) attesting %s", edge.DstReference())
+
+		return nil
+	}
+
+	logrus.Infof(" attesting %s", edge.DstReference())
+
+	//nolint:contextcheck
+	if err := di.ensureAttestationSigner(opts); err != nil {
+		return fmt.Errorf("initializing attestation signer: %w", err)
+	}
+
+	record := provenance.PromotionRecord{
+		SrcRef:    edge.SrcReference(),
+		DstRef:    edge.DstReference(),
+		Digest:    string(edge.Digest),
+		Timestamp: timestamppb.New(time.Now()),
+		BuilderId: promotionBuilderID(),
+	}
+
+	return di.pushAttestation(ctx, edge, generator, &record)
+}
+
+// replicateAttestation copies the promotion record attestation of an image
+// in one mirror to the same image in another mirror. The attestation is
+// pushed by digest, so the registry links it to its subject again.
+func (di *DefaultPromoterImplementation) replicateAttestation(
+	ctx context.Context, opts *options.Options, srcRef, dstRef string,
+) error {
+	src, err := name.NewDigest(srcRef)
+	if err != nil {
+		return fmt.Errorf("parsing digest reference %s: %w", srcRef, err)
+	}
+
+	dst, err := name.NewDigest(dstRef)
+	if err != nil {
+		return fmt.Errorf("parsing digest reference %s: %w", dstRef, err)
+	}
+
+	bundle, ok, err := di.bundleForPredicate(ctx, src, provenance.PredicateType)
+	if err != nil {
+		return fmt.Errorf("looking up the attestation of %s: %w", srcRef, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("no promotion record attestation found for %s", srcRef)
+	}
+
+	srcBundle := src.Context().Digest(bundle.String()).String()
+	dstBundle := dst.Context().Digest(bundle.String()).String()
+
+	if !opts.SignCheckFix {
+		logrus.Infof(" (NOOP) replicating attestation %s to %s ", srcBundle, dstBundle)
+
+		return nil
+	}
+
+	logrus.Infof(" replicating attestation %s to %s ", srcBundle, dstBundle)
+
+	if err := ratelimit.WithRetry(func() error {
+		return di.copyWithTimeout(ctx, srcBundle, dstBundle, ratelimit.CopyTimeout)
+	}); err != nil {
+		return fmt.Errorf("copying attestation %s to %s: %w", srcBundle, dstBundle, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/require"
+
+	checkresults "sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/provenance"
+	reg "sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry/registryfakes"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+func TestCheckAndFixAttestations(t *testing.T) {
+	t.Parallel()
+
+	host, di := newTLSTestRegistry(t)
+	gen := &fakeGenerator{data: []byte(`{"test": "attestation"}`)}
+	di.attSigner = &fakeStatementSigner{bundle: []byte(`{"test": "bundle"}`)}
+
+	// The same image is promoted to three mirrors, only the first one
+	// has its attestation.
+	digest := pushTestImage(t, di, host+"/mirror1/myimage:v1.0")
+	for _, mirror := range []string{"mirror2", "mirror3"} {
+		require.NoError(t, di.registryProvider.CopyImage(
+			context.Background(), host+"/mirror1/myimage:v1.0", host+"/"+mirror+"/myimage:v1.0",
+		))
+	}
+
+	edge := promotion.Edge{
+		SrcRegistry: reg.Context{Name: image.Registry(host + "/staging"), Src: true},
+		SrcImageTag: promotion.ImageTag{Name: "myimage", Tag: "v1.0"},
+		Digest:      image.Digest(digest),
+		DstRegistry: reg.Context{Name: image.Registry(host + "/mirror1")},
+		DstImageTag: promotion.ImageTag{Name: "myimage", Tag: "v1.0"},
+	}
+	require.NoError(t, di.pushAttestation(context.Background(), &edge, gen, &provenance.PromotionRecord{}))
+
+	refs := make([]string, 0, 3)
+	for _, mirror := range []string{"mirror1", "mirror2", "mirror3"} {
+		refs = append(refs, fmt.Sprintf("%s/%s/myimage@%s", host, mirror, digest))
+	}
+
+	list := checkresults.CheckList{}
+	require.NoError(t, di.CheckAttestations(context.Background(), &list, refs))
+	require.Equal(t, refs[:1], list.Attested)
+	require.Equal(t, refs[1:], list.MissingAttestation)
+	require.Empty(t, list.AttestationErrors)
+
+	// mirror3 is also declared in a manifest, so a new attestation can be
+	// generated for it when no mirror has one to replicate.
+	manifest := filepath.Join(t.TempDir(), "promoter-manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, fmt.Appendf(nil, `registries:
+- name: %[1]s/staging
+  src: true
+- name: %[1]s/mirror3
+images:
+- name: myimage
+  dmap:
+    "%[2]s": ["v1.0"]
+`, host, digest), 0o600))
+
+	results := checkresults.Signature{
+		"example.com/myimage:v1.0": {Attested: refs[:1], MissingAttestation: refs[1:2]},
+		"example.com/other:v1.0":   {MissingAttestation: refs[2:]},
+	}
+
+	// Without SignCheckFix the fixes are only planned
+	opts := &options.Options{Manifest: manifest}
+	require.NoError(t, di.FixMissingAttestations(context.Background(), opts, results, gen))
+
+	list = checkresults.CheckList{}
+	require.NoError(t, di.CheckAttestations(context.Background(), &list, refs))
+	require.Equal(t, refs[1:], list.MissingAttestation)
+
+	results = checkresults.Signature{
+		"example.com/myimage:v1.0": {Attested: refs[:1], MissingAttestation: refs[1:2]},
+		"example.com/other:v1.0":   {MissingAttestation: refs[2:]},
+	}
+
+	opts.SignCheckFix = true
+	require.NoError(t, di.FixMissingAttestations(context.Background(), opts, results, gen))
+
+	require.Equal(t, []checkresults.Action{{
+		Type: checkresults.ActionReplicateAttestation, Source: refs[0], Target: refs[1], Applied: true,
+	}}, results["example.com/myimage:v1.0"].Actions)
+	require.Equal(t, []checkresults.Action{{
+		Type: checkresults.ActionAttest, Target: refs[2], Applied: true,
+	}}, results["example.com/other:v1.0"].Actions)
+
+	list = checkresults.CheckList{}
+	require.NoError(t, di.CheckAttestations(context.Background(), &list, refs))
+	require.Equal(t, refs, list.Attested)
+	require.Empty(t, list.MissingAttestation)
+}
+
+func TestCheckAttestationsLookupError(t *testing.T) {
+	t.Parallel()
+
+	const digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	refs := []string{"mirror1.example.com/foo@" + digest, "mirror2.example.com/foo@" + digest}
+
+	provider := &registryfakes.FakeProvider{}
+	provider.ListReferrersCalls(func(_ context.Context, ref, _ string) ([]v1.Descriptor, error) {
+		if ref == refs[0] {
+			return nil, errors.New("referrers unavailable")
+		}
+
+		return nil, nil
+	})
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	// A failed lookup is reported, not taken for a missing attestation
+	list := checkresults.CheckList{}
+	require.NoError(t, di.CheckAttestations(context.Background(), &list, refs))
+	require.Empty(t, list.Attested)
+	require.Equal(t, refs[1:], list.MissingAttestation)
+	require.Len(t, list.AttestationErrors, 1)
+	require.Contains(t, list.AttestationErrors[refs[0]], "referrers unavailable")
+
+	// so it is not fixed either
+	results := checkresults.Signature{
+		"example.com/foo:v1.0": {AttestationErrors: list.AttestationErrors},
+	}
+	require.Zero(t, results.TotalUnattested())
+
+	opts := &options.Options{SignCheckFix: true}
+	require.NoError(t, di.FixMissingAttestations(context.Background(), opts, results, &fakeGenerator{}))
+	require.Empty(t, results["example.com/foo:v1.0"].Actions)
+}
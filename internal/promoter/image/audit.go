@@ -0,0 +1,222 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"sync"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/audit"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// AuditRegistries reads the destination registries declared in the
+// manifests recursively and compares their images with the ones the
+// manifests promote to them.
+func (di *DefaultPromoterImplementation) AuditRegistries(
+	ctx context.Context, opts *options.Options, mfests []schema.Manifest,
+) (*audit.Report, error) {
+	edges, err := promotion.ToEdges(mfests)
+	if err != nil {
+		return nil, fmt.Errorf("converting manifests to edges: %w", err)
+	}
+
+	expected := map[image.Registry]registry.RegInvImage{}
+
+	for _, mfest := range mfests {
+		for _, rc := range mfest.Registries {
+			if !rc.Src {
+				expected[rc.Name] = registry.RegInvImage{}
+			}
+		}
+	}
+
+	if len(expected) == 0 {
+		return nil, errors.New("no destination registries found in manifests")
+	}
+
+	for edge := range edges {
+		rii, ok := expected[edge.DstRegistry.Name]
+		if !ok {
+			continue
+		}
+
+		if rii[edge.DstImageTag.Name] == nil {
+			rii[edge.DstImageTag.Name] = registry.DigestTags{}
+		}
+
+		tags := rii[edge.DstImageTag.Name][edge.Digest]
+		if tags == nil {
+			tags = registry.TagSlice{}
+		}
+
+		if edge.DstImageTag.Tag != "" {
+			tags = append(tags, edge.DstImageTag.Tag)
+		}
+
+		rii[edge.DstImageTag.Name][edge.Digest] = tags
+	}
+
+	configs := make([]registry.RegistryConfig, 0, len(expected))
+	for reg := range expected {
+		configs = append(configs, registry.RegistryConfig{Name: reg})
+	}
+
+	// Walking a registry also walks the registries nested in it, so key
+	// every repository by the longest registry containing it.
+	sort.Slice(configs, func(i, j int) bool {
+		if len(configs[i].Name) != len(configs[j].Name) {
+			return len(configs[i].Name) > len(configs[j].Name)
+		}
+
+		return configs[i].Name < configs[j].Name
+	})
+
+	inv, err := di.registryProvider.ReadRegistries(ctx, configs, true, configs)
+	if err != nil {
+		return nil, fmt.Errorf("reading destination registries: %w", err)
+	}
+
+	accounted, err := di.accountedDigests(ctx, opts, inv, expected)
+	if err != nil {
+		return nil, err
+	}
+
+	report := audit.Compare(expected, inv.Images, accounted)
+
+	logrus.Infof(
+		"Audited %d registries: %d unknown digests, %d unknown tags, %d tag mismatches",
+		len(report.Registries),
+		report.Count(audit.KindUnknownDigest),
+		report.Count(audit.KindUnknownTag),
+		report.Count(audit.KindTagMismatch),
+	)
+
+	return report, nil
+}
+
+// signatureArtifactTypes are the artifact types of the signatures and
+// attestations attached to the images with the referrers API.
+var signatureArtifactTypes = []string{
+	"application/vnd.dev.sigstore.bundle.v0.3+json",
+	"application/vnd.dev.cosign.artifact.sig.v1+json",
+	"application/vnd.dev.cosign.artifact.att.v1+json",
+	"application/vnd.dsse.envelope.v1+json",
+	"application/vnd.in-toto+json",
+}
+
+// accountedDigests returns the digests of the inventory that are pushed
+// along with the promoted images without being declared in the manifests:
+// the children of image indexes and the signatures and attestations whose
+// subject is an image declared in the same repository. Only the manifests
+// of image indexes and of the undeclared untagged digests are read.
+func (di *DefaultPromoterImplementation) accountedDigests(
+	ctx context.Context,
+	opts *options.Options,
+	inv *registry.Inventory,
+	expected map[image.Registry]registry.RegInvImage,
+) (map[image.Digest]bool, error) {
+	accounted := map[image.Digest]bool{}
+
+	var mu sync.Mutex
+
+	g := new(errgroup.Group)
+	g.SetLimit(concurrencyLimit(opts.Threads))
+
+	for reg, rii := range inv.Images {
+		for name, dmap := range rii {
+			for digest, tags := range dmap {
+				mediaType := inv.MediaTypes[digest]
+				isIndex := mediaType == cr.DockerManifestList || mediaType == cr.OCIImageIndex
+				_, declared := expected[reg][name][digest]
+
+				if !isIndex && (declared || len(tags) > 0) {
+					continue
+				}
+
+				ref := path.Join(string(reg), string(name)) + "@" + string(digest)
+
+				g.Go(func() error {
+					rawManifest, _, err := di.registryProvider.GetManifest(ctx, ref)
+					if err != nil {
+						return fmt.Errorf("reading manifest %s: %w", ref, err)
+					}
+
+					var manifest struct {
+						ArtifactType string          `json:"artifactType"`
+						Config       *v1.Descriptor  `json:"config"`
+						Manifests    []v1.Descriptor `json:"manifests"`
+						Subject      *v1.Descriptor  `json:"subject"`
+					}
+
+					if err := json.Unmarshal(rawManifest, &manifest); err != nil {
+						return fmt.Errorf("parsing manifest %s: %w", ref, err)
+					}
+
+					// The artifact type of a manifest defaults to the
+					// media type of its config
+					artifactType := manifest.ArtifactType
+					if artifactType == "" && manifest.Config != nil {
+						artifactType = string(manifest.Config.MediaType)
+					}
+
+					mu.Lock()
+					defer mu.Unlock()
+
+					for i := range manifest.Manifests {
+						accounted[image.Digest(manifest.Manifests[i].Digest.String())] = true
+					}
+
+					if manifest.Subject != nil && slices.Contains(signatureArtifactTypes, artifactType) {
+						if _, ok := expected[reg][name][image.Digest(manifest.Subject.Digest.String())]; ok {
+							accounted[digest] = true
+						}
+					}
+
+					return nil
+				})
+			}
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("reading the undeclared manifests: %w", err)
+	}
+
+	return accounted, nil
+}
+
+// WriteAuditReport writes the audit report to stdout or opts.OutputFile in
+// the format configured in the options.
+func (di *DefaultPromoterImplementation) WriteAuditReport(opts *options.Options, report *audit.Report) error {
+	return writeReport(opts.OutputFile, "audit report", report, opts.AuditOutput)
+}
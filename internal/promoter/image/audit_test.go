@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/audit"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry/registryfakes"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+func TestAuditRegistries(t *testing.T) {
+	host, di := newTLSTestRegistry(t)
+	transport := remote.WithTransport(di.getTransport())
+
+	child, err := random.Image(1024, 1)
+	require.NoError(t, err)
+
+	idx := mutate.AppendManifests(
+		mutate.IndexMediaType(empty.Index, cr.OCIImageIndex),
+		mutate.IndexAddendum{Add: child},
+	)
+
+	idxRef, err := name.ParseReference(host + "/production/foo:v1.0")
+	require.NoError(t, err)
+	require.NoError(t, remote.WriteIndex(idxRef, idx, transport))
+
+	idxDesc, err := partial.Descriptor(idx)
+	require.NoError(t, err)
+
+	childDigest, err := child.Digest()
+	require.NoError(t, err)
+
+	// pushReferrer attaches an artifact of artifactType to subject with
+	// the referrers API and returns its digest
+	pushReferrer := func(subject v1.Descriptor, artifactType cr.MediaType) image.Digest {
+		artifact, err := random.Image(128, 1)
+		require.NoError(t, err)
+
+		artifact = mutate.ConfigMediaType(mutate.MediaType(artifact, cr.OCIManifestSchema1), artifactType)
+
+		referrer, ok := mutate.Subject(artifact, subject).(v1.Image)
+		require.True(t, ok)
+
+		referrerDigest, err := referrer.Digest()
+		require.NoError(t, err)
+
+		referrerRef, err := name.ParseReference(host + "/production/foo@" + referrerDigest.String())
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(referrerRef, referrer, transport))
+
+		return image.Digest(referrerDigest.String())
+	}
+
+	// An untagged image pushed by hand
+	manual, err := random.Image(1024, 1)
+	require.NoError(t, err)
+
+	manualDesc, err := partial.Descriptor(manual)
+	require.NoError(t, err)
+
+	manualRef, err := name.ParseReference(host + "/production/foo@" + manualDesc.Digest.String())
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(manualRef, manual, transport))
+
+	manualDigest := image.Digest(manualDesc.Digest.String())
+
+	// An attestation of the declared index is accounted for, but not an
+	// artifact of another type, nor an attestation of the undeclared image
+	attestationDigest := pushReferrer(*idxDesc, "application/vnd.dev.sigstore.bundle.v0.3+json")
+	reportDigest := pushReferrer(*idxDesc, "application/vnd.aquasec.trivy.report+json")
+	manualAttestationDigest := pushReferrer(*manualDesc, "application/vnd.dev.sigstore.bundle.v0.3+json")
+
+	const nestedDigest = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+
+	prod := image.Registry(host + "/production")
+	nested := image.Registry(host + "/production/nested")
+
+	inv := registry.NewFakeProvider()
+	inv.AddImage(prod, "foo", image.Digest(idxDesc.Digest.String()), "v1.0", "latest")
+	inv.AddImage(prod, "foo", image.Digest(childDigest.String()))
+	inv.AddImage(prod, "foo", attestationDigest)
+	inv.AddImage(prod, "foo", reportDigest)
+	inv.AddImage(prod, "foo", manualDigest)
+	inv.AddImage(prod, "foo", manualAttestationDigest)
+	inv.AddImage(nested, "bar", nestedDigest, "v1.0")
+	inv.Inventory.MediaTypes[image.Digest(idxDesc.Digest.String())] = cr.OCIImageIndex
+
+	provider := &registryfakes.FakeProvider{}
+	provider.ReadRegistriesReturns(inv.Inventory, nil)
+	provider.GetManifestCalls(di.registryProvider.GetManifest)
+	di.registryProvider = provider
+
+	mfests := []schema.Manifest{
+		{
+			Registries: []registry.Context{
+				{Name: image.Registry(host + "/staging"), Src: true},
+				{Name: prod},
+			},
+			Images: []registry.Image{
+				{Name: "foo", Dmap: registry.DigestTags{image.Digest(idxDesc.Digest.String()): {"v1.0"}}},
+			},
+		},
+		{
+			Registries: []registry.Context{
+				{Name: image.Registry(host + "/staging"), Src: true},
+				{Name: nested},
+			},
+			Images: []registry.Image{
+				{Name: "bar", Dmap: registry.DigestTags{nestedDigest: {"v1.0"}}},
+			},
+		},
+	}
+
+	for i := range mfests {
+		require.NoError(t, mfests[i].Finalize())
+	}
+
+	report, err := di.AuditRegistries(context.Background(), &options.Options{}, mfests)
+	require.NoError(t, err)
+
+	require.Equal(t, []image.Registry{prod, nested}, report.Registries)
+	// Findings are sorted by digest and the index digest is random
+	require.ElementsMatch(t, []audit.Finding{
+		{Kind: audit.KindUnknownTag, Registry: prod, Image: "foo", Digest: image.Digest(idxDesc.Digest.String()), Tag: "latest"},
+		{Kind: audit.KindUnknownDigest, Registry: prod, Image: "foo", Digest: reportDigest, Tags: registry.TagSlice{}},
+		{Kind: audit.KindUnknownDigest, Registry: prod, Image: "foo", Digest: manualDigest, Tags: registry.TagSlice{}},
+		{Kind: audit.KindUnknownDigest, Registry: prod, Image: "foo", Digest: manualAttestationDigest, Tags: registry.TagSlice{}},
+	}, report.Findings)
+
+	// Registries are walked recursively, keyed by the longest one
+	_, configs, recurse, baseConfigs := provider.ReadRegistriesArgsForCall(0)
+	require.True(t, recurse)
+	require.Equal(t, nested, configs[0].Name)
+	require.Equal(t, configs, baseConfigs)
+
+	opts := &options.Options{OutputFile: filepath.Join(t.TempDir(), "audit.json")}
+	require.NoError(t, di.WriteAuditReport(opts, report))
+
+	data, err := os.ReadFile(opts.OutputFile)
+	require.NoError(t, err)
+
+	decoded := &audit.Report{}
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Len(t, decoded.Findings, 4)
+	require.Equal(t, 3, decoded.Count(audit.KindUnknownDigest))
+
+	// Unreadable manifests fail the audit instead of being reported
+	provider.GetManifestReturns(nil, "", errors.New("manifest unknown"))
+
+	_, err = di.AuditRegistries(context.Background(), &options.Options{}, mfests)
+	require.ErrorContains(t, err, "manifest unknown")
+}
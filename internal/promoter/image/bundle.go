@@ -0,0 +1,544 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// cosignTagSuffixes are the suffixes of the tags cosign attaches objects
+// to a digest with.
+var cosignTagSuffixes = []string{".sig", ".att", ".sbom"}
+
+// bundleCopy is a reference copied into or out of a bundle.
+type bundleCopy struct {
+	src string
+	dst string
+}
+
+// ExportImages copies the images declared in the manifests into an OCI
+// image layout archive written to opts.BundleFile, optionally restricted
+// to opts.BundleImages and opts.BundleTags. Images are read from the first
+// destination registry of their manifest, along with the cosign
+// signatures, attestations and SBOMs and the referrers of their digests
+// and of the children of image indexes.
+func (di *DefaultPromoterImplementation) ExportImages(
+	ctx context.Context, opts *options.Options, mfests []schema.Manifest,
+) error {
+	if opts.BundleFile == "" {
+		return errors.New("no bundle file set")
+	}
+
+	dir, err := os.MkdirTemp("", "kpromo-export-")
+	if err != nil {
+		return fmt.Errorf("creating bundle directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	bundle := registry.LayoutScheme + dir
+
+	exports := exportedImages(opts, mfests)
+	if len(exports) == 0 {
+		return errors.New("no images to export")
+	}
+
+	var (
+		mu     sync.Mutex
+		copies []bundleCopy
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrencyLimit(opts.Threads))
+
+	for e, tags := range exports {
+		g.Go(func() error {
+			repo := fmt.Sprintf("%s/%s", e.registry, e.name)
+			dst := fmt.Sprintf("%s/%s", bundle, e.name)
+
+			imageCopies := []bundleCopy{}
+			for _, tag := range tags {
+				imageCopies = append(imageCopies, bundleCopy{
+					src: fmt.Sprintf("%s@%s", repo, e.digest),
+					dst: fmt.Sprintf("%s:%s", dst, tag),
+				})
+			}
+
+			if len(tags) == 0 {
+				imageCopies = append(imageCopies, bundleCopy{
+					src: fmt.Sprintf("%s@%s", repo, e.digest),
+					dst: fmt.Sprintf("%s@%s", dst, e.digest),
+				})
+			}
+
+			attached, err := di.attachedCopies(gctx, repo, dst, e.digest)
+			if err != nil {
+				return fmt.Errorf("finding the objects attached to %s@%s: %w", repo, e.digest, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			copies = append(copies, imageCopies...)
+			copies = append(copies, attached...)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err //nolint:wrapcheck // errors are wrapped in the goroutines
+	}
+
+	if err := di.copyBundleReferences(ctx, opts, copies); err != nil {
+		return err
+	}
+
+	if err := writeBundle(dir, opts.BundleFile); err != nil {
+		return fmt.Errorf("writing bundle %s: %w", opts.BundleFile, err)
+	}
+
+	logrus.Infof("Exported %d images and %d references to %s", len(exports), len(copies), opts.BundleFile)
+
+	return nil
+}
+
+// exportedImage is a digest of an image to export.
+type exportedImage struct {
+	registry image.Registry
+	name     image.Name
+	digest   image.Digest
+}
+
+// exportedImages returns the digests declared in the manifests that match
+// the image and tag filters of the options, read from the first
+// destination registry of their manifest, along with their selected tags.
+func exportedImages(opts *options.Options, mfests []schema.Manifest) map[exportedImage][]image.Tag {
+	exports := map[exportedImage][]image.Tag{}
+
+	for i := range mfests {
+		idx := slices.IndexFunc(mfests[i].Registries, func(rc registry.Context) bool { return !rc.Src })
+		if idx < 0 {
+			continue
+		}
+
+		reg := image.Registry(strings.TrimSuffix(string(mfests[i].Registries[idx].Name), "/"))
+
+		for _, img := range mfests[i].Images {
+			if len(opts.BundleImages) > 0 && !slices.Contains(opts.BundleImages, string(img.Name)) {
+				continue
+			}
+
+			for digest, tags := range img.Dmap {
+				selected := []image.Tag{}
+
+				for _, tag := range tags {
+					if len(opts.BundleTags) == 0 || slices.Contains(opts.BundleTags, string(tag)) {
+						selected = append(selected, tag)
+					}
+				}
+
+				if len(opts.BundleTags) > 0 && len(selected) == 0 {
+					continue
+				}
+
+				key := exportedImage{registry: reg, name: img.Name, digest: digest}
+				exports[key] = append(exports[key], selected...)
+			}
+		}
+	}
+
+	for e, tags := range exports {
+		slices.Sort(tags)
+		exports[e] = slices.Compact(tags)
+	}
+
+	return exports
+}
+
+// attachedCopies returns the copies of the cosign objects and referrers
+// attached to a digest and to the children of an image index.
+func (di *DefaultPromoterImplementation) attachedCopies(
+	ctx context.Context, repo, dst string, digest image.Digest,
+) ([]bundleCopy, error) {
+	digests := []image.Digest{digest}
+
+	children, err := di.childDigests(ctx, repo, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	digests = append(digests, children...)
+
+	copies := []bundleCopy{}
+
+	for _, d := range digests {
+		for _, suffix := range cosignTagSuffixes {
+			tag := strings.ReplaceAll(string(d), "sha256:", "sha256-") + suffix
+			if _, err := di.registryProvider.HeadDigest(ctx, repo+":"+tag); err != nil {
+				continue
+			}
+
+			copies = append(copies, bundleCopy{src: repo + ":" + tag, dst: dst + ":" + tag})
+		}
+
+		referrers, err := di.registryProvider.ListReferrers(ctx, fmt.Sprintf("%s@%s", repo, d), "")
+		if err != nil {
+			logrus.Debugf("Unable to list the referrers of %s@%s: %v", repo, d, err)
+
+			continue
+		}
+
+		for _, referrer := range referrers {
+			copies = append(copies, bundleCopy{
+				src: fmt.Sprintf("%s@%s", repo, referrer.Digest),
+				dst: fmt.Sprintf("%s@%s", dst, referrer.Digest),
+			})
+		}
+	}
+
+	return copies, nil
+}
+
+// childDigests returns the digests of the children of an image index, or
+// nothing if the digest is not an index.
+func (di *DefaultPromoterImplementation) childDigests(
+	ctx context.Context, repo string, digest image.Digest,
+) ([]image.Digest, error) {
+	raw, mediaType, err := di.registryProvider.GetManifest(ctx, fmt.Sprintf("%s@%s", repo, digest))
+	if err != nil {
+		return nil, fmt.Errorf("getting manifest: %w", err)
+	}
+
+	if !mediaType.IsIndex() {
+		return nil, nil
+	}
+
+	var index v1.IndexManifest
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, fmt.Errorf("parsing index: %w", err)
+	}
+
+	children := make([]image.Digest, 0, len(index.Manifests))
+	for _, desc := range index.Manifests {
+		children = append(children, image.Digest(desc.Digest.String()))
+	}
+
+	return children, nil
+}
+
+// ImportImages pushes the images of the bundle opts.BundleFile into
+// opts.ImportRegistry, preserving their digests and tags. Without
+// opts.Confirm, the copies are only logged. When opts.ImportVerifySignatures
+// is set, the signatures of the imported images, tagged or not, are then
+// verified against the signature check identity, and an error is returned
+// if any image is unsigned or signed by another identity. Cosign objects and
+// referrers are not verified.
+func (di *DefaultPromoterImplementation) ImportImages(ctx context.Context, opts *options.Options) error {
+	if opts.BundleFile == "" {
+		return errors.New("no bundle file set")
+	}
+
+	target := strings.TrimSuffix(opts.ImportRegistry, "/")
+	if target == "" {
+		return errors.New("no registry to import into set")
+	}
+
+	dir, err := os.MkdirTemp("", "kpromo-import-")
+	if err != nil {
+		return fmt.Errorf("creating bundle directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := readBundle(opts.BundleFile, dir); err != nil {
+		return fmt.Errorf("reading bundle %s: %w", opts.BundleFile, err)
+	}
+
+	bundle := image.Registry(registry.LayoutScheme + dir)
+
+	inv, err := di.registryProvider.ReadRegistries(ctx, []registry.RegistryConfig{{Name: bundle, Src: true}}, true, nil)
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+
+	var (
+		copies []bundleCopy
+		signed []string
+	)
+
+	for imageName, dmap := range inv.Images[bundle] {
+		src := fmt.Sprintf("%s/%s", bundle, imageName)
+		dst := fmt.Sprintf("%s/%s", target, imageName)
+
+		for digest, tags := range dmap {
+			for _, tag := range tags {
+				copies = append(copies, bundleCopy{src: src + ":" + string(tag), dst: dst + ":" + string(tag)})
+			}
+
+			if len(tags) == 0 {
+				copies = append(copies, bundleCopy{
+					src: fmt.Sprintf("%s@%s", src, digest),
+					dst: fmt.Sprintf("%s@%s", dst, digest),
+				})
+			}
+
+			verify, err := di.isBundleImage(ctx, fmt.Sprintf("%s@%s", src, digest), tags)
+			if err != nil {
+				return err
+			}
+
+			if verify {
+				signed = append(signed, fmt.Sprintf("%s@%s", dst, digest))
+			}
+		}
+	}
+
+	slices.SortFunc(copies, func(a, b bundleCopy) int { return strings.Compare(a.dst, b.dst) })
+	slices.Sort(signed)
+
+	if !opts.Confirm {
+		for _, c := range copies {
+			logrus.Infof("Would copy %s to %s", strings.TrimPrefix(c.src, string(bundle)+"/"), c.dst)
+		}
+
+		logrus.Infof("Dry run: %d references would be imported, use --confirm to import them", len(copies))
+
+		return nil
+	}
+
+	if err := di.copyBundleReferences(ctx, opts, copies); err != nil {
+		return err
+	}
+
+	logrus.Infof("Imported %d references into %s", len(copies), target)
+
+	if !opts.ImportVerifySignatures {
+		return nil
+	}
+
+	return di.verifyImportedImages(opts, signed)
+}
+
+// isBundleImage returns whether a digest of a bundle is an image, tagged
+// or not, rather than a cosign object or a referrer attached to an image.
+func (di *DefaultPromoterImplementation) isBundleImage(ctx context.Context, ref string, tags registry.TagSlice) (bool, error) {
+	if len(tags) > 0 {
+		return slices.ContainsFunc(tags, func(tag image.Tag) bool { return !registry.CosignTag.MatchString(string(tag)) }), nil
+	}
+
+	raw, _, err := di.registryProvider.GetManifest(ctx, ref)
+	if err != nil {
+		return false, fmt.Errorf("reading manifest of %s: %w", ref, err)
+	}
+
+	var manifest struct {
+		Subject *v1.Descriptor `json:"subject,omitempty"`
+	}
+
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return false, fmt.Errorf("parsing manifest of %s: %w", ref, err)
+	}
+
+	return manifest.Subject == nil, nil
+}
+
+// verifyImportedImages checks that the imported images are signed by the
+// signature check identity.
+func (di *DefaultPromoterImplementation) verifyImportedImages(opts *options.Options, refs []string) error {
+	verifiers := []ImageVerifier{}
+	for _, signer := range trustedSigners(opts, nil) {
+		verifiers = append(verifiers, di.verifierFor(signerOptions(opts, signer)))
+	}
+
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	g := new(errgroup.Group)
+	g.SetLimit(concurrencyLimit(opts.MaxSignatureOps))
+
+	for _, ref := range refs {
+		g.Go(func() error {
+			signed, err := verifyStagingReference(verifiers, ref)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err != nil:
+				errs = append(errs, fmt.Errorf("imported image %s is not signed by a trusted identity: %w", ref, err))
+			case !signed:
+				errs = append(errs, fmt.Errorf("imported image %s is not signed", ref))
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("verifying imported images: %w", err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("verifying imported images: %w", errors.Join(errs...))
+	}
+
+	logrus.Infof("Verified the signatures of %d imported images", len(refs))
+
+	return nil
+}
+
+// copyBundleReferences runs the copies into or out of a bundle.
+func (di *DefaultPromoterImplementation) copyBundleReferences(
+	ctx context.Context, opts *options.Options, copies []bundleCopy,
+) error {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrencyLimit(opts.Threads))
+
+	for _, c := range copies {
+		g.Go(func() error {
+			logrus.Debugf("Copying %s to %s", c.src, c.dst)
+
+			if err := ratelimit.WithRetry(func() error {
+				return di.registryProvider.CopyImage(gctx, c.src, c.dst)
+			}); err != nil {
+				return fmt.Errorf("copying %s to %s: %w", c.src, c.dst, err)
+			}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err //nolint:wrapcheck // errors are wrapped in the goroutines
+	}
+
+	return nil
+}
+
+// writeBundle archives the content of a directory into a tar file.
+func writeBundle(dir, file string) (err error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	tw := tar.NewWriter(f)
+
+	if err := tw.AddFS(os.DirFS(dir)); err != nil {
+		return fmt.Errorf("archiving %s: %w", dir, err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing archive: %w", err)
+	}
+
+	return nil
+}
+
+// readBundle extracts a tar file into a directory, refusing entries that
+// would be written outside of it.
+func readBundle(file, dir string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("reading archive: %w", err)
+		}
+
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("invalid path %q in archive", header.Name)
+		}
+
+		path := filepath.Join(dir, header.Name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return fmt.Errorf("creating %s: %w", header.Name, err)
+			}
+		case tar.TypeReg:
+			if err := extractFile(tr, path); err != nil {
+				return fmt.Errorf("extracting %s: %w", header.Name, err)
+			}
+		default:
+			return fmt.Errorf("unsupported entry %q in archive", header.Name)
+		}
+	}
+}
+
+// extractFile writes the current entry of a tar reader to a file.
+func extractFile(r io.Reader, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, fs.FileMode(0o644))
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"archive/tar"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	reg "sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+	"sigs.k8s.io/release-sdk/sign"
+)
+
+func TestExportImportImages(t *testing.T) {
+	t.Parallel()
+
+	const identity = "signer@example.com"
+
+	host, di := newTLSTestRegistry(t)
+	ctx := context.Background()
+
+	fooDigest := pushTestImage(t, di, host+"/production/foo:v1.0")
+	sigTag := strings.ReplaceAll(fooDigest, "sha256:", "sha256-") + ".sig"
+	sigDigest := pushTestImage(t, di, host+"/production/foo:"+sigTag)
+	barDigest := pushTestImage(t, di, host+"/production/bar:v2.0")
+	bazDigest := pushTestImage(t, di, host+"/production/baz:v3.0")
+
+	// An attestation attached to foo with the referrers API
+	fooRef, err := name.ParseReference(host + "/production/foo:v1.0")
+	require.NoError(t, err)
+
+	fooDesc, err := remote.Head(fooRef, remote.WithTransport(di.getTransport()))
+	require.NoError(t, err)
+
+	artifact, err := random.Image(128, 1)
+	require.NoError(t, err)
+
+	referrer, ok := mutate.Subject(artifact, *fooDesc).(v1.Image)
+	require.True(t, ok)
+
+	referrerDigest, err := partial.Digest(referrer)
+	require.NoError(t, err)
+
+	referrerRef, err := name.ParseReference(host + "/production/foo@" + referrerDigest.String())
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(referrerRef, referrer, remote.WithTransport(di.getTransport())))
+
+	mfests := []schema.Manifest{{
+		Registries: []reg.Context{
+			{Name: image.Registry(host + "/staging"), Src: true},
+			{Name: image.Registry(host + "/production")},
+		},
+		Images: []reg.Image{
+			{Name: "foo", Dmap: reg.DigestTags{image.Digest(fooDigest): {"v1.0"}}},
+			{Name: "bar", Dmap: reg.DigestTags{image.Digest(barDigest): {"v2.0"}}},
+			{Name: "baz", Dmap: reg.DigestTags{image.Digest(bazDigest): {}}},
+		},
+	}}
+
+	di.newVerifier = func(o *sign.Options) ImageVerifier {
+		return &fakeImageVerifier{
+			identity: o.CertIdentity,
+			signedBy: map[string]string{host + "/mirror/foo@" + fooDigest: identity},
+		}
+	}
+
+	dir := t.TempDir()
+	fooBundle := filepath.Join(dir, "foo.tar")
+	barBundle := filepath.Join(dir, "bar.tar")
+
+	opts := &options.Options{
+		Threads:                2,
+		MaxSignatureOps:        2,
+		SignCheckIdentity:      identity,
+		BundleFile:             fooBundle,
+		BundleImages:           []string{"foo"},
+		ImportRegistry:         host + "/mirror",
+		ImportVerifySignatures: true,
+	}
+	require.NoError(t, di.ExportImages(ctx, opts, mfests))
+
+	// Nothing is imported without confirmation
+	require.NoError(t, di.ImportImages(ctx, opts))
+
+	_, err = di.registryProvider.HeadDigest(ctx, host+"/mirror/foo:v1.0")
+	require.Error(t, err)
+
+	opts.Confirm = true
+	require.NoError(t, di.ImportImages(ctx, opts))
+
+	digest, err := di.registryProvider.HeadDigest(ctx, host+"/mirror/foo:v1.0")
+	require.NoError(t, err)
+	require.EqualValues(t, fooDigest, digest)
+
+	digest, err = di.registryProvider.HeadDigest(ctx, host+"/mirror/foo:"+sigTag)
+	require.NoError(t, err)
+	require.EqualValues(t, sigDigest, digest)
+
+	// The referrer is imported without being verified
+	digest, err = di.registryProvider.HeadDigest(ctx, host+"/mirror/foo@"+referrerDigest.String())
+	require.NoError(t, err)
+	require.EqualValues(t, referrerDigest.String(), digest)
+
+	_, err = di.registryProvider.HeadDigest(ctx, host+"/mirror/bar:v2.0")
+	require.Error(t, err)
+
+	// Unsigned images fail the verification once imported
+	opts.BundleFile = barBundle
+	opts.BundleImages = nil
+	opts.BundleTags = []string{"v2.0"}
+	require.NoError(t, di.ExportImages(ctx, opts, mfests))
+
+	err = di.ImportImages(ctx, opts)
+	require.ErrorContains(t, err, host+"/mirror/bar@"+barDigest+" is not signed")
+
+	opts.ImportVerifySignatures = false
+	require.NoError(t, di.ImportImages(ctx, opts))
+
+	// Images exported by digest are verified as well
+	opts.BundleTags = nil
+	opts.BundleImages = []string{"baz"}
+	opts.ImportVerifySignatures = true
+	require.NoError(t, di.ExportImages(ctx, opts, mfests))
+
+	err = di.ImportImages(ctx, opts)
+	require.ErrorContains(t, err, host+"/mirror/baz@"+bazDigest+" is not signed")
+
+	// Filters matching nothing have nothing to export
+	opts.BundleTags = []string{"v3.0"}
+	require.Error(t, di.ExportImages(ctx, opts, mfests))
+}
+
+func TestReadBundle(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bundle.tar")
+
+	f, err := os.Create(file)
+	require.NoError(t, err)
+
+	tw := tar.NewWriter(f)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0o644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err = tw.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+
+	out := filepath.Join(dir, "out")
+	require.ErrorContains(t, readBundle(file, out), "invalid path")
+
+	_, err = os.Stat(filepath.Join(dir, "evil"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -31,14 +31,81 @@ import (
 	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
 // This file has all the promoter implementation functions
 // related to image promotion.
 
 // ParseManifests reads the manifest file or manifest directory
-// and parses them to return a slice of Manifest objects.
+// and parses them to return a slice of Manifest objects. The credentials
+// and transport settings of their registries are registered in the
+// keychain and transports, if set.
 func (di *DefaultPromoterImplementation) ParseManifests(opts *options.Options) ([]schema.Manifest, error) {
+	mfests, err := readManifests(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := di.addRegistries(opts, mfests); err != nil {
+		return nil, err
+	}
+
+	return mfests, nil
+}
+
+// addRegistries registers the credentials and transport settings of the
+// registries of the manifests, and of the insecure and plain HTTP
+// registries of the options.
+func (di *DefaultPromoterImplementation) addRegistries(opts *options.Options, mfests []schema.Manifest) error {
+	if di.keychain != nil {
+		for i := range mfests {
+			if err := di.keychain.AddContexts(context.Background(), mfests[i].Registries, opts.UseServiceAccount); err != nil {
+				return fmt.Errorf("adding registry credentials: %w", err)
+			}
+		}
+	}
+
+	if di.transports != nil {
+		if err := di.transports.AddContexts(optionsTransports(opts)); err != nil {
+			return fmt.Errorf("adding registry transports: %w", err)
+		}
+
+		for i := range mfests {
+			if err := di.transports.AddContexts(mfests[i].Registries); err != nil {
+				return fmt.Errorf("adding registry transports: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// optionsTransports returns the registry contexts of the insecure and
+// plain HTTP registries of the options.
+func optionsTransports(opts *options.Options) []registry.Context {
+	rcs := make([]registry.Context, 0, len(opts.InsecureRegistries)+len(opts.PlainHTTPRegistries))
+
+	for _, r := range opts.InsecureRegistries {
+		rcs = append(rcs, registry.Context{
+			Name:      image.Registry(r),
+			Transport: &registry.TransportConfig{Insecure: true},
+		})
+	}
+
+	for _, r := range opts.PlainHTTPRegistries {
+		rcs = append(rcs, registry.Context{
+			Name:      image.Registry(r),
+			Transport: &registry.TransportConfig{PlainHTTP: true},
+		})
+	}
+
+	return rcs
+}
+
+// readManifests reads the manifest file or the thin manifest directory of
+// the options.
+func readManifests(opts *options.Options) ([]schema.Manifest, error) {
 	// If the options have a manifest file defined, we use that one
 	if opts.Manifest != "" {
 		mfest, err := schema.ParseManifestFromFile(opts.Manifest)
@@ -133,7 +200,9 @@ func (di *DefaultPromoterImplementation) PromoteImages(
 
 	var completed atomic.Int64
 
-	g, ctx := errgroup.WithContext(ctx)
+	// The copies are independent: one failing does not cancel the others,
+	// so a later run only has to promote what failed.
+	g := new(errgroup.Group)
 	g.SetLimit(opts.Threads)
 
 	for edge := range edges {
@@ -0,0 +1,265 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/require"
+
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	reg "sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+func TestPromoteImagesLayout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	staging := image.Registry(reg.LayoutScheme + filepath.Join(dir, "staging"))
+	prod := image.Registry(reg.LayoutScheme + filepath.Join(dir, "prod"))
+
+	path, err := layout.Write(filepath.Join(dir, "staging", "foo"), empty.Index)
+	require.NoError(t, err)
+
+	img, err := random.Image(1024, 1)
+	require.NoError(t, err)
+	require.NoError(t, path.AppendImage(img, layout.WithAnnotations(map[string]string{
+		"org.opencontainers.image.ref.name": "v1.0",
+	})))
+
+	idx, err := random.Index(1024, 1, 2)
+	require.NoError(t, err)
+	require.NoError(t, path.AppendIndex(idx))
+
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+
+	idxDigest, err := idx.Digest()
+	require.NoError(t, err)
+
+	di := &DefaultPromoterImplementation{registryProvider: reg.NewCraneProvider()}
+
+	mfests := []schema.Manifest{{
+		Registries: []reg.Context{{Name: staging, Src: true}, {Name: prod}},
+		Images: []reg.Image{{Name: "foo", Dmap: reg.DigestTags{
+			image.Digest(imgDigest.String()): {"v1.0"},
+			image.Digest(idxDigest.String()): {"v2.0"},
+		}}},
+	}}
+	require.NoError(t, mfests[0].Finalize())
+
+	opts := &options.Options{Threads: 2}
+
+	edges, err := di.GetPromotionEdges(context.Background(), opts, mfests)
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+	require.NoError(t, di.PromoteImages(context.Background(), opts, edges))
+
+	tags, err := di.registryProvider.ListTags(context.Background(), string(prod)+"/foo")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []image.Tag{"v1.0", "v2.0"}, tags)
+
+	digest, err := di.registryProvider.HeadDigest(context.Background(), string(prod)+"/foo:v2.0")
+	require.NoError(t, err)
+	require.EqualValues(t, idxDigest.String(), digest)
+
+	// Promoting again has nothing left to copy
+	edges, err = di.GetPromotionEdges(context.Background(), opts, mfests)
+	require.NoError(t, err)
+	require.Empty(t, edges)
+}
+
+func TestGetPromotionEdges(t *testing.T) {
+	const (
+		index = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		amd64 = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		bar   = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+		lost  = "sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd"
+		moved = "sha256:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	)
+
+	provider := reg.NewFakeProvider()
+	provider.AddIndex("gcr.io/staging", "foo", index, []reg.IndexChild{
+		{Digest: amd64, Platform: "linux/amd64"},
+	}, "v1.0")
+	provider.AddImage("gcr.io/staging", "bar", bar, "v2.0")
+	provider.AddImage("gcr.io/prod", "bar", bar, "v2.0")
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	mfests := []schema.Manifest{{
+		Registries: []reg.Context{{Name: "gcr.io/staging", Src: true}, {Name: "gcr.io/prod"}},
+		Images: []reg.Image{
+			{Name: "foo", Dmap: reg.DigestTags{index: {"v1.0"}}},
+			{Name: "bar", Dmap: reg.DigestTags{bar: {"v2.0"}, lost: {"v0.1"}}},
+		},
+	}}
+	require.NoError(t, mfests[0].Finalize())
+
+	// Promoted and lost images are skipped
+	edges, err := di.GetPromotionEdges(context.Background(), &options.Options{}, mfests)
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+
+	for edge := range edges {
+		require.EqualValues(t, index, edge.Digest)
+		require.EqualValues(t, "gcr.io/prod", edge.DstRegistry.Name)
+	}
+
+	require.Len(t, provider.CallsTo("ReadRegistries"), 1)
+
+	// Tags are never moved
+	provider.AddImage("gcr.io/prod", "foo", moved, "v1.0")
+
+	_, err = di.GetPromotionEdges(context.Background(), &options.Options{}, mfests)
+	require.ErrorContains(t, err, "encountered errors during edge filtering")
+}
+
+func TestPromoteImagesFailures(t *testing.T) {
+	const digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	provider := reg.NewFakeProvider()
+	provider.AddImage("gcr.io/staging", "foo", digest, "v1.0")
+	provider.CopyImageErrs = map[string]error{"gcr.io/mirror": errors.New("denied")}
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	mfests := []schema.Manifest{{
+		Registries: []reg.Context{
+			{Name: "gcr.io/staging", Src: true},
+			{Name: "gcr.io/prod"},
+			{Name: "gcr.io/mirror"},
+		},
+		Images: []reg.Image{{Name: "foo", Dmap: reg.DigestTags{digest: {"v1.0"}}}},
+	}}
+	require.NoError(t, mfests[0].Finalize())
+
+	opts := &options.Options{Threads: 1}
+
+	edges, err := di.GetPromotionEdges(context.Background(), opts, mfests)
+	require.NoError(t, err)
+	require.Len(t, edges, 2)
+
+	// A failing destination fails the promotion without retries, and
+	// without cancelling the copies to the other destinations
+	provider.Latency = time.Millisecond
+
+	err = di.PromoteImages(context.Background(), opts, edges)
+	require.ErrorContains(t, err, "copying gcr.io/staging/foo@"+digest+" to gcr.io/mirror/foo:v1.0")
+	require.ErrorContains(t, err, "denied")
+	require.Len(t, provider.CallsTo("CopyImage"), 2)
+	require.Contains(t, provider.CopiedImages, reg.CopyRecord{
+		Src: "gcr.io/staging/foo@" + digest, Dst: "gcr.io/prod/foo:v1.0",
+	})
+
+	provider.Latency = 0
+
+	// Only the first copy fails on the next run
+	provider.CopyImageErrs = nil
+	provider.CopyImageErrOnCall = map[int]error{len(provider.CopiedImages): errors.New("denied")}
+	require.ErrorContains(t, di.PromoteImages(context.Background(), opts, edges), "denied")
+	require.NoError(t, di.PromoteImages(context.Background(), opts, edges))
+}
+
+func TestParseManifestsCredentials(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`registries:
+- name: ghcr.io/staging
+  src: true
+- name: ghcr.io/prod
+  credentials:
+    tokenEnv: PROMO_TEST_PARSE_TOKEN
+images:
+- name: foo
+  dmap:
+    "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": ["v1.0"]
+`), 0o600))
+
+	kc := reg.NewKeychain(authn.DefaultKeychain)
+
+	di := &DefaultPromoterImplementation{}
+	di.SetKeychain(kc)
+
+	opts := &options.Options{Manifest: manifest}
+
+	// Registry credentials are read when the manifests are parsed
+	_, err := di.ParseManifests(opts)
+	require.ErrorContains(t, err, "credentials of registry ghcr.io/prod")
+
+	t.Setenv("PROMO_TEST_PARSE_TOKEN", "token")
+
+	mfests, err := di.ParseManifests(opts)
+	require.NoError(t, err)
+	require.Len(t, mfests, 1)
+
+	repo, err := name.NewRepository("ghcr.io/prod/foo")
+	require.NoError(t, err)
+
+	auth, err := kc.Resolve(repo)
+	require.NoError(t, err)
+	require.Equal(t, &authn.Bearer{Token: "token"}, auth)
+}
+
+func TestParseManifestsTransports(t *testing.T) {
+	t.Parallel()
+
+	manifest := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`registries:
+- name: localhost:5000/staging
+  src: true
+  transport:
+    plainHTTP: true
+- name: registry.example.com/prod
+images:
+- name: foo
+  dmap:
+    "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": ["v1.0"]
+`), 0o600))
+
+	transports := reg.NewTransports(nil)
+
+	di := &DefaultPromoterImplementation{}
+	di.SetTransports(transports)
+
+	// The flags and the manifest agree on the plain HTTP registry
+	opts := &options.Options{
+		Manifest:            manifest,
+		PlainHTTPRegistries: []string{"localhost:5000"},
+	}
+
+	_, err := di.ParseManifests(opts)
+	require.NoError(t, err)
+	require.True(t, transports.Insecure())
+
+	// Registries can't be both insecure and plain HTTP
+	opts.InsecureRegistries = []string{"localhost:5000"}
+
+	_, err = di.ParseManifests(opts)
+	require.ErrorContains(t, err, "conflicting transport settings for host localhost:5000")
+}
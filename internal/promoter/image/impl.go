@@ -18,9 +18,10 @@ package imagepromoter
 
 import (
 	"fmt"
+	"os"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/gcrane"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sirupsen/logrus"
@@ -31,6 +32,7 @@ import (
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/vuln"
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
@@ -42,15 +44,32 @@ example, even though a fixed version of the binary is available, it doesn't
 necessarily mean that a new version of the image layer is available.`
 
 type DefaultPromoterImplementation struct {
-	signer *sign.Signer
+	signer ImageSigner
+
+	// newSigner creates the signer of the promoted images once their
+	// signing options are known. Defaults to sign.New.
+	newSigner func(*sign.Options) ImageSigner
+
+	// newVerifier creates the verifiers used to check staging signatures
+	// against the trusted signers of each manifest and the signatures of
+	// the mirrors in sigcheck. Defaults to sign.New.
+	newVerifier func(*sign.Options) ImageVerifier
 
 	// attSigner signs provenance attestations into sigstore bundles
 	// during the attest phase.
-	attSigner statementSigner
+	attSigner StatementSigner
 
 	// transport is the rate-limited HTTP transport shared by all phases.
 	transport *ratelimit.RoundTripper
 
+	// keychain authenticates the registry operations with the credentials
+	// of the registries declared in the manifests.
+	keychain *registry.Keychain
+
+	// transports is the base of the shared transport, applying the
+	// transport settings of the registries declared in the manifests.
+	transports *registry.Transports
+
 	// puller reuses HTTP auth/transport state across pull operations.
 	puller *remote.Puller
 
@@ -81,6 +100,22 @@ func (di *DefaultPromoterImplementation) SetTransport(rt *ratelimit.RoundTripper
 	di.initRemote()
 }
 
+// SetKeychain sets the keychain registering the credentials of the
+// registries of parsed manifests, and reinitializes the shared
+// puller/pusher to authenticate with it.
+func (di *DefaultPromoterImplementation) SetKeychain(kc *registry.Keychain) {
+	di.keychain = kc
+	if di.transport != nil {
+		di.initRemote()
+	}
+}
+
+// SetTransports sets the base of the shared transport registering the
+// transport settings of the registries of parsed manifests.
+func (di *DefaultPromoterImplementation) SetTransports(t *registry.Transports) {
+	di.transports = t
+}
+
 // SetRegistryProvider sets the registry provider for image operations.
 func (di *DefaultPromoterImplementation) SetRegistryProvider(p registry.Provider) {
 	di.registryProvider = p
@@ -96,6 +131,24 @@ func (di *DefaultPromoterImplementation) SetVulnScanner(s vuln.Scanner) {
 	di.vulnScanner = s
 }
 
+// SetSignerFactory sets the function creating the signers of the promoted
+// images in place of sigstore.
+func (di *DefaultPromoterImplementation) SetSignerFactory(f func(*sign.Options) ImageSigner) {
+	di.newSigner = f
+}
+
+// SetVerifierFactory sets the function creating the verifiers of image
+// signatures in place of sigstore.
+func (di *DefaultPromoterImplementation) SetVerifierFactory(f func(*sign.Options) ImageVerifier) {
+	di.newVerifier = f
+}
+
+// SetStatementSigner sets the signer of the provenance attestations in
+// place of sigstore keyless signing.
+func (di *DefaultPromoterImplementation) SetStatementSigner(s StatementSigner) {
+	di.attSigner = s
+}
+
 // defaultSignerOptions returns a new *sign.Options with default values applied.
 func defaultSignerOptions(opts *options.Options) *sign.Options {
 	signOpts := sign.Default()
@@ -149,6 +202,16 @@ func (di *DefaultPromoterImplementation) getTransport() *ratelimit.RoundTripper
 	return di.transport
 }
 
+// getKeychain returns the keychain of the registry operations, the
+// gcrane keychain unless one was set.
+func (di *DefaultPromoterImplementation) getKeychain() authn.Keychain {
+	if di.keychain == nil {
+		return gcrane.Keychain
+	}
+
+	return di.keychain
+}
+
 // initRemote creates a shared puller and pusher that reuse HTTP
 // auth/transport state across OCI operations.
 func (di *DefaultPromoterImplementation) initRemote() {
@@ -172,7 +235,7 @@ func (di *DefaultPromoterImplementation) initRemote() {
 // puller/pusher state when available.
 func (di *DefaultPromoterImplementation) remoteOptions() []remote.Option {
 	opts := []remote.Option{
-		remote.WithAuthFromKeychain(gcrane.Keychain),
+		remote.WithAuthFromKeychain(di.getKeychain()),
 		remote.WithUserAgent(image.UserAgent),
 	}
 
@@ -191,34 +254,37 @@ func (di *DefaultPromoterImplementation) remoteOptions() []remote.Option {
 	return opts
 }
 
-// craneOptions returns common crane options for registry operations,
-// including authentication, rate-limited transport, and reuse of
-// puller/pusher state when available.
-func (di *DefaultPromoterImplementation) craneOptions() []crane.Option {
-	opts := []crane.Option{
-		crane.WithAuthFromKeychain(gcrane.Keychain),
-		crane.WithUserAgent(image.UserAgent),
+// writeReport writes r to stdout, or to outputFile when set, in format,
+// which defaults to JSON.
+func writeReport(outputFile, description string, r any, format string) error {
+	if format == "" {
+		format = report.FormatJSON
 	}
 
-	if di.transport != nil {
-		opts = append(opts, crane.WithTransport(di.transport))
+	if outputFile == "" {
+		if err := report.Write(os.Stdout, r, format); err != nil {
+			return fmt.Errorf("writing %s: %w", description, err)
+		}
+
+		return nil
 	}
 
-	if di.puller != nil || di.pusher != nil {
-		var remoteOpts []remote.Option
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", description, err)
+	}
 
-		if di.puller != nil {
-			remoteOpts = append(remoteOpts, remote.Reuse(di.puller))
-		}
+	if err := report.Write(f, r, format); err != nil {
+		f.Close()
 
-		if di.pusher != nil {
-			remoteOpts = append(remoteOpts, remote.Reuse(di.pusher))
-		}
+		return fmt.Errorf("writing %s: %w", description, err)
+	}
 
-		opts = append(opts, func(o *crane.Options) {
-			o.Remote = append(o.Remote, remoteOpts...)
-		})
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", description, err)
 	}
 
-	return opts
+	logrus.Infof("Wrote %s to %s", description, outputFile)
+
+	return nil
 }
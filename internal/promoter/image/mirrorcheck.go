@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/mirrorcheck"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// mirrorGroup is a set of destination registries serving the same images.
+type mirrorGroup struct {
+	mirrors []image.Registry
+	names   []image.Name
+}
+
+// GetMirrorStatus reads the images of the manifests from all their
+// destination registries and cross-compares the mirrors of each manifest.
+func (di *DefaultPromoterImplementation) GetMirrorStatus(
+	ctx context.Context, opts *options.Options, mfests []schema.Manifest,
+) (*mirrorcheck.Report, error) {
+	report := mirrorcheck.NewReport()
+
+	// Manifests promoting to the same mirrors are checked together
+	groups := map[string]*mirrorGroup{}
+
+	for _, mfest := range mfests {
+		mirrors := []image.Registry{}
+
+		for _, rc := range mfest.Registries {
+			if !rc.Src && !slices.Contains(mirrors, rc.Name) {
+				mirrors = append(mirrors, rc.Name)
+			}
+		}
+
+		if len(mirrors) < 2 {
+			continue
+		}
+
+		slices.Sort(mirrors)
+
+		key := fmt.Sprint(mirrors)
+
+		group, ok := groups[key]
+		if !ok {
+			group = &mirrorGroup{mirrors: mirrors}
+			groups[key] = group
+		}
+
+		for _, img := range mfest.Images {
+			if !slices.Contains(group.names, img.Name) {
+				group.names = append(group.names, img.Name)
+			}
+		}
+	}
+
+	if len(groups) == 0 {
+		logrus.Info("No manifest promotes to more than one destination registry")
+
+		return report, nil
+	}
+
+	configs := []registry.RegistryConfig{}
+	baseConfigs := []registry.RegistryConfig{}
+
+	for _, group := range groups {
+		for _, mirror := range group.mirrors {
+			if !slices.ContainsFunc(baseConfigs, func(rc registry.RegistryConfig) bool { return rc.Name == mirror }) {
+				baseConfigs = append(baseConfigs, registry.RegistryConfig{Name: mirror})
+			}
+
+			for _, name := range group.names {
+				configs = append(configs, registry.RegistryConfig{
+					Name: image.Registry(path.Join(string(mirror), string(name))),
+				})
+			}
+		}
+	}
+
+	// Key every image by the longest mirror containing it
+	sort.Slice(baseConfigs, func(i, j int) bool {
+		if len(baseConfigs[i].Name) != len(baseConfigs[j].Name) {
+			return len(baseConfigs[i].Name) > len(baseConfigs[j].Name)
+		}
+
+		return baseConfigs[i].Name < baseConfigs[j].Name
+	})
+
+	inv, err := di.registryProvider.ReadRegistries(ctx, configs, false, baseConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("reading mirrors: %w", err)
+	}
+
+	for _, group := range groups {
+		report.Check(group.mirrors, group.names, inv.Images)
+	}
+
+	logrus.Infof(
+		"Checked %d mirrors: %d missing digests, %d missing tags, %d tag conflicts",
+		len(report.Mirrors),
+		report.Count(mirrorcheck.KindMissingDigest),
+		report.Count(mirrorcheck.KindMissingTag),
+		report.Count(mirrorcheck.KindTagConflict),
+	)
+
+	return report, nil
+}
+
+// FixMirrors copies the missing digests and tags of the report from the
+// mirrors having them, and records the outcome in the findings. Missing
+// digests are copied to each of their tags, or by digest when untagged.
+// Failed copies are logged and left unfixed in the report.
+func (di *DefaultPromoterImplementation) FixMirrors(
+	ctx context.Context, opts *options.Options, report *mirrorcheck.Report,
+) error {
+	g := new(errgroup.Group)
+	g.SetLimit(concurrencyLimit(opts.Threads))
+
+	for i := range report.Findings {
+		finding := &report.Findings[i]
+		if !finding.Fixable() {
+			continue
+		}
+
+		g.Go(func() error {
+			src := path.Join(string(finding.Source), string(finding.Image)) + "@" + string(finding.Digest)
+			dstRepo := path.Join(string(finding.Mirror), string(finding.Image))
+
+			dsts := []string{}
+
+			switch {
+			case finding.Kind == mirrorcheck.KindMissingTag:
+				dsts = append(dsts, dstRepo+":"+string(finding.Tag))
+			case len(finding.Tags) == 0:
+				dsts = append(dsts, dstRepo+"@"+string(finding.Digest))
+			default:
+				for _, tag := range finding.Tags {
+					dsts = append(dsts, dstRepo+":"+string(tag))
+				}
+			}
+
+			for _, dst := range dsts {
+				logrus.Infof("Copying %s to %s", src, dst)
+
+				if err := di.registryProvider.CopyImage(ctx, src, dst); err != nil {
+					logrus.Errorf("Copying %s to %s: %v", src, dst, err)
+
+					finding.Error = err.Error()
+
+					return nil
+				}
+			}
+
+			finding.Fixed = true
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("fixing mirrors: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMirrorReport writes the mirror check report to stdout or
+// opts.OutputFile in the format configured in the options.
+func (di *DefaultPromoterImplementation) WriteMirrorReport(opts *options.Options, report *mirrorcheck.Report) error {
+	return writeReport(opts.OutputFile, "mirror check report", report, opts.MirrorCheckOutput)
+}
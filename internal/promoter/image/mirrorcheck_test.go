@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/mirrorcheck"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+)
+
+func TestCheckAndFixMirrors(t *testing.T) {
+	const (
+		digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+
+	provider := registry.NewFakeProvider()
+	provider.AddImage("us.gcr.io/prod", "foo", digestA, "v1.0")
+	provider.AddImage("us.gcr.io/prod", "foo", digestB)
+	provider.AddImage("eu.gcr.io/prod", "foo", digestA)
+	provider.AddImage("eu.gcr.io/prod", "foo", digestB)
+	// Only promoted to a single mirror, so never compared
+	provider.AddImage("gcr.io/single", "bar", digestA, "v1.0")
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	mfests := []schema.Manifest{
+		{
+			Registries: []registry.Context{
+				{Name: "gcr.io/staging", Src: true},
+				{Name: "us.gcr.io/prod"},
+				{Name: "eu.gcr.io/prod"},
+			},
+			Images: []registry.Image{{Name: "foo", Dmap: registry.DigestTags{digestA: {"v1.0"}}}},
+		},
+		{
+			Registries: []registry.Context{
+				{Name: "gcr.io/staging", Src: true},
+				{Name: "gcr.io/single"},
+			},
+			Images: []registry.Image{{Name: "bar", Dmap: registry.DigestTags{digestA: {"v1.0"}}}},
+		},
+		{
+			Registries: []registry.Context{
+				{Name: "gcr.io/staging", Src: true},
+				{Name: "eu.gcr.io/prod"},
+				{Name: "us.gcr.io/prod"},
+			},
+			Images: []registry.Image{{Name: "baz", Dmap: registry.DigestTags{digestB: {"v1.0"}}}},
+		},
+	}
+
+	opts := &options.Options{}
+
+	report, err := di.GetMirrorStatus(context.Background(), opts, mfests)
+	require.NoError(t, err)
+	require.Len(t, report.Mirrors, 2)
+
+	// baz is checked with foo, but is missing from both mirrors
+	require.Equal(t, []mirrorcheck.Finding{{
+		Kind:   mirrorcheck.KindMissingTag,
+		Mirror: "eu.gcr.io/prod",
+		Image:  "foo",
+		Digest: digestA,
+		Tag:    "v1.0",
+		Source: "us.gcr.io/prod",
+	}}, report.Findings)
+
+	require.NoError(t, di.FixMirrors(context.Background(), opts, report))
+	require.Equal(t, []registry.CopyRecord{{
+		Src: "us.gcr.io/prod/foo@" + digestA,
+		Dst: "eu.gcr.io/prod/foo:v1.0",
+	}}, provider.CopiedImages)
+	require.True(t, report.Findings[0].Fixed)
+	require.NoError(t, report.Err())
+
+	// Failed copies are recorded and left unfixed
+	report.Findings[0].Fixed = false
+	provider.CopyImageErr = errors.New("denied")
+
+	require.NoError(t, di.FixMirrors(context.Background(), opts, report))
+	require.False(t, report.Findings[0].Fixed)
+	require.Contains(t, report.Findings[0].Error, "denied")
+	require.Error(t, report.Err())
+}
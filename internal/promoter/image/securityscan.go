@@ -19,8 +19,14 @@ package imagepromoter
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
@@ -28,7 +34,8 @@ import (
 )
 
 // ScanEdges runs vulnerability scans on the new images detected by the
-// promoter using the configured vuln.Scanner.
+// promoter using the configured vuln.Scanner, and returns an error listing
+// the images that failed the check.
 func (di *DefaultPromoterImplementation) ScanEdges(
 	ctx context.Context,
 	opts *options.Options,
@@ -40,7 +47,75 @@ func (di *DefaultPromoterImplementation) ScanEdges(
 		return nil
 	}
 
-	threshold := vuln.Severity(opts.SeverityThreshold)
+	report, err := di.ScanPromotionEdges(ctx, opts, promotionEdges)
+	if err != nil {
+		return err
+	}
+
+	return report.Err()
+}
+
+// ScanPromotionEdges scans the source images of the edges against the
+// severity threshold. Every image is scanned once, even when promoted to
+// several destinations, and the scans run concurrently. The report is
+// written to the configured JSON and SARIF files.
+func (di *DefaultPromoterImplementation) ScanPromotionEdges(
+	ctx context.Context,
+	opts *options.Options,
+	promotionEdges map[promotion.Edge]any,
+) (*vuln.ScanReport, error) {
+	report, err := di.scanImages(ctx, opts, promotionEdges)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeScanReports(opts, report); err != nil {
+		return nil, err
+	}
+
+	failed := report.Failed()
+
+	logrus.Infof(
+		"%d of %d images passed the vulnerability check",
+		len(report.Images)-len(failed), len(report.Images),
+	)
+
+	for i := range failed {
+		img := &failed[i]
+
+		if img.Error != "" {
+			logrus.WithField("image", img.Reference).Errorf("Scanning failed: %s", img.Error)
+
+			continue
+		}
+
+		logrus.WithField("image", img.Reference).Errorf(
+			"Vulnerabilities at or above threshold %s (highest: %s)",
+			report.Threshold, img.HighestSeverity,
+		)
+	}
+
+	return report, nil
+}
+
+// scanImages scans the source image of every digest in the edges and
+// applies the vulnerability exceptions to the results.
+func (di *DefaultPromoterImplementation) scanImages(
+	ctx context.Context,
+	opts *options.Options,
+	promotionEdges map[promotion.Edge]any,
+) (*vuln.ScanReport, error) {
+	exceptions, err := vuln.LoadExceptions(opts.VulnExceptions...)
+	if err != nil {
+		return nil, fmt.Errorf("loading vulnerability exceptions: %w", err)
+	}
+
+	type scanTarget struct {
+		ref          string
+		destinations []string
+	}
+
+	images := map[string]*scanTarget{}
 
 	for edge := range promotionEdges {
 		ref := edge.SrcReference()
@@ -48,18 +123,116 @@ func (di *DefaultPromoterImplementation) ScanEdges(
 			continue
 		}
 
-		result, err := di.vulnScanner.Scan(ctx, ref)
+		img, ok := images[string(edge.Digest)]
+		if !ok {
+			img = &scanTarget{ref: ref}
+			images[string(edge.Digest)] = img
+		}
+
+		// Scan the same source reference on every run
+		if ref < img.ref {
+			img.ref = ref
+		}
+
+		if dst := edge.DstReference(); dst != "" && !slices.Contains(img.destinations, dst) {
+			img.destinations = append(img.destinations, dst)
+		}
+	}
+
+	report := vuln.NewScanReport(vuln.Severity(opts.SeverityThreshold))
+	now := time.Now()
+
+	var mu sync.Mutex
+
+	g := new(errgroup.Group)
+	g.SetLimit(concurrencyLimit(opts.Threads))
+
+	for digest, img := range images {
+		slices.Sort(img.destinations)
+
+		g.Go(func() error {
+			result, err := di.vulnScanner.Scan(ctx, img.ref)
+			if err != nil {
+				err = fmt.Errorf("scanning %s: %w", img.ref, err)
+			} else {
+				var expired []vuln.AppliedException
+
+				result, expired = exceptions.Apply(result, now)
+				logExceptions(result, expired)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Add(img.ref, digest, img.destinations, result, err)
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, fmt.Errorf("scanning images: %w", err)
+	}
+
+	return report, nil
+}
+
+// writeScanReports writes the scan report to the configured files.
+func writeScanReports(opts *options.Options, report *vuln.ScanReport) error {
+	for _, out := range []struct {
+		path  string
+		write func(io.Writer) error
+	}{
+		{opts.VulnScanReportJSON, report.WriteJSON},
+		{opts.VulnScanReportSARIF, report.WriteSARIF},
+	} {
+		if out.path == "" {
+			continue
+		}
+
+		f, err := os.Create(out.path)
 		if err != nil {
-			return fmt.Errorf("scanning %s: %w", ref, err)
+			return fmt.Errorf("creating scan report: %w", err)
+		}
+
+		if err := out.write(f); err != nil {
+			f.Close()
+
+			return err
 		}
 
-		if result.ExceedsSeverity(threshold) {
-			return fmt.Errorf(
-				"image %s has vulnerabilities at or above threshold %d (highest: %d)",
-				ref, threshold, result.HighestSeverity,
-			)
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("closing scan report: %w", err)
 		}
+
+		logrus.Infof("Wrote vulnerability scan report to %s", out.path)
 	}
 
 	return nil
 }
+
+// logExceptions reports the exceptions applied to a scan result and the
+// expired ones that no longer apply.
+func logExceptions(result *vuln.ScanResult, expired []vuln.AppliedException) {
+	for _, e := range result.Exceptions {
+		logrus.WithField("image", result.Reference).Infof(
+			"Ignoring %s (%s) until %s: %s",
+			e.Vulnerability.ID, e.Exception.Source, expiryString(&e.Exception), e.Exception.Justification,
+		)
+	}
+
+	for _, e := range expired {
+		logrus.WithField("image", result.Reference).Warnf(
+			"Exception for %s (%s) expired on %s",
+			e.Vulnerability.ID, e.Exception.Source, e.Exception.Expires,
+		)
+	}
+}
+
+func expiryString(e *vuln.Exception) string {
+	if e.Expires == "" {
+		return "revoked"
+	}
+
+	return e.Expires
+}
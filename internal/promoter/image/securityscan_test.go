@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/vuln"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/vuln/vulnfakes"
+)
+
+func TestScanEdgesExceptions(t *testing.T) {
+	const digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	edges := map[promotion.Edge]any{testEdgeForHost("gcr.io", digest): nil}
+
+	scanner := &vulnfakes.FakeScanner{}
+	scanner.ScanReturns(&vuln.ScanResult{
+		Reference:       "gcr.io/staging/myimage@" + digest,
+		Vulnerabilities: []vuln.Vulnerability{{ID: "CVE-2024-0001", Severity: vuln.SeverityCritical}},
+		HighestSeverity: vuln.SeverityCritical,
+	}, nil)
+
+	di := &DefaultPromoterImplementation{}
+	di.SetVulnScanner(scanner)
+
+	opts := &options.Options{SeverityThreshold: int(vuln.SeverityHigh)}
+	require.Error(t, di.ScanEdges(context.Background(), opts, edges))
+
+	dir := t.TempDir()
+	exceptions := filepath.Join(dir, "exceptions.yaml")
+	require.NoError(t, os.WriteFile(exceptions, []byte(`exceptions:
+- id: CVE-2024-0001
+  images: [staging/myimage]
+  justification: not reachable
+  expires: "2999-01-01"
+`), 0o600))
+
+	opts.VulnExceptions = []string{exceptions}
+	require.NoError(t, di.ScanEdges(context.Background(), opts, edges))
+
+	// Expired exceptions are ignored
+	require.NoError(t, os.WriteFile(exceptions, []byte(`exceptions:
+- id: CVE-2024-0001
+  images: [staging/myimage]
+  justification: not reachable
+  expires: "2020-01-01"
+`), 0o600))
+	require.Error(t, di.ScanEdges(context.Background(), opts, edges))
+
+	opts.VulnExceptions = []string{filepath.Join(dir, "missing.yaml")}
+	require.Error(t, di.ScanEdges(context.Background(), opts, edges))
+}
+
+func TestScanEdgesReport(t *testing.T) {
+	const (
+		cleanDigest      = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		vulnerableDigest = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+
+	mirror := testEdgeForHost("gcr.io", cleanDigest)
+	mirror.DstRegistry.Name = "us.gcr.io/production"
+
+	edges := map[promotion.Edge]any{
+		testEdgeForHost("gcr.io", cleanDigest): nil,
+		mirror:                                 nil,
+		testEdgeForHost("gcr.io", vulnerableDigest): nil,
+	}
+
+	scanner := &vulnfakes.FakeScanner{}
+	scanner.ScanCalls(func(_ context.Context, ref string) (*vuln.ScanResult, error) {
+		if strings.HasSuffix(ref, cleanDigest) {
+			return &vuln.ScanResult{Reference: ref}, nil
+		}
+
+		return &vuln.ScanResult{
+			Reference: ref,
+			Vulnerabilities: []vuln.Vulnerability{{
+				ID: "CVE-2024-0001", Severity: vuln.SeverityCritical, FixedVersion: "1.2.3",
+			}},
+			HighestSeverity: vuln.SeverityCritical,
+		}, nil
+	})
+
+	di := &DefaultPromoterImplementation{}
+	di.SetVulnScanner(scanner)
+
+	dir := t.TempDir()
+	opts := &options.Options{
+		SeverityThreshold:   int(vuln.SeverityHigh),
+		Threads:             2,
+		VulnScanReportJSON:  filepath.Join(dir, "report.json"),
+		VulnScanReportSARIF: filepath.Join(dir, "report.sarif"),
+	}
+
+	err := di.ScanEdges(context.Background(), opts, edges)
+	require.ErrorContains(t, err, "1 of 2 images failed")
+	require.ErrorContains(t, err, vulnerableDigest)
+
+	// Each digest is scanned once
+	require.Equal(t, 2, scanner.ScanCallCount())
+
+	data, err := os.ReadFile(opts.VulnScanReportJSON)
+	require.NoError(t, err)
+
+	report := &vuln.ScanReport{}
+	require.NoError(t, json.Unmarshal(data, report))
+	require.False(t, report.Passed)
+	require.Len(t, report.Images, 2)
+
+	byDigest := map[string]vuln.ImageScan{}
+	for _, img := range report.Images {
+		byDigest[img.Digest] = img
+	}
+
+	require.True(t, byDigest[cleanDigest].Passed)
+	require.Len(t, byDigest[cleanDigest].Destinations, 2)
+	require.False(t, byDigest[vulnerableDigest].Passed)
+	require.Equal(t, "1.2.3", byDigest[vulnerableDigest].Vulnerabilities[0].FixedVersion)
+
+	data, err = os.ReadFile(opts.VulnScanReportSARIF)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"ruleId": "CVE-2024-0001"`)
+
+	// Scan errors fail the check without stopping the other scans
+	scanner.ScanReturns(nil, errors.New("scanner unavailable"))
+	scanner.ScanCalls(nil)
+
+	err = di.ScanEdges(context.Background(), opts, edges)
+	require.ErrorContains(t, err, "2 of 2 images failed")
+	require.Equal(t, 4, scanner.ScanCallCount())
+}
@@ -21,14 +21,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/remote"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/sigstore/cosign/v2/pkg/cosign/env"
 	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
@@ -39,11 +41,12 @@ import (
 	"sigs.k8s.io/release-sdk/sign"
 	"sigs.k8s.io/release-utils/version"
 
-	"sigs.k8s.io/promo-tools/v4/image/consts"
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/provenance"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
@@ -62,52 +65,226 @@ const (
 	bundlePredicateTypeAnnotation = "dev.sigstore.bundle.predicateType"
 )
 
+// ImageVerifier verifies the signatures of an image reference. It is
+// implemented by *sign.Signer and returns a nil object for unsigned images.
+type ImageVerifier interface {
+	VerifyImage(reference string) (*sign.SignedObject, error)
+}
+
+// ImageSigner signs an image reference and stores its signature in the
+// registry. It is implemented by *sign.Signer.
+type ImageSigner interface {
+	SignImageWithOptions(options *sign.Options, reference string) (*sign.SignedObject, error)
+}
+
+// stagingImage identifies an image in a source registry, used to look up
+// the signature policy of the manifest that declares it.
+type stagingImage struct {
+	registry image.Registry
+	name     image.Name
+	digest   image.Digest
+}
+
 // ValidateStagingSignatures checks if edges (images) have a signature
-// applied during its staging run. If they do it verifies them and
-// returns an error if they are not valid.
+// applied during its staging run. Each image is verified against the
+// trusted signers declared in the manifest it comes from, falling back
+// to the globally configured identity when the manifest declares none.
+// An error is returned if any image carries a signature that does not
+// verify, or if it is unsigned and its manifest requires signatures.
 func (di *DefaultPromoterImplementation) ValidateStagingSignatures(
-	edges map[promotion.Edge]any,
-) (map[promotion.Edge]any, error) {
-	refsToEdges := map[string]promotion.Edge{}
+	opts *options.Options, mfests []schema.Manifest, edges map[promotion.Edge]any,
+) error {
+	policies := stagingSignaturePolicies(mfests)
+
+	// Group the source references by the policy that applies to them so
+	// that the verifiers of each policy are only created once.
+	type policyRefs struct {
+		verifiers []ImageVerifier
+		required  bool
+		refs      map[string]struct{}
+	}
+
+	groups := map[*schema.SignaturePolicy]*policyRefs{}
 
 	for edge := range edges {
 		ref := edge.SrcReference()
-		refsToEdges[ref] = edge
+		if ref == "" {
+			continue
+		}
+
+		policy := policies[stagingImage{
+			registry: edge.SrcRegistry.Name,
+			name:     edge.SrcImageTag.Name,
+			digest:   edge.Digest,
+		}]
+
+		group, ok := groups[policy]
+		if !ok {
+			group = &policyRefs{
+				required: policy.IsRequired(),
+				refs:     map[string]struct{}{},
+			}
+
+			for _, signer := range trustedSigners(opts, policy) {
+				group.verifiers = append(group.verifiers, di.verifierFor(signerOptions(opts, signer)))
+			}
+
+			groups[policy] = group
+		}
+
+		group.refs[ref] = struct{}{}
 	}
 
-	refs := make([]string, 0, len(refsToEdges))
-	for ref := range refsToEdges {
-		refs = append(refs, ref)
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	g := new(errgroup.Group)
+	g.SetLimit(concurrencyLimit(opts.MaxSignatureOps))
+
+	for _, group := range groups {
+		for ref := range group.refs {
+			g.Go(func() error {
+				signed, err := verifyStagingReference(group.verifiers, ref)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch {
+				case err != nil:
+					errs = append(errs, fmt.Errorf("staging image %s is not signed by a trusted identity: %w", ref, err))
+				case signed:
+					logrus.Debugf("Staging image %s is signed by a trusted identity", ref)
+				case group.required:
+					errs = append(errs, fmt.Errorf("staging image %s is not signed, but its manifest requires signatures", ref))
+				default:
+					logrus.Debugf("Staging image %s is not signed", ref)
+				}
+
+				return nil
+			})
+		}
 	}
 
-	res, err := di.signer.VerifyImages(refs...)
-	if err != nil {
-		return nil, fmt.Errorf("verify images: %w", err)
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("verifying staging signatures: %w", err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("verify images: %w", errors.Join(errs...))
 	}
 
-	signedEdges := map[promotion.Edge]any{}
+	return nil
+}
 
-	res.Range(func(key, _ any) bool {
-		ref, ok := key.(string)
-		if !ok {
-			logrus.Errorf("Interface conversion failed: key is not a string: %v", key)
+// verifyStagingReference checks the signatures of ref against a list of
+// verifiers, one per trusted signer. It returns true as soon as one of
+// them accepts the signature, and false without error if the image is
+// not signed at all.
+func verifyStagingReference(verifiers []ImageVerifier, ref string) (bool, error) {
+	errs := make([]error, 0, len(verifiers))
+
+	for _, v := range verifiers {
+		obj, err := v.VerifyImage(ref)
+		if err != nil {
+			errs = append(errs, err)
 
-			return false
+			continue
 		}
 
-		edge, ok := refsToEdges[ref]
-		if !ok {
-			logrus.Errorf("Reference %s is not in edge map", ref)
+		// Whether an image is signed does not depend on the identity,
+		// so no other verifier will find a signature either.
+		if obj == nil {
+			return false, nil
+		}
 
-			return true
+		return true, nil
+	}
+
+	return false, errors.Join(errs...)
+}
+
+// stagingSignaturePolicies indexes the signature policy of each manifest
+// by the staging images it declares. If an image appears in more than one
+// manifest, a policy requiring signatures takes precedence.
+func stagingSignaturePolicies(mfests []schema.Manifest) map[stagingImage]*schema.SignaturePolicy {
+	policies := map[stagingImage]*schema.SignaturePolicy{}
+
+	for i := range mfests {
+		mfest := &mfests[i]
+		if mfest.StagingSignatures == nil || mfest.SrcRegistry == nil {
+			continue
+		}
+
+		for _, img := range mfest.Images {
+			for digest := range img.Dmap {
+				key := stagingImage{registry: mfest.SrcRegistry.Name, name: img.Name, digest: digest}
+				if existing, ok := policies[key]; ok && existing.IsRequired() {
+					continue
+				}
+
+				policies[key] = mfest.StagingSignatures
+			}
 		}
+	}
+
+	return policies
+}
+
+// trustedSigners returns the signers accepted by a policy. When the policy
+// does not list any, the identity from the promoter options is trusted.
+func trustedSigners(opts *options.Options, policy *schema.SignaturePolicy) []schema.Signer {
+	if policy != nil && len(policy.Signers) > 0 {
+		return policy.Signers
+	}
+
+	return []schema.Signer{{
+		Identity:       opts.SignCheckIdentity,
+		IdentityRegexp: opts.SignCheckIdentityRegexp,
+		Issuer:         opts.SignCheckIssuer,
+		IssuerRegexp:   opts.SignCheckIssuerRegexp,
+	}}
+}
+
+// signerOptions returns the default signer options set up to verify
+// signatures from the given signer.
+func signerOptions(opts *options.Options, signer schema.Signer) *sign.Options {
+	signOpts := defaultSignerOptions(opts)
+	signOpts.CertIdentity = signer.Identity
+	signOpts.CertIdentityRegexp = signer.IdentityRegexp
+	signOpts.CertOidcIssuer = signer.Issuer
+	signOpts.CertOidcIssuerRegexp = signer.IssuerRegexp
+
+	return signOpts
+}
+
+// verifierFor returns an image verifier for the given signer options.
+func (di *DefaultPromoterImplementation) verifierFor(signOpts *sign.Options) ImageVerifier {
+	if di.newVerifier != nil {
+		return di.newVerifier(signOpts)
+	}
+
+	return sign.New(signOpts)
+}
 
-		signedEdges[edge] = nil
+// signerFor returns an image signer for the given signer options.
+func (di *DefaultPromoterImplementation) signerFor(signOpts *sign.Options) ImageSigner {
+	if di.newSigner != nil {
+		return di.newSigner(signOpts)
+	}
 
-		return true
-	})
+	return sign.New(signOpts)
+}
 
-	return signedEdges, nil
+// concurrencyLimit turns a configured number of concurrent operations into
+// an errgroup limit, treating unset values as unlimited.
+func concurrencyLimit(n int) int {
+	if n <= 0 {
+		return -1
+	}
+
+	return n
 }
 
 // SignImages signs the promoted images and stores their signatures in
@@ -142,17 +319,17 @@ func (di *DefaultPromoterImplementation) SignImages(
 	// because that's the only way to propagate the identity token to the
 	// internal Signer structs. Without that, the identity token wouldn't be
 	// used at all and images would be signed with a wrong identity.
-	di.signer = sign.New(signOpts)
+	di.signer = di.signerFor(signOpts)
 
 	// We only sign the first normalized image per digest of each edge.
-	grouped := groupEdgesByIdentityDigest(edges)
+	grouped := groupEdgesByIdentityDigest(opts, edges)
 
 	g := new(errgroup.Group)
 	g.SetLimit(opts.MaxSignatureOps)
 
 	for _, group := range grouped {
 		g.Go(func() error {
-			return di.signFirst(signOpts, targetIdentity(&group[0]), &group[0])
+			return di.signFirst(signOpts, targetIdentity(opts.IdentityRules(), &group[0]), &group[0])
 		})
 	}
 
@@ -163,6 +340,82 @@ func (di *DefaultPromoterImplementation) SignImages(
 	return nil
 }
 
+// GetUnsignedEdges returns the promotion edges of the manifests whose images
+// are already in their destination but were never signed there, for example
+// because an earlier promotion failed before signing them.
+func (di *DefaultPromoterImplementation) GetUnsignedEdges(
+	ctx context.Context, opts *options.Options, mfests []schema.Manifest,
+) (map[promotion.Edge]any, error) {
+	edges, err := promotion.ToEdges(mfests)
+	if err != nil {
+		return nil, fmt.Errorf("converting manifests to edges: %w", err)
+	}
+
+	// Only the destination repositories have to be read
+	repos := make(map[registry.Context]any)
+	baseRegs := make(map[registry.Context]any)
+
+	for edge := range edges {
+		repo := edge.DstRegistry
+		repo.Name += "/" + image.Registry(edge.DstImageTag.Name)
+		repos[repo] = nil
+		baseRegs[edge.DstRegistry] = nil
+	}
+
+	inv, err := di.registryProvider.ReadRegistries(
+		ctx,
+		registry.RegistryConfigsFromContexts(slices.Collect(maps.Keys(repos))),
+		false,
+		registry.RegistryConfigsFromContexts(slices.Collect(maps.Keys(baseRegs))),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading registries: %w", err)
+	}
+
+	promoted := make(map[promotion.Edge]any)
+
+	for edge := range edges {
+		if _, dp := edge.VertexProps(inv.Images); dp.PqinDigestMatch {
+			promoted[edge] = nil
+		}
+	}
+
+	// SignImages only signs the first image of each group, so that is
+	// where the signature has to be.
+	unsigned := make(map[promotion.Edge]any)
+
+	for _, group := range groupEdgesByIdentityDigest(opts, promoted) {
+		first := &group[0]
+
+		digestTags := inv.Images[first.DstRegistry.Name][first.DstImageTag.Name]
+		if hasSignatureTag(digestTags, first.Digest) {
+			continue
+		}
+
+		logrus.Infof("Image %s was promoted but is not signed", first.DstReference())
+
+		for _, edge := range group {
+			unsigned[edge] = nil
+		}
+	}
+
+	return unsigned, nil
+}
+
+// hasSignatureTag returns true if the signature tag of a digest is found in
+// the tags of a repository.
+func hasSignatureTag(digestTags registry.DigestTags, dg image.Digest) bool {
+	sigTag := image.Tag(digestToSignatureTag(dg))
+
+	for _, tags := range digestTags {
+		if slices.Contains(tags, sigTag) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // signFirst signs the first (primary) image for a given identity+digest group.
 func (di *DefaultPromoterImplementation) signFirst(signOpts *sign.Options, identity string, edge *promotion.Edge) error {
 	imageRef := edge.DstReference()
@@ -174,6 +427,10 @@ func (di *DefaultPromoterImplementation) signFirst(signOpts *sign.Options, ident
 	signOptsCopy.SignContainerIdentity = identity
 	logrus.Infof("Using new production registry reference for %s: %v", imageRef, identity)
 
+	// Cosign talks to the registries with its own transport, so an
+	// insecure registry of the image has to be allowed explicitly.
+	signOptsCopy.AllowInsecure = di.transports.InsecureReference(imageRef)
+
 	// Add an annotation recording the kpromo version to ensure we
 	// get a 2nd signature, otherwise cosign will not resign a signed image:
 	signOptsCopy.Annotations = []string{
@@ -201,37 +458,90 @@ func (di *DefaultPromoterImplementation) signFirst(signOpts *sign.Options, ident
 // an example signature:
 // 'us-west2-docker.pkg.dev/k8s-artifacts-prod/images/kubernetes/conformance-arm64'
 //
-// to match the production registry:
+// to match the production registry, as configured in the identity rules:
 // 'registry.k8s.io/kubernetes/conformance-arm64'.
-func targetIdentity(edge *promotion.Edge) string {
-	identity := string(edge.DstRegistry.Name) + "/" + string(edge.DstImageTag.Name)
+func targetIdentity(rules []options.IdentityRule, edge *promotion.Edge) string {
+	ref := string(edge.DstRegistry.Name) + "/" + string(edge.DstImageTag.Name)
 
-	if !strings.Contains(string(edge.DstRegistry.Name), productionRepositoryPath) {
+	identity := publicIdentity(rules, ref)
+	if identity == ref {
 		logrus.Debugf(
-			"No production registry path %q used in image, not modifying target signature reference",
-			productionRepositoryPath,
+			"No identity rule matches %q, not modifying target signature reference", ref,
 		)
+	}
+
+	return identity
+}
+
+// publicIdentity rewrites a repository (registry and image path) into its
+// public identity using the longest matching identity rule. The repository
+// is returned unchanged when no rule matches.
+func publicIdentity(rules []options.IdentityRule, repo string) string {
+	_, path, _ := strings.Cut(repo, "/")
+
+	best, rest := -1, ""
+
+	for i, rule := range rules {
+		if best >= 0 && len(rule.Prefix) <= len(rules[best].Prefix) {
+			continue
+		}
+
+		// Prefixes without a host match the path on any registry host.
+		for _, candidate := range []string{repo, path} {
+			if after, ok := cutPathPrefix(candidate, rule.Prefix); ok {
+				best, rest = i, after
+
+				break
+			}
+		}
+	}
+
+	if best < 0 {
+		return repo
+	}
+
+	return rules[best].Identity + rest
+}
+
+// mirrorRepository returns the repository in a destination registry (mirror)
+// that is served under the public repository publicRepo. It returns false if
+// the mirror does not serve that repository according to the identity rules.
+func mirrorRepository(rules []options.IdentityRule, mirror, publicRepo string) (string, bool) {
+	rest, ok := cutPathPrefix(publicRepo, publicIdentity(rules, mirror))
+	if !ok {
+		return "", false
+	}
 
-		return identity
+	return mirror + rest, true
+}
+
+// cutPathPrefix returns s without the given path prefix. The prefix must
+// match whole path components.
+func cutPathPrefix(s, prefix string) (string, bool) {
+	if s == prefix {
+		return "", true
 	}
 
-	idx := strings.Index(identity, productionRepositoryPath) + len(productionRepositoryPath)
-	newRef := consts.ProdRegistry + identity[idx:]
+	if after, ok := strings.CutPrefix(s, prefix+"/"); ok {
+		return "/" + after, true
+	}
 
-	return newRef
+	return "", false
 }
 
 // groupEdgesByIdentityDigest groups promotion edges by their target identity
 // and digest. Within each group, edges are sorted so that the canonical
-// registry (canonicalRegistry) comes first, with remaining registries in
-// alphabetical order. The first edge in each group is used as the signing
-// target.
-func groupEdgesByIdentityDigest(edges map[promotion.Edge]any) [][]promotion.Edge {
+// registry (opts.CanonicalRegistry) comes first, with remaining
+// registries in alphabetical order. The first edge in each group is used as
+// the signing target.
+func groupEdgesByIdentityDigest(opts *options.Options, edges map[promotion.Edge]any) [][]promotion.Edge {
 	type key struct {
 		identity string
 		digest   image.Digest
 	}
 
+	rules, canonical := opts.IdentityRules(), opts.CanonicalRegistry()
+
 	grouped := make(map[key][]promotion.Edge, len(edges)/2)
 
 	for edge := range edges {
@@ -242,16 +552,22 @@ func groupEdgesByIdentityDigest(edges map[promotion.Edge]any) [][]promotion.Edge
 			continue
 		}
 
-		k := key{identity: targetIdentity(&edge), digest: edge.Digest}
+		k := key{identity: targetIdentity(rules, &edge), digest: edge.Digest}
 		grouped[k] = append(grouped[k], edge)
 	}
 
+	isCanonical := func(edge *promotion.Edge) bool {
+		_, ok := cutPathPrefix(string(edge.DstRegistry.Name), canonical)
+
+		return ok
+	}
+
 	result := make([][]promotion.Edge, 0, len(grouped))
 	for _, group := range grouped {
 		sort.Slice(group, func(i, j int) bool {
-			iCanonical := strings.HasPrefix(string(group[i].DstRegistry.Name), canonicalRegistry)
+			iCanonical := isCanonical(&group[i])
 
-			jCanonical := strings.HasPrefix(string(group[j].DstRegistry.Name), canonicalRegistry)
+			jCanonical := isCanonical(&group[j])
 			if iCanonical != jCanonical {
 				return iCanonical
 			}
@@ -289,7 +605,7 @@ func (di *DefaultPromoterImplementation) copyAttachedObjects(edge *promotion.Edg
 	logrus.Infof("Signature pre copy: %s to %s", srcRefString, dstRefString)
 
 	if err := ratelimit.WithRetry(func() error {
-		return craneCopyWithTimeout(context.TODO(), srcRef.String(), dstRef.String(), ratelimit.CopyTimeout, di.craneOptions())
+		return di.copyWithTimeout(context.TODO(), srcRef.String(), dstRef.String(), ratelimit.CopyTimeout)
 	}); err != nil {
 		// If the signature layer does not exist it means that the src image
 		// is not signed, so we catch the error and return nil
@@ -356,11 +672,7 @@ func (di *DefaultPromoterImplementation) WriteProvenanceAttestations(
 		return fmt.Errorf("initializing attestation signer: %w", err)
 	}
 
-	builderID := "https://k8s.io/promo-tools"
-	if v := version.GetVersionInfo().GitVersion; v != "" {
-		builderID += "@" + v
-	}
-
+	builderID := promotionBuilderID()
 	now := time.Now()
 
 	g := new(errgroup.Group)
@@ -399,6 +711,17 @@ func (di *DefaultPromoterImplementation) WriteProvenanceAttestations(
 	return nil
 }
 
+// promotionBuilderID returns the builder ID recorded in promotion record
+// attestations.
+func promotionBuilderID() string {
+	builderID := "https://k8s.io/promo-tools"
+	if v := version.GetVersionInfo().GitVersion; v != "" {
+		builderID += "@" + v
+	}
+
+	return builderID
+}
+
 // pushAttestation generates a provenance attestation, signs it into a
 // sigstore bundle, and attaches it to the destination digest as an OCI 1.1
 // referrer artifact (as cosign does now). The referrer manifest carries
@@ -427,7 +750,12 @@ func (di *DefaultPromoterImplementation) pushAttestation(
 	remoteOpt := ociremote.WithRemoteOptions(di.remoteOptions()...)
 
 	// Check if our predicate type already exists (idempotent).
-	if di.hasBundleForPredicate(digest, provenance.PredicateType) {
+	attested, err := di.hasBundleForPredicate(ctx, digest, provenance.PredicateType)
+	if err != nil {
+		return fmt.Errorf("checking the attestations of %s: %w", dstDigestRef, err)
+	}
+
+	if attested {
 		logrus.Debugf("Attestation for %s already exists, skipping", dstDigestRef)
 
 		return nil
@@ -453,26 +781,34 @@ func (di *DefaultPromoterImplementation) pushAttestation(
 
 // hasBundleForPredicate checks if the given digest already has an
 // attestation bundle referrer with the specified predicate type.
+func (di *DefaultPromoterImplementation) hasBundleForPredicate(
+	ctx context.Context, digest name.Digest, predicateType string,
+) (bool, error) {
+	_, ok, err := di.bundleForPredicate(ctx, digest, predicateType)
+
+	return ok, err
+}
+
+// bundleForPredicate looks for an attestation bundle referrer of the given
+// digest with the specified predicate type and returns its digest.
 //
 // When dealing with descriptors without annotations, we fetch the
 // referrer manifest itself.
-func (di *DefaultPromoterImplementation) hasBundleForPredicate(
-	digest name.Digest, predicateType string,
-) bool {
-	idx, err := ociremote.Referrers(
-		digest, "", ociremote.WithRemoteOptions(di.remoteOptions()...),
-	)
+func (di *DefaultPromoterImplementation) bundleForPredicate(
+	ctx context.Context, digest name.Digest, predicateType string,
+) (v1.Hash, bool, error) {
+	referrers, err := di.registryProvider.ListReferrers(ctx, digest.String(), "")
 	if err != nil {
-		return false
+		return v1.Hash{}, false, fmt.Errorf("listing referrers of %s: %w", digest, err)
 	}
 
 	// Cycle all the manifest descriptors
-	for i := range idx.Manifests {
-		desc := &idx.Manifests[i]
+	for i := range referrers {
+		desc := &referrers[i]
 
 		// Best case scenario: we find the cosign annotation
 		if desc.Annotations[bundlePredicateTypeAnnotation] == predicateType {
-			return true
+			return desc.Digest, true, nil
 		}
 
 		if len(desc.Annotations) > 0 {
@@ -480,11 +816,11 @@ func (di *DefaultPromoterImplementation) hasBundleForPredicate(
 		}
 
 		// No annotations in the descriptor, fetch the manifest and check it
-		raw, err := remote.Get(
-			digest.Context().Digest(desc.Digest.String()), di.remoteOptions()...,
-		)
+		ref := digest.Context().Digest(desc.Digest.String()).String()
+
+		raw, _, err := di.registryProvider.GetManifest(ctx, ref)
 		if err != nil {
-			continue
+			return v1.Hash{}, false, fmt.Errorf("reading referrer %s: %w", ref, err)
 		}
 
 		// Parse the manifest to find the predicate type annotation
@@ -492,30 +828,26 @@ func (di *DefaultPromoterImplementation) hasBundleForPredicate(
 			Annotations map[string]string `json:"annotations"`
 		}
 
-		if err := json.Unmarshal(raw.Manifest, &manifest); err != nil {
+		if err := json.Unmarshal(raw, &manifest); err != nil {
 			continue
 		}
 
 		if manifest.Annotations[bundlePredicateTypeAnnotation] == predicateType {
-			return true
+			return desc.Digest, true, nil
 		}
 	}
 
-	return false
+	return v1.Hash{}, false, nil
 }
 
-// craneCopyWithTimeout wraps crane.Copy with a per-request context timeout.
-// It copies the opts slice to avoid mutating the caller's backing array.
-func craneCopyWithTimeout(ctx context.Context, src, dst string, timeout time.Duration, opts []crane.Option) error {
+// copyWithTimeout copies src to dst with the registry provider, bounded by
+// a per-request context timeout.
+func (di *DefaultPromoterImplementation) copyWithTimeout(ctx context.Context, src, dst string, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	withCtx := make([]crane.Option, len(opts), len(opts)+1)
-	copy(withCtx, opts)
-	withCtx = append(withCtx, crane.WithContext(ctx))
-
 	//nolint:wrapcheck // callers add their own context-specific wrapping
-	return crane.Copy(src, dst, withCtx...)
+	return di.registryProvider.CopyImage(ctx, src, dst)
 }
 
 // PrewarmTUFCache initializes the TUF cache so that threads do not have to compete
@@ -63,7 +63,8 @@ func newTLSTestRegistry(t *testing.T) (string, *DefaultPromoterImplementation) {
 	rt := ratelimit.NewRoundTripperWithBase(ratelimit.MaxEvents, s.Client().Transport)
 
 	di := &DefaultPromoterImplementation{
-		transport: rt,
+		transport:        rt,
+		registryProvider: reg.NewCraneProvider(reg.WithTransport(rt)),
 	}
 
 	return host, di
@@ -158,7 +159,7 @@ func (f *fakeGenerator) Generate(_ context.Context, _ *provenance.PromotionRecor
 	return f.data, nil
 }
 
-// fakeStatementSigner is a statementSigner that returns a fixed bundle.
+// fakeStatementSigner is a StatementSigner that returns a fixed bundle.
 type fakeStatementSigner struct {
 	bundle []byte
 	calls  int
@@ -196,8 +197,9 @@ func TestPushAttestation(t *testing.T) {
 	digestRef, err := name.NewDigest(fmt.Sprintf("%s/production/myimage@%s", host, digest))
 	require.NoError(t, err)
 
-	require.True(t, di.hasBundleForPredicate(digestRef, provenance.PredicateType),
-		"attestation bundle with predicate type should exist")
+	attested, err := di.hasBundleForPredicate(context.Background(), digestRef, provenance.PredicateType)
+	require.NoError(t, err)
+	require.True(t, attested, "attestation bundle with predicate type should exist")
 }
 
 func TestPushAttestationIdempotent(t *testing.T) {
@@ -240,7 +242,7 @@ func TestPushAttestationIdempotent(t *testing.T) {
 	require.Len(t, idx.Manifests, 1, "should have exactly one attestation referrer")
 }
 
-// keyBundleSigner is a statementSigner that signs statements into real
+// keyBundleSigner is a StatementSigner that signs statements into real
 // sigstore bundles using an ephemeral test key. It stands in for the
 // keyless production signer so that pushed attestations can be verified
 // cryptographically without contacting Fulcio or Rekor.
@@ -464,6 +466,7 @@ func TestWriteProvenanceAttestationsIdempotent(t *testing.T) {
 	digestRef, err := name.NewDigest(fmt.Sprintf("%s/app@%s", dstRegistry, digest))
 	require.NoError(t, err)
 
-	require.True(t, di.hasBundleForPredicate(digestRef, provenance.PredicateType),
-		"attestation bundle should exist in production")
+	attested, err := di.hasBundleForPredicate(context.Background(), digestRef, provenance.PredicateType)
+	require.NoError(t, err)
+	require.True(t, attested, "attestation bundle should exist in production")
 }
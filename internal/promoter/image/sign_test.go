@@ -25,10 +25,13 @@ import (
 
 	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/release-sdk/sign"
 
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
@@ -167,12 +170,57 @@ func TestTargetIdentity(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			res := targetIdentity(edge)
+			res := targetIdentity(options.DefaultOptions.SignIdentityRules, edge)
 			assert(res)
 		})
 	}
 }
 
+func TestPublicIdentity(t *testing.T) {
+	t.Parallel()
+
+	rules := []options.IdentityRule{
+		{Prefix: "example-prod/images", Identity: "registry.example.com"},
+		{Prefix: "example-prod", Identity: "registry.example.com"},
+		{Prefix: "ghcr.io/example", Identity: "cr.example.com/gh"},
+	}
+
+	for _, tc := range []struct {
+		repo string
+		want string
+	}{
+		{"us-docker.pkg.dev/example-prod/images/app", "registry.example.com/app"},
+		{"europe-docker.pkg.dev/example-prod/images/sub/app", "registry.example.com/sub/app"},
+		{"eu.gcr.io/example-prod/app", "registry.example.com/app"},
+		{"eu.gcr.io/example-prod", "registry.example.com"},
+		{"ghcr.io/example/app", "cr.example.com/gh/app"},
+		{"ghcr.io/example-other/app", "ghcr.io/example-other/app"},
+		{"us-docker.pkg.dev/example-production/app", "us-docker.pkg.dev/example-production/app"},
+	} {
+		t.Run(tc.repo, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.want, publicIdentity(rules, tc.repo))
+		})
+	}
+}
+
+func TestMirrorRepository(t *testing.T) {
+	t.Parallel()
+
+	rules := options.DefaultOptions.SignIdentityRules
+
+	repo, ok := mirrorRepository(rules, "us-west2-docker.pkg.dev/k8s-artifacts-prod/images", "registry.k8s.io/kube-apiserver")
+	require.True(t, ok)
+	require.Equal(t, "us-west2-docker.pkg.dev/k8s-artifacts-prod/images/kube-apiserver", repo)
+
+	// Only the images path of the production registry maps to registry.k8s.io
+	_, ok = mirrorRepository(rules, "eu.gcr.io/k8s-artifacts-prod", "registry.k8s.io/kube-apiserver")
+	require.False(t, ok)
+
+	_, ok = mirrorRepository(rules, "gcr.io/other-prod", "registry.k8s.io/kube-apiserver")
+	require.False(t, ok)
+}
+
 func TestGroupEdgesByIdentityDigest(t *testing.T) {
 	t.Parallel()
 
@@ -209,29 +257,233 @@ func TestGroupEdgesByIdentityDigest(t *testing.T) {
 		mkEdge("us-central1-docker.pkg.dev/k8s-artifacts-prod/images", "app", digest1, ""): nil,
 	}
 
-	groups := groupEdgesByIdentityDigest(edges)
+	// The zero value options fall back to the default identity rules and
+	// canonical registry.
+	for _, tc := range []struct {
+		name string
+		opts *options.Options
+	}{
+		{name: "default options", opts: options.DefaultOptions},
+		{name: "zero value options", opts: &options.Options{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := groupEdgesByIdentityDigest(tc.opts, edges)
+
+			// The edges array must not be mutated if we drain it, it silently skips
+			// the attestation generation.
+			require.Len(t, edges, 8)
+
+			// Should have 2 groups (digest1 and digest2), metadata/tagless skipped.
+			require.Len(t, groups, 2)
+
+			// Sort groups by digest for deterministic assertion.
+			sort.Slice(groups, func(i, j int) bool {
+				return groups[i][0].Digest < groups[j][0].Digest
+			})
+
+			// Group 1: digest1 with 4 edges. The canonical registry (us-central1)
+			// must be first regardless of alphabetical ordering.
+			require.Len(t, groups[0], 4)
+			require.Equal(t, image.Registry("us-central1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][0].DstRegistry.Name)
+			require.Equal(t, image.Registry("asia-east1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][1].DstRegistry.Name)
+			require.Equal(t, image.Registry("us-east1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][2].DstRegistry.Name)
+			require.Equal(t, image.Registry("us-west1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][3].DstRegistry.Name)
+
+			// Group 2: digest2 with 1 edge.
+			require.Len(t, groups[1], 1)
+			require.Equal(t, digest2, groups[1][0].Digest)
+		})
+	}
+}
+
+// fakeImageVerifier accepts signatures from a single identity. Images in
+// unsigned are reported as not signed, all others as signed by signedBy.
+type fakeImageVerifier struct {
+	identity string
+	signedBy map[string]string
+}
+
+func (f *fakeImageVerifier) VerifyImage(ref string) (*sign.SignedObject, error) {
+	identity, ok := f.signedBy[ref]
+	if !ok {
+		return nil, nil //nolint:nilnil // mirrors sign.Signer for unsigned images
+	}
+
+	if identity != f.identity {
+		return nil, fmt.Errorf("none of the expected identities matched: %s", identity)
+	}
+
+	return &sign.SignedObject{}, nil
+}
+
+func TestGetUnsignedEdges(t *testing.T) {
+	t.Parallel()
+
+	const (
+		foo    = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		bar    = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		fooSig = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	)
+
+	provider := registry.NewFakeProvider()
+	provider.AddImage("gcr.io/staging", "foo", foo, "v1.0")
+	provider.AddImage("gcr.io/staging", "bar", bar, "v1.0")
+	provider.AddImage("gcr.io/prod", "foo", foo, "v1.0")
+	provider.AddImage("gcr.io/prod", "foo", fooSig, image.Tag(digestToSignatureTag(foo)))
+	provider.AddImage("gcr.io/mirror", "foo", foo, "v1.0")
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	mfests := []schema.Manifest{{
+		Registries: []registry.Context{
+			{Name: "gcr.io/staging", Src: true},
+			{Name: "gcr.io/prod"},
+			{Name: "gcr.io/mirror"},
+		},
+		Images: []registry.Image{
+			{Name: "foo", Dmap: registry.DigestTags{foo: {"v1.0"}}},
+			{Name: "bar", Dmap: registry.DigestTags{bar: {"v1.0"}}},
+		},
+	}}
+	require.NoError(t, mfests[0].Finalize())
+
+	// Signed and missing destination copies are skipped
+	edges, err := di.GetUnsignedEdges(t.Context(), &options.Options{}, mfests)
+	require.NoError(t, err)
+	require.Len(t, edges, 1)
+
+	for edge := range edges {
+		require.EqualValues(t, foo, edge.Digest)
+		require.EqualValues(t, "gcr.io/mirror", edge.DstRegistry.Name)
+	}
+
+	// Only the images signed in the canonical registry are skipped when the
+	// registries share an identity
+	opts := &options.Options{
+		SignIdentityRules: []options.IdentityRule{
+			{Prefix: "gcr.io/prod", Identity: "registry.k8s.io"},
+			{Prefix: "gcr.io/mirror", Identity: "registry.k8s.io"},
+		},
+		SignCanonicalRegistry: "gcr.io/prod",
+	}
+
+	edges, err = di.GetUnsignedEdges(t.Context(), opts, mfests)
+	require.NoError(t, err)
+	require.Empty(t, edges)
+}
+
+func TestValidateStagingSignatures(t *testing.T) {
+	t.Parallel()
+
+	const (
+		globalIdentity = "global@example.com"
+		teamIdentity   = "team@example.com"
+	)
+
+	digestA := image.Digest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	digestB := image.Digest("sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
 
-	// The edges array must not be mutated if we drain it, it silently skips
-	// the attestation generation.
-	require.Len(t, edges, 8)
+	src := registry.Context{Name: "gcr.io/staging", Src: true}
+	dst := registry.Context{Name: "gcr.io/prod"}
+
+	mkEdge := func(digest image.Digest) promotion.Edge {
+		return promotion.Edge{
+			SrcRegistry: src,
+			SrcImageTag: promotion.ImageTag{Name: "app"},
+			Digest:      digest,
+			DstRegistry: dst,
+			DstImageTag: promotion.ImageTag{Name: "app", Tag: "v1"},
+		}
+	}
 
-	// Should have 2 groups (digest1 and digest2), metadata/tagless skipped.
-	require.Len(t, groups, 2)
+	edgeA, edgeB := mkEdge(digestA), mkEdge(digestB)
 
-	// Sort groups by digest for deterministic assertion.
-	sort.Slice(groups, func(i, j int) bool {
-		return groups[i][0].Digest < groups[j][0].Digest
-	})
+	mkManifests := func(policy *schema.SignaturePolicy) []schema.Manifest {
+		return []schema.Manifest{{
+			Registries: []registry.Context{src, dst},
+			Images: []registry.Image{{
+				Name: "app",
+				Dmap: registry.DigestTags{digestA: {"v1"}, digestB: {"v2"}},
+			}},
+			StagingSignatures: policy,
+			SrcRegistry:       &src,
+		}}
+	}
 
-	// Group 1: digest1 with 4 edges. The canonical registry (us-central1)
-	// must be first regardless of alphabetical ordering.
-	require.Len(t, groups[0], 4)
-	require.Equal(t, image.Registry("us-central1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][0].DstRegistry.Name)
-	require.Equal(t, image.Registry("asia-east1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][1].DstRegistry.Name)
-	require.Equal(t, image.Registry("us-east1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][2].DstRegistry.Name)
-	require.Equal(t, image.Registry("us-west1-docker.pkg.dev/k8s-artifacts-prod/images"), groups[0][3].DstRegistry.Name)
+	teamSigner := []schema.Signer{{Identity: teamIdentity, Issuer: "https://issuer"}}
 
-	// Group 2: digest2 with 1 edge.
-	require.Len(t, groups[1], 1)
-	require.Equal(t, digest2, groups[1][0].Digest)
+	for _, tc := range []struct {
+		name      string
+		policy    *schema.SignaturePolicy
+		signedBy  map[string]string
+		shouldErr bool
+	}{
+		{
+			name:     "no policy uses the global identity",
+			signedBy: map[string]string{edgeA.SrcReference(): globalIdentity},
+		},
+		{
+			name:      "no policy rejects other identities",
+			signedBy:  map[string]string{edgeA.SrcReference(): teamIdentity},
+			shouldErr: true,
+		},
+		{
+			name:     "manifest signers replace the global identity",
+			policy:   &schema.SignaturePolicy{Signers: teamSigner},
+			signedBy: map[string]string{edgeA.SrcReference(): teamIdentity, edgeB.SrcReference(): teamIdentity},
+		},
+		{
+			name:      "manifest signers reject the global identity",
+			policy:    &schema.SignaturePolicy{Signers: teamSigner},
+			signedBy:  map[string]string{edgeA.SrcReference(): globalIdentity},
+			shouldErr: true,
+		},
+		{
+			name:   "required mode rejects unsigned images",
+			policy: &schema.SignaturePolicy{Mode: schema.SignatureModeRequired, Signers: teamSigner},
+			signedBy: map[string]string{
+				edgeA.SrcReference(): teamIdentity,
+			},
+			shouldErr: true,
+		},
+		{
+			name:   "required mode passes when all images are signed",
+			policy: &schema.SignaturePolicy{Mode: schema.SignatureModeRequired, Signers: teamSigner},
+			signedBy: map[string]string{
+				edgeA.SrcReference(): teamIdentity,
+				edgeB.SrcReference(): teamIdentity,
+			},
+		},
+		{
+			name:     "required mode without signers uses the global identity",
+			policy:   &schema.SignaturePolicy{Mode: schema.SignatureModeRequired},
+			signedBy: map[string]string{edgeA.SrcReference(): globalIdentity, edgeB.SrcReference(): globalIdentity},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			di := &DefaultPromoterImplementation{
+				newVerifier: func(o *sign.Options) ImageVerifier {
+					return &fakeImageVerifier{identity: o.CertIdentity, signedBy: tc.signedBy}
+				},
+			}
+
+			opts := &options.Options{
+				SignCheckIdentity: globalIdentity,
+				SignCheckIssuer:   "https://issuer",
+			}
+
+			err := di.ValidateStagingSignatures(
+				opts, mkManifests(tc.policy), map[promotion.Edge]any{edgeA: nil, edgeB: nil},
+			)
+			if tc.shouldErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
 }
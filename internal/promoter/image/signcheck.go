@@ -18,62 +18,48 @@ package imagepromoter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/url"
+	"net/http"
+	"os"
+	"regexp"
 	"slices"
+	"sort"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/google/go-containerregistry/pkg/authn"
-	"github.com/google/go-containerregistry/pkg/crane"
-	"github.com/google/go-containerregistry/pkg/gcrane"
 	"github.com/google/go-containerregistry/pkg/name"
-	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
-	"sigs.k8s.io/release-sdk/sign"
-	"sigs.k8s.io/release-utils/http"
 	"sigs.k8s.io/release-utils/version"
-	"sigs.k8s.io/yaml"
 
-	"sigs.k8s.io/promo-tools/v4/image/consts"
 	checkresults "sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
-var (
-	mirrorsOnce     sync.Once
-	mirrorsList     []string
-	errMirrorsFetch error
-)
-
-const (
-	productionImagePath      = "k8s-artifacts-prod"
-	productionRepositoryPath = productionImagePath + "/images"
-
-	// canonicalRegistry is the primary Artifact Registry region used for
-	// indexing images and as the signing target. This must match the
-	// SIGNATURE_UPSTREAM_ENDPOINT configured in archeio so that signature
-	// lookups via registry.k8s.io are routed to the correct backend.
-	canonicalRegistry = "us-central1-docker.pkg.dev"
-)
-
-func (di *DefaultPromoterImplementation) GetLatestImages(opts *options.Options) ([]string, error) {
+func (di *DefaultPromoterImplementation) GetLatestImages(opts *options.Options) ([]checkresults.Image, error) {
 	// If there is a list of images to check in the options
 	// we default to checking those.
 	if len(opts.SignCheckReferences) > 0 {
+		images := make([]checkresults.Image, 0, len(opts.SignCheckReferences))
+
 		for _, refString := range opts.SignCheckReferences {
 			_, err := name.ParseReference(refString)
 			if err != nil {
 				return nil, fmt.Errorf("invalid image reference %s: %w", refString, err)
 			}
+
+			images = append(images, checkresults.Image{Reference: refString})
 		}
 
-		return opts.SignCheckReferences, nil
+		return images, nil
 	}
 
 	images, err := di.readLatestImages(opts)
@@ -86,115 +72,132 @@ func (di *DefaultPromoterImplementation) GetLatestImages(opts *options.Options)
 	return images, nil
 }
 
-func (di *DefaultPromoterImplementation) getMirrors() ([]string, error) {
-	mirrorsOnce.Do(func() {
-		iurls := map[string]string{}
-
-		manifest, err := http.NewAgent().Get(
-			"https://github.com/kubernetes/k8s.io/raw/main/registry.k8s.io/manifests/k8s-staging-kubernetes/promoter-manifest.yaml",
-		)
-		if err != nil {
-			errMirrorsFetch = fmt.Errorf("downloading promoter manifest: %w", err)
-
-			return
-		}
-
-		type entriesList struct {
-			Registries []struct {
-				Name string `json:"name,omitempty" yaml:"name,omitempty"`
-				Src  bool   `json:"src,omitempty"  yaml:"src,omitempty"`
-			} `json:"registries" yaml:"registries"`
-		}
-
-		entries := entriesList{}
-		if err := yaml.Unmarshal(manifest, &entries); err != nil {
-			errMirrorsFetch = fmt.Errorf("unmarshalling promoter manifest: %w", err)
+// getMirrors returns the destination registries declared in the promoter
+// manifests configured in the options. Each registry is a full registry
+// path, e.g. "us-central1-docker.pkg.dev/k8s-artifacts-prod/images".
+func (di *DefaultPromoterImplementation) getMirrors(opts *options.Options) ([]string, error) {
+	mfests, err := di.ParseManifests(opts)
+	if err != nil {
+		return nil, fmt.Errorf("parsing manifests: %w", err)
+	}
 
-			return
-		}
+	registries := map[string]struct{}{}
 
-		for _, e := range entries.Registries {
-			if e.Src {
+	for _, mfest := range mfests {
+		for _, rc := range mfest.Registries {
+			if rc.Src {
 				continue
 			}
 
-			u, err := url.Parse("https://" + e.Name)
-			if err != nil {
-				errMirrorsFetch = fmt.Errorf("parsing url %s: %w", u, err)
-
-				return
-			}
-
-			iurls[u.Hostname()] = u.Hostname()
+			registries[strings.TrimRight(string(rc.Name), "/")] = struct{}{}
 		}
+	}
 
-		urls := make([]string, 0, len(iurls))
-		for u := range iurls {
-			urls = append(urls, u)
-		}
+	if len(registries) == 0 {
+		return nil, errors.New("no destination registries found in manifests")
+	}
 
-		mirrorsList = urls
-	})
+	mirrors := make([]string, 0, len(registries))
+	for r := range registries {
+		mirrors = append(mirrors, r)
+	}
+
+	sort.Strings(mirrors)
 
-	return mirrorsList, errMirrorsFetch
+	return mirrors, nil
 }
 
 func (di *DefaultPromoterImplementation) GetSignatureStatus(
-	opts *options.Options, images []string,
+	ctx context.Context, opts *options.Options, images []checkresults.Image,
 ) (checkresults.Signature, error) {
 	results := checkresults.Signature{}
 
-	mirrors, err := di.getMirrors()
+	mirrors, err := di.getMirrors(opts)
 	if err != nil {
 		return results, fmt.Errorf("reading mirrors: %w", err)
 	}
 
 	logrus.Infof(
-		"Checking %d images for signatures, each in %d mirrors",
+		"Checking %d images for signatures in %d destination registries",
 		len(images), len(mirrors),
 	)
 
-	for _, refString := range images {
+	for _, img := range images {
+		refString := img.Reference
+
 		ref, err := name.ParseReference(refString)
 		if err != nil {
 			return results, fmt.Errorf("parsing reference: %w", err)
 		}
 
-		digest, err := crane.Digest(refString)
+		digest, err := di.registryProvider.HeadDigest(ctx, refString)
 		if err != nil {
 			return results, fmt.Errorf("getting digest for %s: %w", refString, err)
 		}
 
-		targetImages := []string{}
-
-		for _, mirror := range mirrors {
-			rpath := productionRepositoryPath
-			if strings.HasSuffix(mirror, ".gcr.io") {
-				rpath = productionImagePath
-			}
-
-			targetImages = append(targetImages, fmt.Sprintf("%s/%s/%s:%s.sig",
-				mirror, rpath, ref.Context().RepositoryStr(),
-				strings.ReplaceAll(digest, ":", "-"),
-			))
-		}
+		targetImages := mirrorSignatureReferences(
+			opts.IdentityRules(), mirrors, ref, digest,
+		)
 
 		logrus.Infof("Checking %s for signatures in %d mirrors", refString, len(targetImages))
 
-		existing, missing, err := di.CheckSignatureLayers(opts, targetImages)
+		list, err := di.CheckSignatureLayers(opts, targetImages)
 		if err != nil {
 			return results, fmt.Errorf("checking objects: %w", err)
 		}
 
-		results[refString] = checkresults.CheckList{
-			Signed:  existing,
-			Missing: missing,
+		if opts.SignCheckAttestations {
+			refs := make([]string, 0, len(targetImages))
+			for _, target := range targetImages {
+				refs = append(refs, signatureDigestReference(target))
+			}
+
+			if err := di.CheckAttestations(ctx, &list, refs); err != nil {
+				return results, fmt.Errorf("checking attestations: %w", err)
+			}
 		}
+
+		list.Digest = string(digest)
+		list.Uploaded = img.Uploaded
+
+		results[refString] = list
 	}
 
 	return results, nil
 }
 
+// mirrorSignatureReferences returns the signature references of an image in
+// every mirror serving it. Mirrors are destination registries of possibly
+// different subprojects, so several of them can resolve to the same
+// repository; each signature reference is only returned once.
+func mirrorSignatureReferences(
+	rules []options.IdentityRule, mirrors []string, ref name.Reference, digest image.Digest,
+) []string {
+	seen := map[string]struct{}{}
+	targets := []string{}
+
+	for _, mirror := range mirrors {
+		// Find the repository backing the public reference in
+		// the mirror using the signature identity rules.
+		repo, ok := mirrorRepository(rules, mirror, ref.Context().Name())
+		if !ok {
+			logrus.Debugf("Mirror %s does not serve %s, skipping", mirror, ref.Context().Name())
+
+			continue
+		}
+
+		target := repo + ":" + digestToSignatureTag(digest)
+		if _, ok := seen[target]; ok {
+			continue
+		}
+
+		seen[target] = struct{}{}
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
 // miniManifest is a minimal representation of the sigstore signature manifest.
 type miniManifest struct {
 	Layers []struct {
@@ -203,71 +206,121 @@ type miniManifest struct {
 	} `json:"layers"`
 }
 
-// CheckSignatureLayers checks a list of signature layers in parallel.
-func (di *DefaultPromoterImplementation) CheckSignatureLayers(opts *options.Options, oList []string) ([]string, []string, error) {
-	type result struct {
-		ref    string
-		exists bool
+// signatureStatus is the outcome of checking a signature reference.
+type signatureStatus int
+
+const (
+	signatureMissing signatureStatus = iota
+	signatureValid
+	signatureInvalid
+	signatureWrongIdentity
+)
+
+// CheckSignatureLayers checks a list of signature references in parallel.
+// Signatures with a certificate for the expected identity are verified
+// cryptographically using the same options as the promoter signer.
+func (di *DefaultPromoterImplementation) CheckSignatureLayers(
+	opts *options.Options, oList []string,
+) (checkresults.CheckList, error) {
+	matcher, err := newIdentityMatcher(opts)
+	if err != nil {
+		return checkresults.CheckList{}, err
 	}
 
-	results := make([]result, len(oList))
+	statuses := make([]signatureStatus, len(oList))
+	verifier := di.verifierFor(defaultSignerOptions(opts))
 
 	g := new(errgroup.Group)
 	g.SetLimit(10)
 
 	for i, s := range oList {
-		results[i].ref = s
-
 		g.Go(func() error {
-			e, err := objectExists(opts, s)
+			status, err := di.inspectSignature(matcher, s)
 			if err != nil {
 				return fmt.Errorf("checking reference: %w", err)
 			}
 
-			results[i].exists = e
+			if status == signatureValid {
+				status = verifySignatureReference(verifier, s)
+			}
+
+			statuses[i] = status
 
 			return nil
 		})
 	}
 
 	if err := g.Wait(); err != nil {
-		return nil, nil, fmt.Errorf("checking signature layers: %w", err)
+		return checkresults.CheckList{}, fmt.Errorf("checking signature layers: %w", err)
 	}
 
-	var existing, missing []string
-
-	for _, r := range results {
-		if r.exists {
-			existing = append(existing, r.ref)
-		} else {
-			missing = append(missing, r.ref)
+	list := checkresults.CheckList{}
+
+	for i, status := range statuses {
+		switch status {
+		case signatureValid:
+			list.Signed = append(list.Signed, oList[i])
+		case signatureInvalid:
+			list.Invalid = append(list.Invalid, oList[i])
+		case signatureWrongIdentity:
+			list.WrongIdentity = append(list.WrongIdentity, oList[i])
+		case signatureMissing:
+			list.Missing = append(list.Missing, oList[i])
 		}
 	}
 
-	return existing, missing, nil
+	return list, nil
+}
+
+// verifySignatureReference verifies the image a signature reference
+// belongs to and classifies the result.
+func verifySignatureReference(verifier ImageVerifier, sigRef string) signatureStatus {
+	digestRef := signatureDigestReference(sigRef)
+
+	obj, err := verifier.VerifyImage(digestRef)
+	if err != nil {
+		logrus.WithField("image", digestRef).Warnf("Signature verification failed: %v", err)
+
+		return signatureInvalid
+	}
+
+	// The signature was deleted between the manifest check and the
+	// verification.
+	if obj == nil {
+		return signatureMissing
+	}
+
+	return signatureValid
 }
 
-func objectExists(opts *options.Options, refString string) (bool, error) {
-	// Check
-	manifestData, err := crane.Manifest(refString)
+// signatureDigestReference turns a signature reference of the form
+// repo:sha256-<hex>.sig into the digest reference of the signed image.
+func signatureDigestReference(sigRef string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(sigRef, ":sha256-", "@sha256:"), ".sig")
+}
+
+// inspectSignature reads the signature manifest of a reference and checks
+// the certificates of its layers. It returns signatureValid if one of them
+// was issued to the expected identity; the signature still needs to be
+// verified.
+func (di *DefaultPromoterImplementation) inspectSignature(
+	matcher *identityMatcher, refString string,
+) (signatureStatus, error) {
+	manifestData, _, err := di.registryProvider.GetManifest(context.TODO(), refString)
 	if err != nil {
-		if strings.Contains(err.Error(), "MANIFEST_UNKNOWN") {
+		var terr *transport.Error
+		if errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound {
 			logrus.WithField("image", refString).Info("No signature found")
 
-			return false, nil
+			return signatureMissing, nil
 		}
 
-		return false, fmt.Errorf("pulling signature manifest: %w", err)
+		return signatureMissing, fmt.Errorf("pulling signature manifest: %w", err)
 	}
 
 	manifest := &miniManifest{}
 	if err := json.Unmarshal(manifestData, manifest); err != nil {
-		return false, fmt.Errorf("parsing .sig image manifest: %w", err)
-	}
-
-	// Get the certificate
-	if len(manifest.Layers) == 0 {
-		return false, nil
+		return signatureMissing, fmt.Errorf("parsing .sig image manifest: %w", err)
 	}
 
 	signedLayers := 0
@@ -287,82 +340,156 @@ func objectExists(opts *options.Options, refString string) (bool, error) {
 
 		certs, err := cryptoutils.LoadCertificatesFromPEM(&b)
 		if err != nil {
-			return false, fmt.Errorf("loading certificates from PEM: %w", err)
+			logrus.WithField("image", refString).Warnf("Unable to load signature certificate: %v", err)
+
+			return signatureInvalid, nil
 		}
 
-		names := cryptoutils.GetSubjectAlternateNames(certs[0])
-		if slices.Contains(names, opts.SignCheckIdentity) {
-			return true, nil
+		if len(certs) == 0 {
+			continue
 		}
 
 		signedLayers++
+
+		if matcher.matches(cryptoutils.GetSubjectAlternateNames(certs[0])) {
+			return signatureValid, nil
+		}
 	}
 
 	if signedLayers == 0 {
 		logrus.WithField("image", refString).Debugf("No certificates found")
-	} else {
-		logrus.WithField("image", refString).Debugf("Image signed, but not with expected identity")
+
+		return signatureMissing, nil
 	}
 
-	return false, nil
+	logrus.WithField("image", refString).Infof("Image signed, but not with expected identity")
+
+	return signatureWrongIdentity, nil
+}
+
+// identityMatcher matches the subject alternative names of a certificate
+// against the signing identity configured in the options.
+type identityMatcher struct {
+	identity string
+	re       *regexp.Regexp
 }
 
-// FixMissingSignatures signs an image that has no signatures at all.
+// newIdentityMatcher compiles the identity regexp of the options. It is
+// checked by Options.Validate, so this only fails for unvalidated options.
+func newIdentityMatcher(opts *options.Options) (*identityMatcher, error) {
+	m := &identityMatcher{identity: opts.SignCheckIdentity}
+
+	if opts.SignCheckIdentityRegexp != "" {
+		re, err := regexp.Compile(opts.SignCheckIdentityRegexp)
+		if err != nil {
+			return nil, fmt.Errorf("compiling the signature check identity regexp: %w", err)
+		}
+
+		m.re = re
+	}
+
+	return m, nil
+}
+
+// matches returns true if one of the subject alternative names of a
+// certificate matches the signing identity.
+func (m *identityMatcher) matches(names []string) bool {
+	if m.identity != "" && slices.Contains(names, m.identity) {
+		return true
+	}
+
+	if m.re == nil {
+		return m.identity == ""
+	}
+
+	return slices.ContainsFunc(names, m.re.MatchString)
+}
+
+// FixMissingSignatures signs an image that has no verified signatures at
+// all. Signatures that are invalid or from the wrong identity are replaced.
 func (di *DefaultPromoterImplementation) FixMissingSignatures(opts *options.Options, results checkresults.Signature) error {
 	for mainImg, res := range results {
-		if len(res.Signed) > 0 || len(res.Missing) == 0 {
+		unverified := res.Unverified()
+		if len(res.Signed) > 0 || len(unverified) == 0 {
 			continue
 		}
 
 		logrus.Infof("Signing and replicating first mirror (%s)", mainImg)
 
-		// Build the digest of the first missing one
-		digestRef := strings.TrimSuffix(strings.ReplaceAll(res.Missing[0], ":sha256-", "@sha256:"), ".sig")
+		// Build the digest of the first unverified one
+		digestRef := signatureDigestReference(unverified[0])
 		if err := di.signReference(opts, digestRef); err != nil {
 			return fmt.Errorf("signing first mirror reference %s: %w", digestRef, err)
 		}
 
-		logrus.Infof("Replicating image to %d mirrors", len(res.Missing[1:]))
+		res.Actions = append(res.Actions, checkresults.Action{
+			Type: checkresults.ActionSign, Target: digestRef, Applied: opts.SignCheckFix,
+		})
+
+		logrus.Infof("Replicating image to %d mirrors", len(unverified[1:]))
 
-		for _, targetRef := range res.Missing[1:] {
-			if err := di.replicateReference(opts, res.Missing[0], targetRef); err != nil {
+		for _, targetRef := range unverified[1:] {
+			if err := di.replicateReference(opts, unverified[0], targetRef); err != nil {
 				return fmt.Errorf("replicating signature: %w", err)
 			}
+
+			res.Actions = append(res.Actions, checkresults.Action{
+				Type: checkresults.ActionReplicate, Source: unverified[0], Target: targetRef, Applied: opts.SignCheckFix,
+			})
 		}
+
+		results[mainImg] = res
 	}
 
 	return nil
 }
 
-// FixPartialSignatures fixes images that had some signatures but some mirrors
-// are missing some signatures.
+// FixPartialSignatures fixes images that had some verified signatures but
+// some mirrors are missing them or hold invalid ones.
 func (di *DefaultPromoterImplementation) FixPartialSignatures(opts *options.Options, results checkresults.Signature) error {
 	for mainImg, res := range results {
-		if len(res.Missing) == 0 || len(res.Signed) == 0 {
+		unverified := res.Unverified()
+		if len(unverified) == 0 || len(res.Signed) == 0 {
 			continue
 		}
 
-		logrus.Infof("%s has %d signed copies, %d are missing", mainImg, len(res.Signed), len(res.Missing))
+		logrus.Infof(
+			"%s has %d signed copies, %d are missing, %d invalid and %d from the wrong identity",
+			mainImg, len(res.Signed), len(res.Missing), len(res.Invalid), len(res.WrongIdentity),
+		)
 
 		sourceRef := res.Signed[0]
-		for _, targetRef := range res.Missing {
+		for _, targetRef := range unverified {
 			// Copy the first signature to the target ref
 			if err := di.replicateReference(opts, sourceRef, targetRef); err != nil {
 				return fmt.Errorf("replicating signature: %w", err)
 			}
+
+			res.Actions = append(res.Actions, checkresults.Action{
+				Type: checkresults.ActionReplicate, Source: sourceRef, Target: targetRef, Applied: opts.SignCheckFix,
+			})
 		}
+
+		results[mainImg] = res
 	}
 
 	return nil
 }
 
-// replicateReference copies an image reference to another mirror.
-func (di *DefaultPromoterImplementation) replicateReference(opts *options.Options, srcRef, dstRef string) error {
-	craneOpts := []crane.Option{
-		crane.WithAuthFromKeychain(gcrane.Keychain),
-		crane.WithUserAgent(image.UserAgent),
+// WriteSignatureReport writes the signature check results to stdout in the
+// report format configured in the options.
+func (di *DefaultPromoterImplementation) WriteSignatureReport(
+	opts *options.Options, results checkresults.Signature,
+) error {
+	if err := report.Write(os.Stdout, results, opts.SignCheckOutput); err != nil {
+		return fmt.Errorf("writing signature report: %w", err)
 	}
 
+	return nil
+}
+
+// replicateReference copies an image reference to another mirror.
+func (di *DefaultPromoterImplementation) replicateReference(opts *options.Options, srcRef, dstRef string) error {
 	if !opts.SignCheckFix {
 		logrus.Infof(" (NOOP) replicating %s to %s ", srcRef, dstRef)
 
@@ -371,7 +498,7 @@ func (di *DefaultPromoterImplementation) replicateReference(opts *options.Option
 
 	logrus.Infof(" replicating %s to %s ", srcRef, dstRef)
 
-	if err := crane.Copy(srcRef, dstRef, craneOpts...); err != nil {
+	if err := di.registryProvider.CopyImage(context.TODO(), srcRef, dstRef); err != nil {
 		return fmt.Errorf(
 			"copying signature %s to %s: %w", srcRef, dstRef, err,
 		)
@@ -401,7 +528,7 @@ func (di *DefaultPromoterImplementation) signReference(opts *options.Options, re
 
 	signOpts.IdentityToken = token
 
-	di.signer = sign.New(signOpts)
+	di.signer = di.signerFor(signOpts)
 
 	// Add an annotation recording the kpromo version to ensure we
 	// get a 2nd signature, otherwise cosign will not resign a signed image:
@@ -409,6 +536,10 @@ func (di *DefaultPromoterImplementation) signReference(opts *options.Options, re
 		"org.kubernetes.kpromo.version=kpromo-" + version.GetVersionInfo().GitVersion,
 	}
 
+	// Cosign talks to the registries with its own transport, so an
+	// insecure registry of the image has to be allowed explicitly.
+	signOpts.AllowInsecure = di.transports.InsecureReference(refString)
+
 	if _, err := di.signer.SignImageWithOptions(signOpts, refString); err != nil {
 		return fmt.Errorf("signing image %s: %w", refString, err)
 	}
@@ -416,15 +547,11 @@ func (di *DefaultPromoterImplementation) signReference(opts *options.Options, re
 	return nil
 }
 
-// readLatestImages returns the latest images uploaded to the registry.
-// Note that this function uses the google GCR/AR extensions so it will
-// not work on other non-GCP registries.
-func (di *DefaultPromoterImplementation) readLatestImages(opts *options.Options) ([]string, error) {
-	creds := google.WithAuthFromKeychain(authn.NewMultiKeychain(
-		authn.DefaultKeychain,
-		google.Keychain,
-	))
-
+// readLatestImages returns the latest images uploaded to the canonical
+// registry, along with the time they were uploaded. The registry is read
+// with the registry provider, so only registries reporting upload times,
+// like the GCR and Artifact Registry extensions, have latest images.
+func (di *DefaultPromoterImplementation) readLatestImages(opts *options.Options) ([]checkresults.Image, error) {
 	dateCutOff := time.Now().AddDate(0, 0, opts.SignCheckFromDays*-1)
 
 	dateCutOffTo := time.Now()
@@ -437,64 +564,64 @@ func (di *DefaultPromoterImplementation) readLatestImages(opts *options.Options)
 		dateCutOffTo.Local().Format(time.RFC822), //nolint:gosmopolitan // local timezone is intentional for human-readable log output
 	)
 
-	images := []string{}
+	canonical := image.Registry(opts.CanonicalRegistry())
 
-	repo, err := name.NewRepository(canonicalRegistry+"/"+productionRepositoryPath, name.WeakValidation)
+	inv, err := di.registryProvider.ReadRegistries(
+		context.Background(), []registry.RegistryConfig{{Name: canonical}}, true, nil,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("creating repo: %w", err)
+		return nil, fmt.Errorf("reading canonical registry: %w", err)
 	}
 
-	var mt sync.Mutex
+	images := []checkresults.Image{}
 
-	walkFn := func(repo name.Repository, tags *google.Tags, _ error) error {
-		if tags == nil {
-			return nil
+	for imageName, digestTags := range inv.Images[canonical] {
+		repo := string(canonical)
+		if imageName != "" {
+			repo += "/" + string(imageName)
 		}
 
 		// We ignore the -arch repositories as the promoter currently
 		// ignores them and does not sign them
-		if strings.HasSuffix(repo.String(), "-amd64") || strings.HasSuffix(repo.String(), "-arm") ||
-			strings.HasSuffix(repo.String(), "-arm64") || strings.HasSuffix(repo.String(), "-ppc64le") ||
-			strings.HasSuffix(repo.String(), "-s390x") {
-			return nil
+		if strings.HasSuffix(repo, "-amd64") || strings.HasSuffix(repo, "-arm") ||
+			strings.HasSuffix(repo, "-arm64") || strings.HasSuffix(repo, "-ppc64le") ||
+			strings.HasSuffix(repo, "-s390x") {
+			continue
 		}
 
-		logrus.Infof("Indexing %d images from %s", len(tags.Manifests), repo)
-		// First var (_) is the digest
-		for _, manifest := range tags.Manifests {
+		logrus.Infof("Indexing %d images from %s", len(digestTags), repo)
+
+		for digest, tags := range digestTags {
 			// Ignore if there are no tags
-			if len(manifest.Tags) == 0 {
+			if len(tags) == 0 {
 				continue
 			}
 			// Ignore signature tags
-			if strings.HasSuffix(manifest.Tags[0], ".sig") {
+			if strings.HasSuffix(string(tags[0]), ".sig") {
 				continue
 			}
 
+			uploaded := inv.Details[digest].Uploaded
+
 			// Ignore if uploaded before our date
-			if manifest.Uploaded.Before(dateCutOff) {
+			if uploaded.Before(dateCutOff) {
 				continue
 			}
 
-			if opts.SignCheckToDays > 0 && manifest.Uploaded.After(dateCutOffTo) {
+			if opts.SignCheckToDays > 0 && uploaded.After(dateCutOffTo) {
 				continue
 			}
 
-			mt.Lock()
-
-			images = append(images, strings.ReplaceAll(
-				fmt.Sprintf("%s:%s", repo, manifest.Tags[0]),
-				canonicalRegistry+"/"+productionRepositoryPath, consts.ProdRegistry),
-			)
-			mt.Unlock()
+			images = append(images, checkresults.Image{
+				Reference: fmt.Sprintf("%s:%s", publicIdentity(opts.IdentityRules(), repo), tags[0]),
+				Uploaded:  &uploaded,
+			})
 		}
-
-		return nil
 	}
 
-	if err := google.Walk(repo, walkFn, creds); err != nil {
-		return nil, fmt.Errorf("walking repo: %w", err)
-	}
+	sort.Slice(images, func(i, j int) bool {
+		return images[i].Reference < images[j].Reference
+	})
 
 	if opts.SignCheckMaxImages != 0 && len(images) > opts.SignCheckMaxImages {
 		images = images[0:opts.SignCheckMaxImages]
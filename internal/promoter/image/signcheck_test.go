@@ -17,10 +17,30 @@ limitations under the License.
 package imagepromoter
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/release-sdk/sign"
 
 	checkresults "sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry/registryfakes"
+	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
 func TestFixMissingSignaturesSkipsEmptyResults(t *testing.T) {
@@ -57,3 +77,267 @@ func TestFixMissingSignaturesSkipsSigned(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestGetMirrors(t *testing.T) {
+	manifest := filepath.Join(t.TempDir(), "promoter-manifest.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte(`registries:
+- name: gcr.io/k8s-staging-foo
+  src: true
+- name: us-central1-docker.pkg.dev/k8s-artifacts-prod/images/foo
+- name: europe-west1-docker.pkg.dev/k8s-artifacts-prod/images/foo/
+- name: us-central1-docker.pkg.dev/k8s-artifacts-prod/images/foo
+images:
+- name: bar
+  dmap:
+    "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": ["v1"]
+`), 0o600))
+
+	di := &DefaultPromoterImplementation{}
+
+	mirrors, err := di.getMirrors(&options.Options{Manifest: manifest})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"europe-west1-docker.pkg.dev/k8s-artifacts-prod/images/foo",
+		"us-central1-docker.pkg.dev/k8s-artifacts-prod/images/foo",
+	}, mirrors)
+
+	_, err = di.getMirrors(&options.Options{})
+	require.Error(t, err)
+}
+
+func TestMirrorSignatureReferences(t *testing.T) {
+	mirrors := []string{
+		"europe-west1-docker.pkg.dev/k8s-artifacts-prod/images",
+		"europe-west1-docker.pkg.dev/k8s-artifacts-prod/images/sig-storage",
+		"us-central1-docker.pkg.dev/k8s-artifacts-prod/images/sig-storage",
+		"gcr.io/some-other-prod",
+	}
+
+	ref, err := name.ParseReference("registry.k8s.io/sig-storage/csi-provisioner:v1.0.0")
+	require.NoError(t, err)
+
+	refs := mirrorSignatureReferences(
+		options.DefaultOptions.SignIdentityRules, mirrors, ref,
+		"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+	)
+
+	// The generic and subproject destination registries resolve to the
+	// same repository, so it is only checked once.
+	require.Equal(t, []string{
+		"europe-west1-docker.pkg.dev/k8s-artifacts-prod/images/sig-storage/csi-provisioner:sha256-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.sig",
+		"us-central1-docker.pkg.dev/k8s-artifacts-prod/images/sig-storage/csi-provisioner:sha256-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.sig",
+	}, refs)
+}
+
+// pushTestSignature pushes a cosign signature manifest to ref with a layer
+// carrying a self-signed certificate for identity.
+func pushTestSignature(t *testing.T, di *DefaultPromoterImplementation, ref, identity string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:   big.NewInt(1),
+		NotBefore:      time.Now(),
+		NotAfter:       time.Now().Add(time.Hour),
+		EmailAddresses: []string{identity},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	sigImage, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(
+			[]byte(`{"critical":{}}`),
+			"application/vnd.dev.cosign.simplesigning.v1+json",
+		),
+		Annotations: map[string]string{
+			"dev.sigstore.cosign/certificate": string(
+				pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			),
+		},
+	})
+	require.NoError(t, err)
+
+	r, err := name.ParseReference(ref)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(r, sigImage, remote.WithTransport(di.getTransport())))
+}
+
+func TestCheckSignatureLayers(t *testing.T) {
+	t.Parallel()
+
+	const (
+		identity = "signer@example.com"
+		digest   = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	)
+
+	host, di := newTLSTestRegistry(t)
+	sigTag := digestToSignatureTag(digest)
+
+	valid := host + "/valid/image:" + sigTag
+	invalid := host + "/invalid/image:" + sigTag
+	wrongIdentity := host + "/wrong/image:" + sigTag
+	missing := host + "/missing/image:" + sigTag
+
+	pushTestSignature(t, di, valid, identity)
+	pushTestSignature(t, di, invalid, identity)
+	pushTestSignature(t, di, wrongIdentity, "someone@example.com")
+
+	verifier := &fakeImageVerifier{
+		identity: identity,
+		signedBy: map[string]string{
+			signatureDigestReference(valid): identity,
+			// A signature with a certificate for the right identity
+			// which does not verify, e.g. a forged payload.
+			signatureDigestReference(invalid): "forged",
+		},
+	}
+	di.newVerifier = func(*sign.Options) ImageVerifier { return verifier }
+
+	opts := &options.Options{SignCheckIdentity: identity}
+
+	list, err := di.CheckSignatureLayers(opts, []string{missing, wrongIdentity, invalid, valid})
+	require.NoError(t, err)
+	require.Equal(t, checkresults.CheckList{
+		Signed:        []string{valid},
+		Missing:       []string{missing},
+		Invalid:       []string{invalid},
+		WrongIdentity: []string{wrongIdentity},
+	}, list)
+
+	require.Equal(t, []string{missing, invalid, wrongIdentity}, list.Unverified())
+
+	results := checkresults.Signature{"partial": list, "unsigned": {Invalid: []string{invalid}}}
+	require.Equal(t, 1, results.TotalPartial())
+	require.Equal(t, 1, results.TotalUnsigned())
+}
+
+func TestReplicateReference(t *testing.T) {
+	const (
+		src = "mirror1/image:sha256-abc.sig"
+		dst = "mirror2/image:sha256-abc.sig"
+	)
+
+	provider := registry.NewFakeProvider()
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	// Without SignCheckFix nothing is copied
+	require.NoError(t, di.replicateReference(&options.Options{}, src, dst))
+	require.Empty(t, provider.CopiedImages)
+
+	require.NoError(t, di.replicateReference(&options.Options{SignCheckFix: true}, src, dst))
+	require.Equal(t, []registry.CopyRecord{{Src: src, Dst: dst}}, provider.CopiedImages)
+}
+
+func TestIdentityMatcher(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"krel-trust@k8s-releng-prod.iam.gserviceaccount.com"}
+
+	for _, tc := range []struct {
+		opts     options.Options
+		expected bool
+	}{
+		{opts: options.Options{}, expected: true},
+		{opts: options.Options{SignCheckIdentity: names[0]}, expected: true},
+		{opts: options.Options{SignCheckIdentity: "other@example.com"}, expected: false},
+		{opts: options.Options{SignCheckIdentityRegexp: `^krel-.*\.iam\.gserviceaccount\.com$`}, expected: true},
+		{opts: options.Options{SignCheckIdentityRegexp: `^other@`}, expected: false},
+	} {
+		matcher, err := newIdentityMatcher(&tc.opts)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, matcher.matches(names), tc.opts)
+	}
+
+	_, err := newIdentityMatcher(&options.Options{SignCheckIdentityRegexp: `(`})
+	require.Error(t, err)
+}
+
+func TestFixSignaturesRecordsActions(t *testing.T) {
+	di := &DefaultPromoterImplementation{}
+
+	// Without SignCheckFix the actions are only planned
+	opts := &options.Options{}
+
+	results := checkresults.Signature{
+		"example.com/image:v1": checkresults.CheckList{
+			Signed:        []string{"mirror1/image:sha256-abc.sig"},
+			Missing:       []string{"mirror2/image:sha256-abc.sig"},
+			WrongIdentity: []string{"mirror3/image:sha256-abc.sig"},
+		},
+		"example.com/other:v1": checkresults.CheckList{
+			Invalid: []string{"mirror1/other:sha256-def.sig", "mirror2/other:sha256-def.sig"},
+		},
+	}
+
+	require.NoError(t, di.FixMissingSignatures(opts, results))
+	require.NoError(t, di.FixPartialSignatures(opts, results))
+
+	require.Equal(t, []checkresults.Action{
+		{Type: checkresults.ActionReplicate, Source: "mirror1/image:sha256-abc.sig", Target: "mirror2/image:sha256-abc.sig"},
+		{Type: checkresults.ActionReplicate, Source: "mirror1/image:sha256-abc.sig", Target: "mirror3/image:sha256-abc.sig"},
+	}, results["example.com/image:v1"].Actions)
+
+	require.Equal(t, []checkresults.Action{
+		{Type: checkresults.ActionSign, Target: "mirror1/other@sha256:def"},
+		{Type: checkresults.ActionReplicate, Source: "mirror1/other:sha256-def.sig", Target: "mirror2/other:sha256-def.sig"},
+	}, results["example.com/other:v1"].Actions)
+}
+
+func TestReadLatestImages(t *testing.T) {
+	t.Parallel()
+
+	const canonical = "us-central1-docker.pkg.dev/k8s-artifacts-prod/images"
+
+	var (
+		recent   = image.Digest("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+		old      = image.Digest("sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+		sig      = image.Digest("sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc")
+		arch     = image.Digest("sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd")
+		untagged = image.Digest("sha256:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+	)
+
+	uploaded := time.Now().Add(-time.Hour)
+
+	inv := registry.NewInventory()
+	inv.Images[canonical] = registry.RegInvImage{
+		"kube-apiserver": {
+			recent:   {"v1.30.0"},
+			old:      {"v1.29.0"},
+			sig:      {"sha256-aaaa.sig"},
+			untagged: {},
+		},
+		"kube-apiserver-amd64": {arch: {"v1.30.0"}},
+	}
+	inv.Details[recent] = registry.ManifestDetails{Uploaded: uploaded}
+	inv.Details[old] = registry.ManifestDetails{Uploaded: time.Now().AddDate(0, 0, -30)}
+	inv.Details[sig] = registry.ManifestDetails{Uploaded: uploaded}
+	inv.Details[arch] = registry.ManifestDetails{Uploaded: uploaded}
+	inv.Details[untagged] = registry.ManifestDetails{Uploaded: uploaded}
+
+	provider := &registryfakes.FakeProvider{}
+	provider.ReadRegistriesReturns(inv, nil)
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	opts := &options.Options{
+		SignCanonicalRegistry: canonical,
+		SignCheckFromDays:     5,
+		SignIdentityRules:     options.DefaultOptions.SignIdentityRules,
+	}
+
+	images, err := di.readLatestImages(opts)
+	require.NoError(t, err)
+	require.Equal(t, []checkresults.Image{
+		{Reference: "registry.k8s.io/kube-apiserver:v1.30.0", Uploaded: &uploaded},
+	}, images)
+
+	// The canonical registry is read through the registry provider
+	require.Equal(t, 1, provider.ReadRegistriesCallCount())
+
+	_, registries, recurse, _ := provider.ReadRegistriesArgsForCall(0)
+	require.Equal(t, []registry.RegistryConfig{{Name: canonical}}, registries)
+	require.True(t, recurse)
+}
@@ -21,12 +21,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
-	"github.com/google/go-containerregistry/pkg/crane"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	cr "github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/yaml"
 
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
@@ -35,22 +41,113 @@ import (
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
-// Snapshot runs a snapshot.
-func (di *DefaultPromoterImplementation) Snapshot(opts *options.Options, rii registry.RegInvImage) error {
+// Snapshot runs a snapshot. The snapshot is printed to stdout or written to
+// opts.OutputFile, or written as a thin manifest images tree when
+// opts.SnapshotThinManifestDir is set. The manifest details of the images
+// are only rendered in detailed snapshots.
+func (di *DefaultPromoterImplementation) Snapshot(
+	opts *options.Options, rii registry.RegInvImage, details map[image.Digest]registry.ManifestDetails,
+) error {
+	if opts.SnapshotThinManifestDir != "" {
+		srcRegistry, err := di.GetSnapshotSourceRegistry(opts)
+		if err != nil {
+			return fmt.Errorf("getting snapshot source registry: %w", err)
+		}
+
+		return writeThinManifestImages(
+			opts.SnapshotThinManifestDir, path.Base(string(srcRegistry.Name)), rii,
+		)
+	}
+
 	// Run the snapshot
-	var snapshot string
+	snapshot, err := di.renderSnapshot(opts, rii, details)
+	if err != nil {
+		return err
+	}
+
+	if opts.OutputFile == "" {
+		fmt.Println(snapshot)
+
+		return nil
+	}
 
-	switch strings.ToLower(opts.OutputFormat) {
+	if err := os.WriteFile(opts.OutputFile, []byte(snapshot+"\n"), 0o644); err != nil { //nolint:gosec // file permissions are intentional
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+
+	logrus.Infof("Wrote snapshot to %s", opts.OutputFile)
+
+	return nil
+}
+
+// renderSnapshot encodes a snapshot in the configured output format.
+func (di *DefaultPromoterImplementation) renderSnapshot(
+	opts *options.Options, rii registry.RegInvImage, details map[image.Digest]registry.ManifestDetails,
+) (string, error) {
+	format := strings.ToLower(opts.OutputFormat)
+
+	if opts.SnapshotDetails {
+		var (
+			data []byte
+			err  error
+		)
+
+		detailed := rii.ToDetailed(details)
+
+		switch format {
+		case "json":
+			data, err = json.MarshalIndent(detailed, "", "  ")
+		case "yaml":
+			data, err = yaml.Marshal(detailed)
+		default:
+			return "", fmt.Errorf("invalid detailed snapshot output format: %s", opts.OutputFormat)
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("encoding detailed snapshot: %w", err)
+		}
+
+		return strings.TrimSuffix(string(data), "\n"), nil
+	}
+
+	switch format {
 	case "csv":
-		snapshot = rii.ToCSV()
+		return rii.ToCSV(), nil
+	case "json":
+		snapshot, err := rii.ToJSON()
+		if err != nil {
+			return "", fmt.Errorf("encoding snapshot: %w", err)
+		}
+
+		return snapshot, nil
 	case "yaml":
-		snapshot = rii.ToYAML(registry.YamlMarshalingOpts{})
+		return rii.ToYAML(registry.YamlMarshalingOpts{}), nil
 	default:
 		// In the previous cli/run it took any malformed format string. Now we err.
-		return fmt.Errorf("invalid snapshot output format: %s", opts.OutputFormat)
+		return "", fmt.Errorf("invalid snapshot output format: %s", opts.OutputFormat)
 	}
+}
 
-	fmt.Println(snapshot)
+// writeThinManifestImages writes the images of a snapshot to
+// <dir>/images/<project>/images.yaml, splitting them into projects by the
+// first path element of their names. Images without one are written to
+// defaultProject.
+func writeThinManifestImages(dir, defaultProject string, rii registry.RegInvImage) error {
+	for project, images := range rii.SplitByPrefix(defaultProject) {
+		projectDir := filepath.Join(dir, "images", project)
+		if err := os.MkdirAll(projectDir, 0o755); err != nil {
+			return fmt.Errorf("creating images directory: %w", err)
+		}
+
+		imagesPath := filepath.Join(projectDir, "images.yaml")
+
+		data := images.ToYAML(registry.YamlMarshalingOpts{})
+		if err := os.WriteFile(imagesPath, []byte(data), 0o644); err != nil { //nolint:gosec // file permissions are intentional
+			return fmt.Errorf("writing images file: %w", err)
+		}
+
+		logrus.Infof("Wrote %d images to %s", len(images), imagesPath)
+	}
 
 	return nil
 }
@@ -123,15 +220,22 @@ func (di *DefaultPromoterImplementation) AppendManifestToSnapshot(
 		return nil, fmt.Errorf("parsing specified manifest: %w", err)
 	}
 
+	if err := di.addRegistries(opts, []schema.Manifest{mfest}); err != nil {
+		return nil, err
+	}
+
 	return append(mfests, mfest), nil
 }
 
+// GetRegistryImageInventory reads the images of the snapshot registry. With
+// opts.SnapshotDetails, the manifest details of the images are returned as
+// well.
 func (di *DefaultPromoterImplementation) GetRegistryImageInventory(
 	ctx context.Context, opts *options.Options, mfests []schema.Manifest,
-) (registry.RegInvImage, error) {
+) (registry.RegInvImage, map[image.Digest]registry.ManifestDetails, error) {
 	srcRegistry, err := di.GetSnapshotSourceRegistry(opts)
 	if err != nil {
-		return nil, fmt.Errorf("creating source registry for image inventory: %w", err)
+		return nil, nil, fmt.Errorf("creating source registry for image inventory: %w", err)
 	}
 
 	registryConfig := registry.RegistryConfigFromContext(*srcRegistry)
@@ -139,7 +243,7 @@ func (di *DefaultPromoterImplementation) GetRegistryImageInventory(
 	if opts.ManifestBasedSnapshotOf != "" {
 		edges, err := promotion.ToEdges(mfests)
 		if err != nil {
-			return nil, fmt.Errorf("converting list of manifests to edges for promotion: %w", err)
+			return nil, nil, fmt.Errorf("converting list of manifests to edges for promotion: %w", err)
 		}
 
 		// Create the registry inventory from manifest edges
@@ -148,7 +252,9 @@ func (di *DefaultPromoterImplementation) GetRegistryImageInventory(
 			opts.ManifestBasedSnapshotOf,
 		)
 
-		if opts.MinimalSnapshot {
+		var details map[image.Digest]registry.ManifestDetails
+
+		if opts.MinimalSnapshot || opts.SnapshotDetails {
 			inv, err := di.registryProvider.ReadRegistries(
 				ctx,
 				[]registry.RegistryConfig{registryConfig},
@@ -156,13 +262,19 @@ func (di *DefaultPromoterImplementation) GetRegistryImageInventory(
 				nil,
 			)
 			if err != nil {
-				return nil, fmt.Errorf("reading registry for minimal snapshot: %w", err)
+				return nil, nil, fmt.Errorf("reading registry for snapshot: %w", err)
+			}
+
+			if opts.MinimalSnapshot {
+				rii = di.removeChildDigests(ctx, inv, rii, srcRegistry.Name)
 			}
 
-			rii = removeChildDigests(inv, rii, srcRegistry.Name, di.craneOptions()...)
+			if opts.SnapshotDetails {
+				details = di.collectManifestDetails(ctx, opts, inv, rii, srcRegistry.Name)
+			}
 		}
 
-		return rii, nil
+		return rii, details, nil
 	}
 
 	// Direct snapshot path: read the registry
@@ -173,7 +285,7 @@ func (di *DefaultPromoterImplementation) GetRegistryImageInventory(
 		nil,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("reading registries: %w", err)
+		return nil, nil, fmt.Errorf("reading registries: %w", err)
 	}
 
 	rii := inv.Images[mfests[0].Registries[0].Name]
@@ -184,21 +296,109 @@ func (di *DefaultPromoterImplementation) GetRegistryImageInventory(
 	if opts.MinimalSnapshot {
 		logrus.Info("removing tagless child digests of manifest lists")
 
-		rii = removeChildDigests(inv, rii, mfests[0].Registries[0].Name, di.craneOptions()...)
+		rii = di.removeChildDigests(ctx, inv, rii, mfests[0].Registries[0].Name)
+	}
+
+	if !opts.SnapshotDetails {
+		return rii, nil, nil
+	}
+
+	return rii, di.collectManifestDetails(ctx, opts, inv, rii, mfests[0].Registries[0].Name), nil
+}
+
+// collectManifestDetails returns the manifest details recorded in the
+// inventory for the digests of rii, adding the platforms of image indexes
+// read from the registry. Images of unknown platform, like the attestation
+// manifests of BuildKit indexes, are not listed as platforms.
+func (di *DefaultPromoterImplementation) collectManifestDetails(
+	ctx context.Context,
+	opts *options.Options,
+	inv *registry.Inventory,
+	rii registry.RegInvImage,
+	registryName image.Registry,
+) map[image.Digest]registry.ManifestDetails {
+	details := map[image.Digest]registry.ManifestDetails{}
+
+	var mu sync.Mutex
+
+	g := new(errgroup.Group)
+	g.SetLimit(concurrencyLimit(opts.Threads))
+
+	for imageName, digestTags := range rii {
+		for digest := range digestTags {
+			md, ok := inv.Details[digest]
+			if !ok {
+				md = registry.ManifestDetails{MediaType: inv.MediaTypes[digest]}
+			}
+
+			if md.MediaType != cr.DockerManifestList && md.MediaType != cr.OCIImageIndex {
+				details[digest] = md
+
+				continue
+			}
+
+			ref := fmt.Sprintf("%s/%s@%s", registryName, imageName, digest)
+
+			g.Go(func() error {
+				platforms, err := di.indexPlatforms(ctx, ref)
+				if err != nil {
+					logrus.Warnf("Failed to read the platforms of %s: %v", ref, err)
+				}
+
+				md.Platforms = platforms
+
+				mu.Lock()
+				details[digest] = md
+				mu.Unlock()
+
+				return nil
+			})
+		}
+	}
+
+	// The goroutines log their errors and never fail
+	_ = g.Wait()
+
+	return details
+}
+
+// indexPlatforms returns the platforms of the images of an image index.
+func (di *DefaultPromoterImplementation) indexPlatforms(ctx context.Context, ref string) ([]string, error) {
+	rawManifest, _, err := di.registryProvider.GetManifest(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest list: %w", err)
+	}
+
+	var idx v1.IndexManifest
+	if err := json.Unmarshal(rawManifest, &idx); err != nil {
+		return nil, fmt.Errorf("parsing manifest list: %w", err)
+	}
+
+	platforms := []string{}
+
+	for i := range idx.Manifests {
+		p := idx.Manifests[i].Platform
+		if p == nil || p.OS == "" || p.OS == "unknown" {
+			continue
+		}
+
+		if platform := p.String(); !slices.Contains(platforms, platform) {
+			platforms = append(platforms, platform)
+		}
 	}
 
-	return rii, nil
+	return platforms, nil
 }
 
 // removeChildDigests filters out tagless entries from rii that are children
 // of manifest lists. It uses the media types from the inventory to identify
 // manifest list digests, fetches their manifests to find child digests,
 // and removes tagless children.
-func removeChildDigests(
+func (di *DefaultPromoterImplementation) removeChildDigests(
+	ctx context.Context,
 	inv *registry.Inventory,
 	rii registry.RegInvImage,
 	registryName image.Registry,
-	opts ...crane.Option,
 ) registry.RegInvImage {
 	// Build a set of child digests by reading manifest lists from the registry.
 	childDigests := make(map[image.Digest]bool)
@@ -214,7 +414,7 @@ func removeChildDigests(
 			// Fetch the manifest list to get child digests
 			ref := fmt.Sprintf("%s/%s@%s", registryName, imageName, digest)
 
-			rawManifest, err := crane.Manifest(ref, opts...)
+			rawManifest, _, err := di.registryProvider.GetManifest(ctx, ref)
 			if err != nil {
 				logrus.Warnf("failed to read manifest list %s: %v", ref, err)
 
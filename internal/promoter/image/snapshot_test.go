@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+func TestSnapshotOutputFile(t *testing.T) {
+	const digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	rii := registry.RegInvImage{"foo/bar": {digest: {"v1.0"}}}
+	di := &DefaultPromoterImplementation{}
+
+	for _, format := range []string{"csv", "json", "yaml"} {
+		opts := &options.Options{
+			Snapshot:     "gcr.io/k8s-staging-foo",
+			OutputFormat: format,
+			OutputFile:   filepath.Join(t.TempDir(), "snapshot"),
+		}
+		require.NoError(t, di.Snapshot(opts, rii, nil), format)
+
+		data, err := os.ReadFile(opts.OutputFile)
+		require.NoError(t, err, format)
+		require.Contains(t, string(data), digest, format)
+	}
+
+	opts := &options.Options{Snapshot: "gcr.io/k8s-staging-foo", OutputFormat: "xml"}
+	require.Error(t, di.Snapshot(opts, rii, nil))
+}
+
+func TestSnapshotThinManifestDir(t *testing.T) {
+	const digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	rii := registry.RegInvImage{
+		"foo/bar": {digest: {"v1.0"}},
+		"baz":     {digest: {"v2.0"}},
+	}
+
+	dir := t.TempDir()
+	opts := &options.Options{Snapshot: "gcr.io/k8s-staging-qux", SnapshotThinManifestDir: dir}
+
+	di := &DefaultPromoterImplementation{}
+	require.NoError(t, di.Snapshot(opts, rii, nil))
+
+	images, err := schema.ParseImagesFromFile(filepath.Join(dir, "images", "foo", "images.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, registry.Images{{Name: "bar", Dmap: registry.DigestTags{digest: {"v1.0"}}}}, images)
+
+	images, err = schema.ParseImagesFromFile(filepath.Join(dir, "images", "k8s-staging-qux", "images.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, registry.Images{{Name: "baz", Dmap: registry.DigestTags{digest: {"v2.0"}}}}, images)
+}
+
+func TestSnapshotDetails(t *testing.T) {
+	const host = "gcr.io"
+
+	di := &DefaultPromoterImplementation{}
+
+	idx := mutate.IndexMediaType(empty.Index, cr.OCIImageIndex)
+
+	for _, platform := range []v1.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		{OS: "unknown", Architecture: "unknown"},
+	} {
+		img, err := random.Image(1024, 1)
+		require.NoError(t, err)
+
+		idx = mutate.AppendManifests(idx, mutate.IndexAddendum{
+			Add:        img,
+			Descriptor: v1.Descriptor{Platform: &platform},
+		})
+	}
+
+	idxDigest, err := idx.Digest()
+	require.NoError(t, err)
+
+	rawIndex, err := idx.RawManifest()
+	require.NoError(t, err)
+
+	const imageDigest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	uploaded := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	provider := registry.NewFakeProvider()
+	provider.AddImage(image.Registry(host+"/staging"), "foo", image.Digest(idxDigest.String()), "v1.0")
+	provider.AddImage(image.Registry(host+"/staging"), "bar", imageDigest, "v2.0")
+	provider.AddManifest(image.Digest(idxDigest.String()), cr.OCIImageIndex, rawIndex)
+	provider.Inventory.Details[imageDigest] = registry.ManifestDetails{
+		MediaType: cr.OCIManifestSchema1,
+		Size:      1234,
+		Uploaded:  uploaded,
+	}
+	di.registryProvider = provider
+
+	opts := &options.Options{
+		Snapshot:        host + "/staging",
+		SnapshotDetails: true,
+		OutputFormat:    "json",
+		OutputFile:      filepath.Join(t.TempDir(), "snapshot.json"),
+	}
+
+	mfests, err := di.GetSnapshotManifests(opts)
+	require.NoError(t, err)
+
+	rii, details, err := di.GetRegistryImageInventory(context.Background(), opts, mfests)
+	require.NoError(t, err)
+	require.NoError(t, di.Snapshot(opts, rii, details))
+
+	data, err := os.ReadFile(opts.OutputFile)
+	require.NoError(t, err)
+
+	snapshot := []registry.DetailedImage{}
+	require.NoError(t, json.Unmarshal(data, &snapshot))
+	require.Len(t, snapshot, 2)
+
+	require.Equal(t, image.Name("bar"), snapshot[0].Name)
+	require.Equal(t, int64(1234), snapshot[0].Digests[0].Size)
+	require.Equal(t, uploaded, *snapshot[0].Digests[0].Uploaded)
+	require.Nil(t, snapshot[0].Digests[0].Created)
+
+	require.Equal(t, image.Name("foo"), snapshot[1].Name)
+	require.Equal(t, string(cr.OCIImageIndex), snapshot[1].Digests[0].MediaType)
+	require.Equal(t, []string{"linux/amd64", "linux/arm64/v8"}, snapshot[1].Digests[0].Platforms)
+	require.Equal(t, registry.TagSlice{"v1.0"}, snapshot[1].Digests[0].Tags)
+}
+
+func TestRemoveChildDigests(t *testing.T) {
+	const (
+		index    = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		amd64    = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		arm64    = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+		broken   = "sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd"
+		orphan   = "sha256:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+		unlisted = "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	)
+
+	provider := registry.NewFakeProvider()
+	provider.AddIndex("gcr.io/staging", "foo", index, []registry.IndexChild{
+		{Digest: amd64, Platform: "linux/amd64"},
+		{Digest: arm64, Platform: "linux/arm64"},
+	}, "v1.0")
+	provider.AddImage("gcr.io/staging", "foo", arm64, "v1.0-arm64")
+	provider.AddImage("gcr.io/staging", "foo", orphan)
+	provider.AddIndex("gcr.io/staging", "bar", broken, []registry.IndexChild{{Digest: unlisted}})
+	provider.GetManifestErrs = map[string]error{"gcr.io/staging/bar": errors.New("denied")}
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	rii := di.removeChildDigests(
+		context.Background(), provider.Inventory,
+		provider.Inventory.Images["gcr.io/staging"], "gcr.io/staging",
+	)
+
+	// Tagless children are removed, unless their index cannot be read
+	require.Equal(t, registry.RegInvImage{
+		"foo": {index: {"v1.0"}, arm64: {"v1.0-arm64"}, orphan: nil},
+		"bar": {broken: nil, unlisted: nil},
+	}, rii)
+	require.ElementsMatch(t, [][]string{
+		{"gcr.io/staging/foo@" + index},
+		{"gcr.io/staging/bar@" + broken},
+	}, provider.CallsTo("GetManifest"))
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/retention"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// PlanStagingGC reads the staging registry set in the options recursively
+// and applies the retention policy of the options to its images. Every
+// digest declared in the manifests counts as promoted and is kept.
+func (di *DefaultPromoterImplementation) PlanStagingGC(
+	ctx context.Context, opts *options.Options, mfests []schema.Manifest,
+) (*retention.Report, error) {
+	stagingRegistry := image.Registry(strings.TrimRight(opts.StagingGCRegistry, "/"))
+	if stagingRegistry == "" {
+		return nil, errors.New("no staging registry set")
+	}
+
+	promoted := map[image.Digest]bool{}
+
+	for _, mfest := range mfests {
+		for _, img := range mfest.Images {
+			for digest := range img.Dmap {
+				promoted[digest] = true
+			}
+		}
+	}
+
+	inv, err := di.registryProvider.ReadRegistries(
+		ctx, []registry.RegistryConfig{{Name: stagingRegistry, Src: true}}, true, nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading staging registry: %w", err)
+	}
+
+	rii := inv.Images[stagingRegistry]
+
+	children, err := di.indexChildren(ctx, opts, stagingRegistry, rii, inv.MediaTypes)
+	if err != nil {
+		return nil, fmt.Errorf("reading image indexes: %w", err)
+	}
+
+	report := retention.Plan(
+		retention.Policy{KeepTags: opts.StagingGCKeepTags, KeepDays: opts.StagingGCKeepDays},
+		stagingRegistry, rii, inv.Details, promoted, children, time.Now(),
+	)
+
+	logrus.Infof(
+		"Retention policy keeps %d digests of %s, %d can be deleted",
+		report.Kept, stagingRegistry, len(report.Deletable),
+	)
+
+	return report, nil
+}
+
+// indexChildren reads the image indexes of rii and returns their children.
+// Unlike other reads, a failure is an error: not knowing the children of an
+// index could get them deleted.
+func (di *DefaultPromoterImplementation) indexChildren(
+	ctx context.Context,
+	opts *options.Options,
+	registryName image.Registry,
+	rii registry.RegInvImage,
+	mediaTypes map[image.Digest]cr.MediaType,
+) (map[image.Digest][]image.Digest, error) {
+	children := map[image.Digest][]image.Digest{}
+
+	var mu sync.Mutex
+
+	g := new(errgroup.Group)
+	g.SetLimit(concurrencyLimit(opts.Threads))
+
+	for name, dmap := range rii {
+		for digest := range dmap {
+			if mt := mediaTypes[digest]; mt != cr.DockerManifestList && mt != cr.OCIImageIndex {
+				continue
+			}
+
+			ref := path.Join(string(registryName), string(name)) + "@" + string(digest)
+
+			g.Go(func() error {
+				rawManifest, _, err := di.registryProvider.GetManifest(ctx, ref)
+				if err != nil {
+					return fmt.Errorf("reading manifest list %s: %w", ref, err)
+				}
+
+				var idx v1.IndexManifest
+				if err := json.Unmarshal(rawManifest, &idx); err != nil {
+					return fmt.Errorf("parsing manifest list %s: %w", ref, err)
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				for i := range idx.Manifests {
+					children[digest] = append(children[digest], image.Digest(idx.Manifests[i].Digest.String()))
+				}
+
+				return nil
+			})
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err //nolint:wrapcheck // errors are wrapped in the goroutines
+	}
+
+	return children, nil
+}
+
+// DeleteStagingImages deletes the tags and manifests of the deletable
+// digests of the report, and records the outcome in it. Image indexes are
+// deleted before the other digests, which may be their children.
+func (di *DefaultPromoterImplementation) DeleteStagingImages(
+	ctx context.Context, opts *options.Options, report *retention.Report,
+) error {
+	for _, indexes := range []bool{true, false} {
+		g := new(errgroup.Group)
+		g.SetLimit(concurrencyLimit(opts.Threads))
+
+		for i := range report.Deletable {
+			d := &report.Deletable[i]
+			if d.IsIndex() != indexes {
+				continue
+			}
+
+			g.Go(func() error {
+				repo := path.Join(string(report.Registry), string(d.Image))
+
+				for _, tag := range d.Tags {
+					if err := di.registryProvider.DeleteTag(ctx, repo+":"+string(tag)); err != nil {
+						logrus.Errorf("Deleting %s:%s: %v", repo, tag, err)
+
+						d.Error = err.Error()
+
+						return nil
+					}
+				}
+
+				if err := di.registryProvider.DeleteManifest(ctx, repo+"@"+string(d.Digest)); err != nil {
+					logrus.Errorf("Deleting %s@%s: %v", repo, d.Digest, err)
+
+					d.Error = err.Error()
+
+					return nil
+				}
+
+				d.Deleted = true
+
+				return nil
+			})
+		}
+
+		if err := g.Wait(); err != nil {
+			return fmt.Errorf("deleting staging images: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteStagingGCReport writes the staging gc report to stdout or
+// opts.OutputFile in the format configured in the options.
+func (di *DefaultPromoterImplementation) WriteStagingGCReport(opts *options.Options, report *retention.Report) error {
+	return writeReport(opts.OutputFile, "staging gc report", report, opts.StagingGCOutput)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imagepromoter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/retention"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+)
+
+func TestPlanAndDeleteStagingImages(t *testing.T) {
+	const (
+		digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		digestC = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	)
+
+	old := registry.ManifestDetails{Uploaded: time.Now().AddDate(-1, 0, 0)}
+
+	provider := registry.NewFakeProvider()
+	provider.AddImage("gcr.io/staging", "foo", digestA, "v1.0")
+	provider.AddImage("gcr.io/staging", "foo", digestB, "v0.9")
+	provider.AddImage("gcr.io/staging", "foo", digestC)
+	provider.Inventory.Details[digestA] = old
+	provider.Inventory.Details[digestB] = old
+	provider.Inventory.Details[digestC] = registry.ManifestDetails{Uploaded: time.Now()}
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	mfests := []schema.Manifest{{
+		Registries: []registry.Context{{Name: "gcr.io/staging", Src: true}, {Name: "gcr.io/prod"}},
+		Images:     []registry.Image{{Name: "foo", Dmap: registry.DigestTags{digestA: {"v1.0"}}}},
+	}}
+
+	opts := &options.Options{StagingGCRegistry: "gcr.io/staging/", StagingGCKeepDays: 30}
+
+	report, err := di.PlanStagingGC(context.Background(), opts, mfests)
+	require.NoError(t, err)
+	require.Equal(t, 2, report.Kept)
+	require.Len(t, report.Deletable, 1)
+	require.EqualValues(t, digestB, report.Deletable[0].Digest)
+
+	require.NoError(t, di.DeleteStagingImages(context.Background(), opts, report))
+	require.Equal(t, []string{
+		"gcr.io/staging/foo:v0.9",
+		"gcr.io/staging/foo@" + digestB,
+	}, provider.DeletedRefs)
+	require.True(t, report.Deletable[0].Deleted)
+	require.NoError(t, report.Err())
+
+	_, err = di.PlanStagingGC(context.Background(), &options.Options{}, mfests)
+	require.Error(t, err)
+}
+
+func TestDeleteStagingImagesIndexesFirst(t *testing.T) {
+	provider := registry.NewFakeProvider()
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	report := &retention.Report{
+		Registry: "gcr.io/staging",
+		Deletable: []retention.Digest{
+			{Image: "foo", Digest: "sha256:child"},
+			{Image: "foo", Digest: "sha256:index", MediaType: cr.OCIImageIndex},
+		},
+	}
+
+	require.NoError(t, di.DeleteStagingImages(context.Background(), &options.Options{Threads: 1}, report))
+	require.Equal(t, []string{
+		"gcr.io/staging/foo@sha256:index",
+		"gcr.io/staging/foo@sha256:child",
+	}, provider.DeletedRefs)
+
+	// Failed deletions are recorded in the report
+	provider.DeleteErr = errors.New("denied")
+	report.Deletable[0].Deleted = false
+
+	require.NoError(t, di.DeleteStagingImages(context.Background(), &options.Options{}, report))
+	require.False(t, report.Deletable[0].Deleted)
+	require.Contains(t, report.Deletable[0].Error, "denied")
+	require.ErrorContains(t, report.Err(), "2 of 2 digests could not be deleted")
+}
+
+func TestDeleteStagingImagesPartialFailure(t *testing.T) {
+	provider := registry.NewFakeProvider()
+	provider.DeleteErrs = map[string]error{"gcr.io/staging/bar": errors.New("denied")}
+
+	di := &DefaultPromoterImplementation{registryProvider: provider}
+
+	report := &retention.Report{
+		Registry: "gcr.io/staging",
+		Deletable: []retention.Digest{
+			{Image: "foo", Digest: "sha256:foo", Tags: registry.TagSlice{"v1.0"}},
+			{Image: "bar", Digest: "sha256:bar", Tags: registry.TagSlice{"v1.0"}},
+		},
+	}
+
+	// A failed deletion does not stop the others, and leaves the digest of
+	// a failed tag deletion alone
+	require.NoError(t, di.DeleteStagingImages(context.Background(), &options.Options{Threads: 2}, report))
+	require.True(t, report.Deletable[0].Deleted)
+	require.False(t, report.Deletable[1].Deleted)
+	require.Contains(t, report.Deletable[1].Error, "denied")
+	require.ElementsMatch(t, [][]string{
+		{"gcr.io/staging/foo:v1.0"},
+		{"gcr.io/staging/bar:v1.0"},
+	}, provider.CallsTo("DeleteTag"))
+	require.Equal(t, [][]string{{"gcr.io/staging/foo@sha256:foo"}}, provider.CallsTo("DeleteManifest"))
+	require.ErrorContains(t, report.Err(), "1 of 2 digests could not be deleted: gcr.io/staging/bar@sha256:bar")
+}
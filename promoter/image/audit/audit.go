@@ -0,0 +1,215 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// Kind is the kind of an audit finding.
+type Kind string
+
+// Kinds of audit findings.
+const (
+	// KindUnknownDigest is a digest found in a registry that no manifest
+	// promotes to the image.
+	KindUnknownDigest Kind = "unknown-digest"
+
+	// KindUnknownTag is a tag of a promoted digest that no manifest
+	// declares for the image.
+	KindUnknownTag Kind = "unknown-tag"
+
+	// KindTagMismatch is a tag pointing to a different digest than the one
+	// the manifests declare for it.
+	KindTagMismatch Kind = "tag-mismatch"
+)
+
+// Report is the outcome of auditing the destination registries against the
+// images the manifests promote to them.
+type Report struct {
+	// Registries are the audited registries, sorted by name.
+	Registries []image.Registry `json:"registries"`
+
+	// Findings lists the images not accounted for by the manifests, sorted
+	// by registry, image, digest and tag.
+	Findings []Finding `json:"findings"`
+}
+
+// Finding is an image in a registry that the manifests do not account for.
+type Finding struct {
+	Kind     Kind           `json:"kind"`
+	Registry image.Registry `json:"registry"`
+	Image    image.Name     `json:"image"`
+	Digest   image.Digest   `json:"digest"`
+
+	// Tags are the tags of an unknown digest.
+	Tags registry.TagSlice `json:"tags,omitempty"`
+
+	// Tag is the unknown or mismatched tag.
+	Tag image.Tag `json:"tag,omitempty"`
+
+	// ExpectedDigest is the digest the manifests declare for a mismatched
+	// tag.
+	ExpectedDigest image.Digest `json:"expectedDigest,omitempty"`
+}
+
+// Compare audits the actual images of each registry against the expected
+// ones, both keyed by registry. Digests in ignored are the children of
+// image indexes and the artifacts referring to images, which are pushed
+// along with the promoted images but not declared in the manifests; they
+// are only reported when tagged. Cosign signature, attestation and SBOM
+// tags are never reported.
+func Compare(
+	expected, actual map[image.Registry]registry.RegInvImage, ignored map[image.Digest]bool,
+) *Report {
+	report := &Report{
+		Registries: make([]image.Registry, 0, len(expected)),
+		Findings:   []Finding{},
+	}
+
+	for reg := range expected {
+		report.Registries = append(report.Registries, reg)
+	}
+
+	sort.Slice(report.Registries, func(i, j int) bool {
+		return report.Registries[i] < report.Registries[j]
+	})
+
+	for _, reg := range report.Registries {
+		for name, dmap := range actual[reg] {
+			want := expected[reg][name]
+			wantTags := tagDigests(want)
+
+			for digest, tags := range dmap {
+				tags = withoutCosignTags(tags)
+
+				_, known := want[digest]
+
+				if !known {
+					if len(tags) == 0 && ignored[digest] {
+						continue
+					}
+
+					report.Findings = append(report.Findings, Finding{
+						Kind:     KindUnknownDigest,
+						Registry: reg,
+						Image:    name,
+						Digest:   digest,
+						Tags:     tags.ToTagSet().ToSorted(),
+					})
+				}
+
+				for _, tag := range tags {
+					wantDigest, declared := wantTags[tag]
+
+					switch {
+					case declared && wantDigest != digest:
+						report.Findings = append(report.Findings, Finding{
+							Kind:           KindTagMismatch,
+							Registry:       reg,
+							Image:          name,
+							Digest:         digest,
+							Tag:            tag,
+							ExpectedDigest: wantDigest,
+						})
+					case !declared && known:
+						report.Findings = append(report.Findings, Finding{
+							Kind:     KindUnknownTag,
+							Registry: reg,
+							Image:    name,
+							Digest:   digest,
+							Tag:      tag,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+
+		switch {
+		case a.Registry != b.Registry:
+			return a.Registry < b.Registry
+		case a.Image != b.Image:
+			return a.Image < b.Image
+		case a.Digest != b.Digest:
+			return a.Digest < b.Digest
+		case a.Tag != b.Tag:
+			return a.Tag < b.Tag
+		default:
+			return a.Kind < b.Kind
+		}
+	})
+
+	return report
+}
+
+// tagDigests maps each tag of dmap to its digest.
+func tagDigests(dmap registry.DigestTags) map[image.Tag]image.Digest {
+	tags := map[image.Tag]image.Digest{}
+
+	for digest, tagSlice := range dmap {
+		for _, tag := range tagSlice {
+			tags[tag] = digest
+		}
+	}
+
+	return tags
+}
+
+func withoutCosignTags(tags registry.TagSlice) registry.TagSlice {
+	filtered := registry.TagSlice{}
+
+	for _, tag := range tags {
+		if !registry.CosignTag.MatchString(string(tag)) {
+			filtered = append(filtered, tag)
+		}
+	}
+
+	return filtered
+}
+
+// Count returns the number of findings of a kind.
+func (r *Report) Count(kind Kind) int {
+	n := 0
+
+	for i := range r.Findings {
+		if r.Findings[i].Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Err returns an error summarizing the findings, or nil if there are none.
+func (r *Report) Err() error {
+	if len(r.Findings) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"found %d digests and tags not accounted for by the manifests: %d unknown digests, %d unknown tags, %d tag mismatches",
+		len(r.Findings), r.Count(KindUnknownDigest), r.Count(KindUnknownTag), r.Count(KindTagMismatch),
+	)
+}
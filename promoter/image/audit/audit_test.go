@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/audit"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+const (
+	digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	digestC = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	digestD = "sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd"
+	digestE = "sha256:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+)
+
+func testReport() *audit.Report {
+	expected := map[image.Registry]registry.RegInvImage{
+		"gcr.io/prod": {
+			"foo": {digestA: {"v1.0"}, digestB: {"v2.0"}},
+		},
+		"gcr.io/other": {},
+	}
+
+	actual := map[image.Registry]registry.RegInvImage{
+		"gcr.io/prod": {
+			"foo": {
+				digestA: {"v1.0", "latest", image.Tag("sha256-" + digestA[7:] + ".sig")},
+				digestB: {},
+				digestC: {"v2.0"},
+				digestD: {},
+				digestE: {},
+			},
+		},
+		"gcr.io/other": {
+			"bar": {digestA: {"v1.0"}},
+		},
+	}
+
+	return audit.Compare(expected, actual, map[image.Digest]bool{digestD: true})
+}
+
+func TestCompare(t *testing.T) {
+	report := testReport()
+
+	require.Equal(t, []image.Registry{"gcr.io/other", "gcr.io/prod"}, report.Registries)
+	require.Equal(t, []audit.Finding{
+		{Kind: audit.KindUnknownDigest, Registry: "gcr.io/other", Image: "bar", Digest: digestA, Tags: registry.TagSlice{"v1.0"}},
+		{Kind: audit.KindUnknownTag, Registry: "gcr.io/prod", Image: "foo", Digest: digestA, Tag: "latest"},
+		{Kind: audit.KindUnknownDigest, Registry: "gcr.io/prod", Image: "foo", Digest: digestC, Tags: registry.TagSlice{"v2.0"}},
+		{Kind: audit.KindTagMismatch, Registry: "gcr.io/prod", Image: "foo", Digest: digestC, Tag: "v2.0", ExpectedDigest: digestB},
+		{Kind: audit.KindUnknownDigest, Registry: "gcr.io/prod", Image: "foo", Digest: digestE, Tags: registry.TagSlice{}},
+	}, report.Findings)
+
+	require.Equal(t, 3, report.Count(audit.KindUnknownDigest))
+	require.ErrorContains(t, report.Err(), "3 unknown digests, 1 unknown tags, 1 tag mismatches")
+
+	clean := audit.Compare(
+		map[image.Registry]registry.RegInvImage{"gcr.io/prod": {"foo": {digestA: {"v1.0"}}}},
+		map[image.Registry]registry.RegInvImage{"gcr.io/prod": {"foo": {digestA: {"v1.0"}}}},
+		nil,
+	)
+	require.Empty(t, clean.Findings)
+	require.NoError(t, clean.Err())
+}
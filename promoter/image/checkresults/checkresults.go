@@ -16,18 +16,115 @@ limitations under the License.
 
 package checkresults
 
+import "time"
+
+// Image is an image whose signatures are checked.
+type Image struct {
+	// Reference is the public reference of the image.
+	Reference string
+
+	// Uploaded is the time the image was pushed to the canonical
+	// registry, if known.
+	Uploaded *time.Time
+}
+
+// CheckList classifies the signature references of an image in each of
+// its mirrors.
 type CheckList struct {
-	Signed  []string
-	Missing []string
+	// Digest is the digest of the checked image.
+	Digest string `json:"digest,omitempty"`
+
+	// Uploaded is the time the image was pushed to the canonical
+	// registry, if known.
+	Uploaded *time.Time `json:"uploaded,omitempty"`
+
+	// Signed lists the signatures that passed verification.
+	Signed []string `json:"signed,omitempty"`
+
+	// Missing lists the mirrors where the image has no signature.
+	Missing []string `json:"missing,omitempty"`
+
+	// Invalid lists the signatures from the expected identity that failed
+	// verification, e.g. because the payload, the certificate chain or the
+	// transparency log inclusion could not be verified.
+	Invalid []string `json:"invalid,omitempty"`
+
+	// WrongIdentity lists the signatures whose certificates do not match
+	// the expected identity.
+	WrongIdentity []string `json:"wrongIdentity,omitempty"`
+
+	// Attested lists the digest references of the image in each mirror
+	// that have a promotion record attestation.
+	Attested []string `json:"attested,omitempty"`
+
+	// MissingAttestation lists the digest references of the image in each
+	// mirror without a promotion record attestation.
+	MissingAttestation []string `json:"missingAttestation,omitempty"`
+
+	// AttestationErrors maps the digest references of the image whose
+	// attestations could not be looked up to the lookup error. They are
+	// neither attested nor missing an attestation, so they are not fixed.
+	AttestationErrors map[string]string `json:"attestationErrors,omitempty"`
+
+	// Actions records the fixes applied to the image, or the ones that
+	// would have been applied when not running with fixes enabled.
+	Actions []Action `json:"actions,omitempty"`
+}
+
+// ActionType is the kind of fix applied to a signature.
+type ActionType string
+
+const (
+	// ActionSign signs an image again.
+	ActionSign ActionType = "sign"
+
+	// ActionReplicate copies a signature from one mirror to another.
+	ActionReplicate ActionType = "replicate"
+
+	// ActionAttest pushes a new promotion record attestation.
+	ActionAttest ActionType = "attest"
+
+	// ActionReplicateAttestation copies a promotion record attestation
+	// from one mirror to another.
+	ActionReplicateAttestation ActionType = "replicate-attestation"
+)
+
+// Action is a fix to the signature of an image in a mirror.
+type Action struct {
+	Type ActionType `json:"type"`
+
+	// Source is the signature or attestation copied by a replicate action.
+	Source string `json:"source,omitempty"`
+
+	// Target is the reference signed or attested, or the signature
+	// replaced.
+	Target string `json:"target"`
+
+	// Applied is false when the action was only planned.
+	Applied bool `json:"applied"`
 }
 
+// Unverified returns the signature references that need to be fixed: the
+// missing ones, followed by the invalid and wrong identity ones.
+func (c *CheckList) Unverified() []string {
+	unverified := make([]string, 0, len(c.Missing)+len(c.Invalid)+len(c.WrongIdentity))
+	unverified = append(unverified, c.Missing...)
+	unverified = append(unverified, c.Invalid...)
+	unverified = append(unverified, c.WrongIdentity...)
+
+	return unverified
+}
+
+// Signature maps image references to the signature status of their mirrors.
 type Signature map[string]CheckList
 
+// TotalPartial returns the number of images that have a verified signature
+// in some of their mirrors, but not in all of them.
 func (s *Signature) TotalPartial() int {
 	total := 0
 
 	for _, list := range *s {
-		if len(list.Signed) > 0 && len(list.Missing) > 0 {
+		if len(list.Signed) > 0 && len(list.Unverified()) > 0 {
 			total++
 		}
 	}
@@ -35,11 +132,27 @@ func (s *Signature) TotalPartial() int {
 	return total
 }
 
+// TotalUnsigned returns the number of images without a verified signature
+// in any of their mirrors.
 func (s *Signature) TotalUnsigned() int {
 	total := 0
 
 	for _, list := range *s {
-		if len(list.Missing) > 0 && len(list.Signed) == 0 {
+		if len(list.Unverified()) > 0 && len(list.Signed) == 0 {
+			total++
+		}
+	}
+
+	return total
+}
+
+// TotalUnattested returns the number of images missing a promotion record
+// attestation in at least one of their mirrors.
+func (s *Signature) TotalUnattested() int {
+	total := 0
+
+	for _, list := range *s {
+		if len(list.MissingAttestation) > 0 {
 			total++
 		}
 	}
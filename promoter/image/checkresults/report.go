@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkresults
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Markdown renders a summary table of all images followed by the details
+// of the ones that need fixing.
+func (s Signature) Markdown() string {
+	images := make([]string, 0, len(s))
+	for img := range s {
+		images = append(images, img)
+	}
+
+	sort.Strings(images)
+
+	var b strings.Builder
+
+	b.WriteString("# Signature consistency report\n\n")
+	fmt.Fprintf(&b,
+		"%d images checked, %d unsigned, %d partially signed, %d missing attestations.\n\n",
+		len(s), s.TotalUnsigned(), s.TotalPartial(), s.TotalUnattested(),
+	)
+
+	b.WriteString("| Image | Digest | Uploaded | Signed | Missing | Invalid | Wrong identity | Attested | Missing attestation |\n")
+	b.WriteString("|-------|--------|----------|--------|---------|---------|----------------|----------|---------------------|\n")
+
+	for _, img := range images {
+		list := s[img]
+
+		uploaded := ""
+		if list.Uploaded != nil {
+			uploaded = list.Uploaded.UTC().Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(&b, "| `%s` | `%s` | %s | %d | %d | %d | %d | %d | %d |\n",
+			img, list.Digest, uploaded,
+			len(list.Signed), len(list.Missing), len(list.Invalid), len(list.WrongIdentity),
+			len(list.Attested), len(list.MissingAttestation),
+		)
+	}
+
+	for _, img := range images {
+		list := s[img]
+		if len(list.Unverified()) == 0 && len(list.MissingAttestation) == 0 && len(list.AttestationErrors) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n## `%s`\n\n", img)
+
+		for _, section := range []struct {
+			title string
+			refs  []string
+		}{
+			{"Missing", list.Missing},
+			{"Invalid", list.Invalid},
+			{"Wrong identity", list.WrongIdentity},
+			{"Missing attestation", list.MissingAttestation},
+		} {
+			for _, ref := range section.refs {
+				fmt.Fprintf(&b, "- %s: `%s`\n", section.title, ref)
+			}
+		}
+
+		refs := make([]string, 0, len(list.AttestationErrors))
+		for ref := range list.AttestationErrors {
+			refs = append(refs, ref)
+		}
+
+		sort.Strings(refs)
+
+		for _, ref := range refs {
+			fmt.Fprintf(&b, "- Attestation check failed: `%s`: %s\n", ref, list.AttestationErrors[ref])
+		}
+
+		for _, action := range list.Actions {
+			state := "planned"
+			if action.Applied {
+				state = "applied"
+			}
+
+			if action.Source != "" {
+				fmt.Fprintf(&b, "- Action (%s): %s `%s` to `%s`\n", state, action.Type, action.Source, action.Target)
+			} else {
+				fmt.Fprintf(&b, "- Action (%s): %s `%s`\n", state, action.Type, action.Target)
+			}
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkresults_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
+)
+
+func testResults() checkresults.Signature {
+	uploaded := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return checkresults.Signature{
+		"registry.k8s.io/foo:v1": {
+			Digest:             "sha256:aaaa",
+			Uploaded:           &uploaded,
+			Signed:             []string{"mirror1/foo:sha256-aaaa.sig"},
+			Invalid:            []string{"mirror2/foo:sha256-aaaa.sig"},
+			Attested:           []string{"mirror1/foo@sha256:aaaa"},
+			MissingAttestation: []string{"mirror2/foo@sha256:aaaa"},
+			Actions: []checkresults.Action{{
+				Type:   checkresults.ActionReplicate,
+				Source: "mirror1/foo:sha256-aaaa.sig",
+				Target: "mirror2/foo:sha256-aaaa.sig",
+			}, {
+				Type:    checkresults.ActionReplicateAttestation,
+				Source:  "mirror1/foo@sha256:aaaa",
+				Target:  "mirror2/foo@sha256:aaaa",
+				Applied: true,
+			}},
+		},
+		"registry.k8s.io/bar:v1": {
+			Digest: "sha256:bbbb",
+			Signed: []string{"mirror1/bar:sha256-bbbb.sig", "mirror2/bar:sha256-bbbb.sig"},
+		},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	md := testResults().Markdown()
+	require.Contains(t, md, "2 images checked, 0 unsigned, 1 partially signed, 1 missing attestations.")
+	require.Contains(t, md, "| `registry.k8s.io/bar:v1` | `sha256:bbbb` |  | 2 | 0 | 0 | 0 | 0 | 0 |")
+	require.Contains(t, md, "| `registry.k8s.io/foo:v1` | `sha256:aaaa` | 2026-01-02T03:04:05Z | 1 | 0 | 1 | 0 | 1 | 1 |")
+	require.Contains(t, md, "- Invalid: `mirror2/foo:sha256-aaaa.sig`")
+	require.Contains(t, md, "- Action (planned): replicate `mirror1/foo:sha256-aaaa.sig` to `mirror2/foo:sha256-aaaa.sig`")
+	require.Contains(t, md, "- Missing attestation: `mirror2/foo@sha256:aaaa`")
+	require.Contains(t, md, "- Action (applied): replicate-attestation `mirror1/foo@sha256:aaaa` to `mirror2/foo@sha256:aaaa`")
+	require.NotContains(t, md, "## `registry.k8s.io/bar:v1`")
+}
@@ -0,0 +1,609 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package e2e runs image promotions end to end against an in-process
+// registry. Signing, signature verification, attestation signing and
+// provenance verification are replaced by fakes that store and read their
+// data in the registry, so no network access or sigstore instance is
+// needed.
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrregistry "github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"gopkg.in/yaml.v2"
+	"sigs.k8s.io/release-sdk/sign"
+
+	impl "sigs.k8s.io/promo-tools/v4/internal/promoter/image"
+	imagepromoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/auth"
+	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/provenance"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+const (
+	// Identity is the identity the promoter signs the promoted images with.
+	Identity = "promoter@e2e.example.com"
+
+	// identityAnnotation is the signature layer annotation where the fake
+	// signer records the identity that signed a digest.
+	identityAnnotation = "dev.promo-tools.e2e/identity"
+
+	// signatureMediaType is the media type of cosign signature layers.
+	signatureMediaType cr.MediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+)
+
+// Harness is an in-process OCI registry, with referrers support, that
+// promotions run against. Its registries are paths of Host, e.g.
+// Registry("staging").
+type Harness struct {
+	// Host is the host:port address of the registry.
+	Host string
+
+	t         testing.TB
+	transport *ratelimit.RoundTripper
+	provider  registry.Provider
+
+	// signMu serializes signatures, which read and rewrite the signature
+	// image of a digest.
+	signMu sync.Mutex
+
+	// mu guards denied.
+	mu     sync.Mutex
+	denied []string
+}
+
+// New starts a registry served over TLS for the duration of the test.
+func New(t testing.TB) *Harness {
+	t.Helper()
+
+	h := &Harness{t: t}
+
+	s := httptest.NewTLSServer(h.intercept(ggcrregistry.New(
+		ggcrregistry.WithReferrersSupport(true),
+		ggcrregistry.Logger(log.New(io.Discard, "", 0)),
+	)))
+	t.Cleanup(s.Close)
+
+	h.Host = s.Listener.Addr().String()
+	h.transport = ratelimit.NewRoundTripperWithBase(ratelimit.MaxEvents, s.Client().Transport)
+	h.provider = registry.NewCraneProvider(registry.WithTransport(h.transport))
+
+	return h
+}
+
+// Registry returns the registry at the given path of the harness host.
+func (h *Harness) Registry(path string) image.Registry {
+	return image.Registry(h.Host + "/" + path)
+}
+
+// Provider returns a registry provider reading the harness registry.
+func (h *Harness) Provider() registry.Provider {
+	return h.provider
+}
+
+// PushImage pushes a random image to a reference and returns its digest.
+func (h *Harness) PushImage(ref string) image.Digest {
+	h.t.Helper()
+
+	img, err := random.Image(1024, 1)
+	if err != nil {
+		h.t.Fatalf("creating image: %v", err)
+	}
+
+	return h.write(ref, img)
+}
+
+// PushIndex pushes a random image index with one image per architecture to
+// a reference and returns the digests of the index and of its children.
+func (h *Harness) PushIndex(ref string, archs ...string) (index image.Digest, children []image.Digest) {
+	h.t.Helper()
+
+	var idx v1.ImageIndex = empty.Index
+
+	for _, arch := range archs {
+		img, err := random.Image(1024, 1)
+		if err != nil {
+			h.t.Fatalf("creating image: %v", err)
+		}
+
+		digest, err := img.Digest()
+		if err != nil {
+			h.t.Fatalf("computing digest: %v", err)
+		}
+
+		idx = mutate.AppendManifests(idx, mutate.IndexAddendum{
+			Add: img,
+			Descriptor: v1.Descriptor{
+				MediaType: cr.DockerManifestSchema2,
+				Platform:  &v1.Platform{OS: "linux", Architecture: arch},
+			},
+		})
+		children = append(children, image.Digest(digest.String()))
+	}
+
+	r := h.parse(ref)
+
+	if err := remote.WriteIndex(r, idx, h.remoteOptions()...); err != nil {
+		h.t.Fatalf("pushing index %s: %v", ref, err)
+	}
+
+	digest, err := idx.Digest()
+	if err != nil {
+		h.t.Fatalf("computing digest: %v", err)
+	}
+
+	return image.Digest(digest.String()), children
+}
+
+// AttachReferrer pushes an artifact of the given type referring to the
+// digest of a reference and returns the digest of the artifact.
+func (h *Harness) AttachReferrer(ref, artifactType string) image.Digest {
+	h.t.Helper()
+
+	desc, err := remote.Head(h.parse(ref), h.remoteOptions()...)
+	if err != nil {
+		h.t.Fatalf("getting %s: %v", ref, err)
+	}
+
+	artifact, err := random.Image(128, 1)
+	if err != nil {
+		h.t.Fatalf("creating artifact: %v", err)
+	}
+
+	artifact = mutate.ConfigMediaType(mutate.MediaType(artifact, cr.OCIManifestSchema1), cr.MediaType(artifactType))
+
+	withSubject, ok := mutate.Subject(artifact, *desc).(v1.Image)
+	if !ok {
+		h.t.Fatal("setting the subject of the artifact")
+	}
+
+	digest, err := withSubject.Digest()
+	if err != nil {
+		h.t.Fatalf("computing digest: %v", err)
+	}
+
+	return h.write(h.parse(ref).Context().Digest(digest.String()).String(), withSubject)
+}
+
+// Sign signs the digest of a reference, and the children of an image
+// index, as the given identity.
+func (h *Harness) Sign(ref, identity string) {
+	h.t.Helper()
+
+	if err := h.sign(ref, identity, true); err != nil {
+		h.t.Fatalf("signing %s: %v", ref, err)
+	}
+}
+
+// Signers returns the identities that signed the digest of a reference.
+// Unsigned digests have none.
+func (h *Harness) Signers(ref string) []string {
+	h.t.Helper()
+
+	signers, err := h.signers(ref)
+	if err != nil {
+		h.t.Fatalf("reading the signatures of %s: %v", ref, err)
+	}
+
+	return signers
+}
+
+// Digest returns the digest of a reference, or an empty digest if it does
+// not exist.
+func (h *Harness) Digest(ref string) image.Digest {
+	h.t.Helper()
+
+	digest, err := h.provider.HeadDigest(context.Background(), ref)
+	if err != nil {
+		if isNotFound(err) {
+			return ""
+		}
+
+		h.t.Fatalf("getting the digest of %s: %v", ref, err)
+	}
+
+	return digest
+}
+
+// Referrers returns the referrers of the digest of a reference.
+func (h *Harness) Referrers(ref string) []v1.Descriptor {
+	h.t.Helper()
+
+	referrers, err := h.provider.ListReferrers(context.Background(), ref, "")
+	if err != nil {
+		h.t.Fatalf("listing the referrers of %s: %v", ref, err)
+	}
+
+	return referrers
+}
+
+// Inventory reads the images of a registry and of the registries nested in
+// it.
+func (h *Harness) Inventory(reg image.Registry) registry.RegInvImage {
+	h.t.Helper()
+
+	inv, err := h.provider.ReadRegistries(
+		context.Background(), []registry.RegistryConfig{{Name: reg}}, true, nil,
+	)
+	if err != nil {
+		h.t.Fatalf("reading %s: %v", reg, err)
+	}
+
+	return inv.Images[reg]
+}
+
+// DenyPushes makes the registry reject the manifests pushed to the
+// repositories under the given path of the harness host, as a registry
+// denying access would.
+func (h *Harness) DenyPushes(path string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.denied = append(h.denied, path)
+}
+
+// AllowPushes lifts the rejections set with DenyPushes.
+func (h *Harness) AllowPushes() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.denied = nil
+}
+
+// WriteManifests writes a thin manifest directory holding a manifest per
+// subproject and returns its path.
+func (h *Harness) WriteManifests(mfests map[string]schema.Manifest) string {
+	h.t.Helper()
+
+	dir := h.t.TempDir()
+
+	for subproject, mfest := range mfests {
+		h.writeYAML(filepath.Join(dir, "manifests", subproject, "promoter-manifest.yaml"), schema.ThinManifest{
+			Registries:        mfest.Registries,
+			StagingSignatures: mfest.StagingSignatures,
+		})
+		h.writeYAML(filepath.Join(dir, "images", subproject, "images.yaml"), mfest.Images)
+	}
+
+	return dir
+}
+
+// Options returns promotion options for a thin manifest directory that
+// promote and sign the images as Identity, and verify staging signatures
+// against it by default.
+func (h *Harness) Options(dir string) *options.Options {
+	opts := *options.DefaultOptions
+	opts.ThinManifestDir = dir
+	opts.Confirm = true
+	opts.Threads = 4
+	opts.MaxSignatureOps = 4
+	opts.SignerAccount = Identity
+	opts.SignCheckIdentity = Identity
+
+	return &opts
+}
+
+// Promote runs Promoter.PromoteImages against the harness registry.
+func (h *Harness) Promote(ctx context.Context, opts *options.Options) error {
+	di := impl.NewDefaultPromoterImplementation(opts)
+	di.SetTransport(h.transport)
+	di.SetRegistryProvider(h.provider)
+	di.SetIdentityTokenProvider(&auth.StaticIdentityTokenProvider{Token: opts.SignerAccount})
+	di.SetSignerFactory(func(*sign.Options) impl.ImageSigner { return &fakeSigner{h: h} })
+	di.SetVerifierFactory(func(o *sign.Options) impl.ImageVerifier { return &fakeVerifier{h: h, opts: o} })
+	di.SetStatementSigner(&fakeStatementSigner{})
+
+	p := imagepromoter.New(opts)
+	p.SetImplementation(&implementation{di})
+	p.SetProvenanceVerifier(&fakeProvenanceVerifier{})
+
+	return p.PromoteImages(ctx, opts)
+}
+
+// implementation is the promoter implementation run by the harness. The
+// fake signers need no sigstore trust root, so the TUF cache is not
+// prewarmed.
+type implementation struct {
+	*impl.DefaultPromoterImplementation
+}
+
+func (*implementation) PrewarmTUFCache(context.Context) error {
+	return nil
+}
+
+// fakeSigner signs images as the identity of its identity token, which
+// the harness sets to the signer account.
+type fakeSigner struct {
+	h *Harness
+}
+
+func (s *fakeSigner) SignImageWithOptions(o *sign.Options, ref string) (*sign.SignedObject, error) {
+	if err := s.h.sign(ref, o.IdentityToken, o.Recursive); err != nil {
+		return nil, err
+	}
+
+	return &sign.SignedObject{}, nil
+}
+
+// fakeVerifier verifies that an image was signed by the certificate
+// identity of its options. The issuer is not checked.
+type fakeVerifier struct {
+	h    *Harness
+	opts *sign.Options
+}
+
+func (v *fakeVerifier) VerifyImage(ref string) (*sign.SignedObject, error) {
+	signers, err := v.h.signers(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signers) == 0 {
+		return nil, nil //nolint:nilnil // mirrors sign.Signer for unsigned images
+	}
+
+	for _, signer := range signers {
+		if signer == v.opts.CertIdentity {
+			return &sign.SignedObject{}, nil
+		}
+
+		if v.opts.CertIdentityRegexp != "" {
+			if ok, err := regexp.MatchString(v.opts.CertIdentityRegexp, signer); err == nil && ok {
+				return &sign.SignedObject{}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("none of the expected identities matched: %v", signers)
+}
+
+// fakeStatementSigner wraps attestation statements into a placeholder
+// bundle.
+type fakeStatementSigner struct{}
+
+func (*fakeStatementSigner) SignStatement(statement []byte) ([]byte, error) {
+	return fmt.Appendf(nil, `{"e2e": %s}`, statement), nil
+}
+
+// fakeProvenanceVerifier accepts the provenance of every image.
+type fakeProvenanceVerifier struct{}
+
+func (*fakeProvenanceVerifier) Verify(context.Context, string) (*provenance.Result, error) {
+	return &provenance.Result{Verified: true}, nil
+}
+
+// sign appends a signature layer recording the identity to the signature
+// image of the digest of a reference and, when recursive, of the children
+// of an image index.
+func (h *Harness) sign(ref, identity string, recursive bool) error {
+	h.signMu.Lock()
+	defer h.signMu.Unlock()
+
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return fmt.Errorf("parsing reference: %w", err)
+	}
+
+	desc, err := remote.Get(r, h.remoteOptions()...)
+	if err != nil {
+		return fmt.Errorf("getting %s: %w", ref, err)
+	}
+
+	digests := []v1.Hash{desc.Digest}
+
+	if recursive && desc.MediaType.IsIndex() {
+		idx, err := desc.ImageIndex()
+		if err != nil {
+			return fmt.Errorf("reading index: %w", err)
+		}
+
+		manifest, err := idx.IndexManifest()
+		if err != nil {
+			return fmt.Errorf("reading index manifest: %w", err)
+		}
+
+		for _, child := range manifest.Manifests {
+			digests = append(digests, child.Digest)
+		}
+	}
+
+	for _, digest := range digests {
+		tag := signatureTag(r.Context(), digest)
+
+		var sig v1.Image = empty.Image
+
+		existing, err := remote.Image(tag, h.remoteOptions()...)
+		switch {
+		case err == nil:
+			sig = existing
+		case !isNotFound(err):
+			return fmt.Errorf("getting signature %s: %w", tag, err)
+		}
+
+		sig, err = mutate.Append(sig, mutate.Addendum{
+			Layer:       static.NewLayer([]byte(digest.String()), signatureMediaType),
+			Annotations: map[string]string{identityAnnotation: identity},
+		})
+		if err != nil {
+			return fmt.Errorf("appending signature: %w", err)
+		}
+
+		if err := remote.Write(tag, sig, h.remoteOptions()...); err != nil {
+			return fmt.Errorf("pushing signature %s: %w", tag, err)
+		}
+	}
+
+	return nil
+}
+
+// signers returns the identities recorded in the signature image of the
+// digest of a reference.
+func (h *Harness) signers(ref string) ([]string, error) {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return nil, fmt.Errorf("parsing reference: %w", err)
+	}
+
+	desc, err := remote.Head(r, h.remoteOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s: %w", ref, err)
+	}
+
+	sig, err := remote.Image(signatureTag(r.Context(), desc.Digest), h.remoteOptions()...)
+	if isNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("getting signature: %w", err)
+	}
+
+	manifest, err := partial.Manifest(sig)
+	if err != nil {
+		return nil, fmt.Errorf("reading signature manifest: %w", err)
+	}
+
+	signers := []string{}
+
+	for _, layer := range manifest.Layers {
+		if identity, ok := layer.Annotations[identityAnnotation]; ok {
+			signers = append(signers, identity)
+		}
+	}
+
+	return signers, nil
+}
+
+// intercept rejects the manifest pushes to the repositories denied with
+// DenyPushes.
+func (h *Harness) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		repo, _, isManifest := strings.Cut(strings.TrimPrefix(r.URL.Path, "/v2/"), "/manifests/")
+		if r.Method == http.MethodPut && isManifest && h.isDenied(repo) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":[{"code":"DENIED","message":"push denied by the e2e harness"}]}`)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isDenied returns whether pushes to a repository are denied.
+func (h *Harness) isDenied(repo string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, path := range h.denied {
+		if repo == path || strings.HasPrefix(repo, path+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// write pushes an image to a reference and returns its digest.
+func (h *Harness) write(ref string, img v1.Image) image.Digest {
+	h.t.Helper()
+
+	if err := remote.Write(h.parse(ref), img, h.remoteOptions()...); err != nil {
+		h.t.Fatalf("pushing %s: %v", ref, err)
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		h.t.Fatalf("computing digest: %v", err)
+	}
+
+	return image.Digest(digest.String())
+}
+
+// writeYAML marshals a value into a file, creating its directory.
+func (h *Harness) writeYAML(path string, v any) {
+	h.t.Helper()
+
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		h.t.Fatalf("marshaling %s: %v", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		h.t.Fatalf("creating directory: %v", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		h.t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+// parse parses a reference of the harness registry.
+func (h *Harness) parse(ref string) name.Reference {
+	h.t.Helper()
+
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		h.t.Fatalf("parsing reference %s: %v", ref, err)
+	}
+
+	return r
+}
+
+func (h *Harness) remoteOptions() []remote.Option {
+	return []remote.Option{remote.WithTransport(h.transport)}
+}
+
+// signatureTag returns the cosign signature tag of a digest.
+func signatureTag(repo name.Repository, digest v1.Hash) name.Tag {
+	return repo.Tag(strings.ReplaceAll(digest.String(), "sha256:", "sha256-") + ".sig")
+}
+
+// isNotFound returns whether a registry error is a not found error.
+func isNotFound(err error) bool {
+	var terr *transport.Error
+
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/e2e"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+const stagingIdentity = "staging@e2e.example.com"
+
+// stagingSigner is the trusted signer of the staging images. The fake
+// verifier of the harness does not check issuers.
+var stagingSigner = schema.Signer{
+	Identity: stagingIdentity,
+	Issuer:   "https://accounts.google.com",
+}
+
+// manifest promotes images from the staging registry of the harness to its
+// production and mirror registries.
+func manifest(h *e2e.Harness, images ...registry.Image) schema.Manifest {
+	return schema.Manifest{
+		Registries: []registry.Context{
+			{Name: h.Registry("staging"), Src: true},
+			{Name: h.Registry("production")},
+			{Name: h.Registry("mirror")},
+		},
+		Images: images,
+	}
+}
+
+func TestPromoteMultiArch(t *testing.T) {
+	t.Parallel()
+
+	h := e2e.New(t)
+	ctx := context.Background()
+
+	index, children := h.PushIndex(string(h.Registry("staging"))+"/foo:v1.0", "amd64", "arm64")
+	h.Sign(string(h.Registry("staging"))+"/foo:v1.0", stagingIdentity)
+	h.AttachReferrer(string(h.Registry("staging"))+"/foo@"+string(index), "application/spdx+json")
+
+	bar := h.PushImage(string(h.Registry("staging")) + "/bar:v2.0")
+
+	signed := manifest(h, registry.Image{Name: "foo", Dmap: registry.DigestTags{index: {"v1.0", "latest"}}})
+	signed.StagingSignatures = &schema.SignaturePolicy{
+		Mode:    schema.SignatureModeRequired,
+		Signers: []schema.Signer{stagingSigner},
+	}
+
+	dir := h.WriteManifests(map[string]schema.Manifest{
+		"foo": signed,
+		"bar": manifest(h, registry.Image{Name: "bar", Dmap: registry.DigestTags{bar: {"v2.0"}}}),
+	})
+	require.NoError(t, h.Promote(ctx, h.Options(dir)))
+
+	for _, dst := range []string{"production", "mirror"} {
+		repo := string(h.Registry(dst))
+
+		require.Equal(t, index, h.Digest(repo+"/foo:v1.0"))
+		require.Equal(t, index, h.Digest(repo+"/foo:latest"))
+		require.Equal(t, bar, h.Digest(repo+"/bar:v2.0"))
+
+		// The staging signature is carried over and the images are signed
+		// again, children included
+		require.ElementsMatch(t, []string{stagingIdentity, e2e.Identity}, h.Signers(repo+"/foo:v1.0"))
+		require.Equal(t, []string{e2e.Identity}, h.Signers(repo+"/bar:v2.0"))
+
+		for _, child := range children {
+			require.Contains(t, h.Signers(repo+"/foo@"+string(child)), e2e.Identity)
+		}
+
+		// Each promoted digest gets a promotion record attestation
+		require.Len(t, h.Referrers(repo+"/foo@"+string(index)), 1)
+		require.Len(t, h.Referrers(repo+"/bar@"+string(bar)), 1)
+	}
+}
+
+func TestPromoteStagingSignatureRequired(t *testing.T) {
+	t.Parallel()
+
+	h := e2e.New(t)
+	ctx := context.Background()
+
+	foo := h.PushImage(string(h.Registry("staging")) + "/foo:v1.0")
+	h.Sign(string(h.Registry("staging"))+"/foo:v1.0", "someone@e2e.example.com")
+
+	mfest := manifest(h, registry.Image{Name: "foo", Dmap: registry.DigestTags{foo: {"v1.0"}}})
+	mfest.StagingSignatures = &schema.SignaturePolicy{
+		Mode:    schema.SignatureModeRequired,
+		Signers: []schema.Signer{stagingSigner},
+	}
+
+	dir := h.WriteManifests(map[string]schema.Manifest{"foo": mfest})
+	require.ErrorContains(t, h.Promote(ctx, h.Options(dir)), "checking signatures in staging images")
+	require.Empty(t, h.Digest(string(h.Registry("production"))+"/foo:v1.0"))
+}
+
+func TestPromoteTagMoveRejected(t *testing.T) {
+	t.Parallel()
+
+	h := e2e.New(t)
+	ctx := context.Background()
+
+	old := h.PushImage(string(h.Registry("production")) + "/foo:v1.0")
+	foo := h.PushImage(string(h.Registry("staging")) + "/foo:v1.0")
+
+	dir := h.WriteManifests(map[string]schema.Manifest{
+		"foo": manifest(h, registry.Image{Name: "foo", Dmap: registry.DigestTags{foo: {"v1.0"}}}),
+	})
+	require.ErrorContains(t, h.Promote(ctx, h.Options(dir)), "encountered errors during edge filtering")
+
+	// The tag is not moved and nothing else is promoted
+	require.Equal(t, old, h.Digest(string(h.Registry("production"))+"/foo:v1.0"))
+	require.Empty(t, h.Digest(string(h.Registry("mirror"))+"/foo:v1.0"))
+}
+
+func TestPromoteLostImages(t *testing.T) {
+	t.Parallel()
+
+	h := e2e.New(t)
+	ctx := context.Background()
+
+	foo := h.PushImage(string(h.Registry("staging")) + "/foo:v1.0")
+	lost := image.Digest("sha256:0000000000000000000000000000000000000000000000000000000000000000")
+
+	dir := h.WriteManifests(map[string]schema.Manifest{
+		"foo": manifest(h, registry.Image{Name: "foo", Dmap: registry.DigestTags{
+			foo:  {"v1.0"},
+			lost: {"v0.9"},
+		}}),
+	})
+
+	// Images missing from staging are skipped without failing the others
+	require.NoError(t, h.Promote(ctx, h.Options(dir)))
+	require.Equal(t, foo, h.Digest(string(h.Registry("production"))+"/foo:v1.0"))
+	require.Empty(t, h.Digest(string(h.Registry("production"))+"/foo:v0.9"))
+}
+
+func TestPromoteOverlappingEdges(t *testing.T) {
+	t.Parallel()
+
+	h := e2e.New(t)
+	ctx := context.Background()
+
+	foo := h.PushImage(string(h.Registry("staging")) + "/foo:v1.0")
+	other := h.PushImage(string(h.Registry("other-staging")) + "/foo:v1.0")
+	h.PushImage(string(h.Registry("other-staging")) + "/foo:v1.0") // other is untagged
+	copied := h.PushImage(string(h.Registry("other-staging")) + "/bar:v1.0")
+	require.NoError(t, h.Provider().CopyImage(ctx,
+		string(h.Registry("other-staging"))+"/bar:v1.0", string(h.Registry("staging"))+"/bar:v1.0"))
+
+	otherManifest := func(images ...registry.Image) schema.Manifest {
+		mfest := manifest(h, images...)
+		mfest.Registries[0].Name = h.Registry("other-staging")
+
+		return mfest
+	}
+
+	// Two manifests promoting different digests to the same tag
+	dir := h.WriteManifests(map[string]schema.Manifest{
+		"foo":   manifest(h, registry.Image{Name: "foo", Dmap: registry.DigestTags{foo: {"v1.0"}}}),
+		"other": otherManifest(registry.Image{Name: "foo", Dmap: registry.DigestTags{other: {"v1.0"}}}),
+	})
+	require.ErrorContains(t, h.Promote(ctx, h.Options(dir)), "overlapping edges detected")
+	require.Empty(t, h.Inventory(h.Registry("production")))
+
+	// Two manifests promoting the same digest to the same tag
+	dir = h.WriteManifests(map[string]schema.Manifest{
+		"bar":   manifest(h, registry.Image{Name: "bar", Dmap: registry.DigestTags{copied: {"v1.0"}}}),
+		"other": otherManifest(registry.Image{Name: "bar", Dmap: registry.DigestTags{copied: {"v1.0"}}}),
+	})
+	require.NoError(t, h.Promote(ctx, h.Options(dir)))
+	require.Equal(t, copied, h.Digest(string(h.Registry("production"))+"/bar:v1.0"))
+}
+
+func TestPromotePartialFailure(t *testing.T) {
+	t.Parallel()
+
+	h := e2e.New(t)
+	ctx := context.Background()
+
+	foo := h.PushImage(string(h.Registry("staging")) + "/foo:v1.0")
+	bar := h.PushImage(string(h.Registry("staging")) + "/bar:v1.0")
+
+	dir := h.WriteManifests(map[string]schema.Manifest{
+		"images": manifest(h,
+			registry.Image{Name: "foo", Dmap: registry.DigestTags{foo: {"v1.0"}}},
+			registry.Image{Name: "bar", Dmap: registry.DigestTags{bar: {"v1.0"}}},
+		),
+	})
+
+	h.DenyPushes("mirror/bar")
+	require.ErrorContains(t, h.Promote(ctx, h.Options(dir)), "running promotion")
+	require.Empty(t, h.Digest(string(h.Registry("mirror"))+"/bar:v1.0"))
+
+	// A later run promotes what is missing once the registry accepts pushes
+	h.AllowPushes()
+	require.NoError(t, h.Promote(ctx, h.Options(dir)))
+
+	// The images copied by the failed run are signed by the later one
+	for _, dst := range []string{"production", "mirror"} {
+		repo := string(h.Registry(dst))
+
+		require.Equal(t, foo, h.Digest(repo+"/foo:v1.0"))
+		require.Equal(t, bar, h.Digest(repo+"/bar:v1.0"))
+		require.Equal(t, []string{e2e.Identity}, h.Signers(repo+"/foo:v1.0"))
+		require.Equal(t, []string{e2e.Identity}, h.Signers(repo+"/bar:v1.0"))
+	}
+}
+
+func TestPromoteIdempotent(t *testing.T) {
+	t.Parallel()
+
+	h := e2e.New(t)
+	ctx := context.Background()
+
+	index, _ := h.PushIndex(string(h.Registry("staging"))+"/foo:v1.0", "amd64", "arm64", "s390x")
+	bar := h.PushImage(string(h.Registry("staging")) + "/bar:v1.0")
+
+	dir := h.WriteManifests(map[string]schema.Manifest{
+		"images": manifest(h,
+			registry.Image{Name: "foo", Dmap: registry.DigestTags{index: {"v1.0"}}},
+			registry.Image{Name: "bar", Dmap: registry.DigestTags{bar: {}}},
+		),
+	})
+	require.NoError(t, h.Promote(ctx, h.Options(dir)))
+
+	production := h.Inventory(h.Registry("production"))
+	signers := h.Signers(string(h.Registry("production")) + "/foo:v1.0")
+	referrers := h.Referrers(string(h.Registry("production")) + "/foo@" + string(index))
+
+	// Running again finds everything promoted and changes nothing
+	require.NoError(t, h.Promote(ctx, h.Options(dir)))
+	require.Equal(t, production, h.Inventory(h.Registry("production")))
+	require.Equal(t, signers, h.Signers(string(h.Registry("production"))+"/foo:v1.0"))
+	require.Equal(t, referrers, h.Referrers(string(h.Registry("production"))+"/foo@"+string(index)))
+	require.Equal(t, bar, h.Digest(string(h.Registry("production"))+"/bar@"+string(bar)))
+}
@@ -21,12 +21,17 @@ import (
 	"context"
 	"sync"
 
+	"sigs.k8s.io/promo-tools/v4/promoter/image/audit"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/mirrorcheck"
 	imagepromotera "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/provenance"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/retention"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/vuln"
+	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
 type FakePromoterImplementation struct {
@@ -44,6 +49,74 @@ type FakePromoterImplementation struct {
 		result1 []schema.Manifest
 		result2 error
 	}
+	AuditRegistriesStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) (*audit.Report, error)
+	auditRegistriesMutex       sync.RWMutex
+	auditRegistriesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}
+	auditRegistriesReturns struct {
+		result1 *audit.Report
+		result2 error
+	}
+	auditRegistriesReturnsOnCall map[int]struct {
+		result1 *audit.Report
+		result2 error
+	}
+	DeleteStagingImagesStub        func(context.Context, *imagepromotera.Options, *retention.Report) error
+	deleteStagingImagesMutex       sync.RWMutex
+	deleteStagingImagesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 *retention.Report
+	}
+	deleteStagingImagesReturns struct {
+		result1 error
+	}
+	deleteStagingImagesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ExportImagesStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) error
+	exportImagesMutex       sync.RWMutex
+	exportImagesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}
+	exportImagesReturns struct {
+		result1 error
+	}
+	exportImagesReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FixMirrorsStub        func(context.Context, *imagepromotera.Options, *mirrorcheck.Report) error
+	fixMirrorsMutex       sync.RWMutex
+	fixMirrorsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 *mirrorcheck.Report
+	}
+	fixMirrorsReturns struct {
+		result1 error
+	}
+	fixMirrorsReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FixMissingAttestationsStub        func(context.Context, *imagepromotera.Options, checkresults.Signature, provenance.Generator) error
+	fixMissingAttestationsMutex       sync.RWMutex
+	fixMissingAttestationsArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 checkresults.Signature
+		arg4 provenance.Generator
+	}
+	fixMissingAttestationsReturns struct {
+		result1 error
+	}
+	fixMissingAttestationsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	FixMissingSignaturesStub        func(*imagepromotera.Options, checkresults.Signature) error
 	fixMissingSignaturesMutex       sync.RWMutex
 	fixMissingSignaturesArgsForCall []struct {
@@ -68,17 +141,32 @@ type FakePromoterImplementation struct {
 	fixPartialSignaturesReturnsOnCall map[int]struct {
 		result1 error
 	}
-	GetLatestImagesStub        func(*imagepromotera.Options) ([]string, error)
+	GetLatestImagesStub        func(*imagepromotera.Options) ([]checkresults.Image, error)
 	getLatestImagesMutex       sync.RWMutex
 	getLatestImagesArgsForCall []struct {
 		arg1 *imagepromotera.Options
 	}
 	getLatestImagesReturns struct {
-		result1 []string
+		result1 []checkresults.Image
 		result2 error
 	}
 	getLatestImagesReturnsOnCall map[int]struct {
-		result1 []string
+		result1 []checkresults.Image
+		result2 error
+	}
+	GetMirrorStatusStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) (*mirrorcheck.Report, error)
+	getMirrorStatusMutex       sync.RWMutex
+	getMirrorStatusArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}
+	getMirrorStatusReturns struct {
+		result1 *mirrorcheck.Report
+		result2 error
+	}
+	getMirrorStatusReturnsOnCall map[int]struct {
+		result1 *mirrorcheck.Report
 		result2 error
 	}
 	GetPromotionEdgesStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) (map[promotion.Edge]any, error)
@@ -96,7 +184,7 @@ type FakePromoterImplementation struct {
 		result1 map[promotion.Edge]any
 		result2 error
 	}
-	GetRegistryImageInventoryStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) (registry.RegInvImage, error)
+	GetRegistryImageInventoryStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) (registry.RegInvImage, map[image.Digest]registry.ManifestDetails, error)
 	getRegistryImageInventoryMutex       sync.RWMutex
 	getRegistryImageInventoryArgsForCall []struct {
 		arg1 context.Context
@@ -105,17 +193,20 @@ type FakePromoterImplementation struct {
 	}
 	getRegistryImageInventoryReturns struct {
 		result1 registry.RegInvImage
-		result2 error
+		result2 map[image.Digest]registry.ManifestDetails
+		result3 error
 	}
 	getRegistryImageInventoryReturnsOnCall map[int]struct {
 		result1 registry.RegInvImage
-		result2 error
+		result2 map[image.Digest]registry.ManifestDetails
+		result3 error
 	}
-	GetSignatureStatusStub        func(*imagepromotera.Options, []string) (checkresults.Signature, error)
+	GetSignatureStatusStub        func(context.Context, *imagepromotera.Options, []checkresults.Image) (checkresults.Signature, error)
 	getSignatureStatusMutex       sync.RWMutex
 	getSignatureStatusArgsForCall []struct {
-		arg1 *imagepromotera.Options
-		arg2 []string
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []checkresults.Image
 	}
 	getSignatureStatusReturns struct {
 		result1 checkresults.Signature
@@ -151,6 +242,33 @@ type FakePromoterImplementation struct {
 		result1 *registry.Context
 		result2 error
 	}
+	GetUnsignedEdgesStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) (map[promotion.Edge]any, error)
+	getUnsignedEdgesMutex       sync.RWMutex
+	getUnsignedEdgesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}
+	getUnsignedEdgesReturns struct {
+		result1 map[promotion.Edge]any
+		result2 error
+	}
+	getUnsignedEdgesReturnsOnCall map[int]struct {
+		result1 map[promotion.Edge]any
+		result2 error
+	}
+	ImportImagesStub        func(context.Context, *imagepromotera.Options) error
+	importImagesMutex       sync.RWMutex
+	importImagesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+	}
+	importImagesReturns struct {
+		result1 error
+	}
+	importImagesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ParseManifestsStub        func(*imagepromotera.Options) ([]schema.Manifest, error)
 	parseManifestsMutex       sync.RWMutex
 	parseManifestsArgsForCall []struct {
@@ -164,6 +282,21 @@ type FakePromoterImplementation struct {
 		result1 []schema.Manifest
 		result2 error
 	}
+	PlanStagingGCStub        func(context.Context, *imagepromotera.Options, []schema.Manifest) (*retention.Report, error)
+	planStagingGCMutex       sync.RWMutex
+	planStagingGCArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}
+	planStagingGCReturns struct {
+		result1 *retention.Report
+		result2 error
+	}
+	planStagingGCReturnsOnCall map[int]struct {
+		result1 *retention.Report
+		result2 error
+	}
 	PrewarmTUFCacheStub        func(context.Context) error
 	prewarmTUFCacheMutex       sync.RWMutex
 	prewarmTUFCacheArgsForCall []struct {
@@ -209,6 +342,21 @@ type FakePromoterImplementation struct {
 	scanEdgesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ScanPromotionEdgesStub        func(context.Context, *imagepromotera.Options, map[promotion.Edge]any) (*vuln.ScanReport, error)
+	scanPromotionEdgesMutex       sync.RWMutex
+	scanPromotionEdgesArgsForCall []struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 map[promotion.Edge]any
+	}
+	scanPromotionEdgesReturns struct {
+		result1 *vuln.ScanReport
+		result2 error
+	}
+	scanPromotionEdgesReturnsOnCall map[int]struct {
+		result1 *vuln.ScanReport
+		result2 error
+	}
 	SignImagesStub        func(*imagepromotera.Options, map[promotion.Edge]any) error
 	signImagesMutex       sync.RWMutex
 	signImagesArgsForCall []struct {
@@ -221,11 +369,12 @@ type FakePromoterImplementation struct {
 	signImagesReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SnapshotStub        func(*imagepromotera.Options, registry.RegInvImage) error
+	SnapshotStub        func(*imagepromotera.Options, registry.RegInvImage, map[image.Digest]registry.ManifestDetails) error
 	snapshotMutex       sync.RWMutex
 	snapshotArgsForCall []struct {
 		arg1 *imagepromotera.Options
 		arg2 registry.RegInvImage
+		arg3 map[image.Digest]registry.ManifestDetails
 	}
 	snapshotReturns struct {
 		result1 error
@@ -244,18 +393,42 @@ type FakePromoterImplementation struct {
 	validateOptionsReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ValidateStagingSignaturesStub        func(map[promotion.Edge]any) (map[promotion.Edge]any, error)
+	ValidateStagingSignaturesStub        func(*imagepromotera.Options, []schema.Manifest, map[promotion.Edge]any) error
 	validateStagingSignaturesMutex       sync.RWMutex
 	validateStagingSignaturesArgsForCall []struct {
-		arg1 map[promotion.Edge]any
+		arg1 *imagepromotera.Options
+		arg2 []schema.Manifest
+		arg3 map[promotion.Edge]any
 	}
 	validateStagingSignaturesReturns struct {
-		result1 map[promotion.Edge]any
-		result2 error
+		result1 error
 	}
 	validateStagingSignaturesReturnsOnCall map[int]struct {
-		result1 map[promotion.Edge]any
-		result2 error
+		result1 error
+	}
+	WriteAuditReportStub        func(*imagepromotera.Options, *audit.Report) error
+	writeAuditReportMutex       sync.RWMutex
+	writeAuditReportArgsForCall []struct {
+		arg1 *imagepromotera.Options
+		arg2 *audit.Report
+	}
+	writeAuditReportReturns struct {
+		result1 error
+	}
+	writeAuditReportReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteMirrorReportStub        func(*imagepromotera.Options, *mirrorcheck.Report) error
+	writeMirrorReportMutex       sync.RWMutex
+	writeMirrorReportArgsForCall []struct {
+		arg1 *imagepromotera.Options
+		arg2 *mirrorcheck.Report
+	}
+	writeMirrorReportReturns struct {
+		result1 error
+	}
+	writeMirrorReportReturnsOnCall map[int]struct {
+		result1 error
 	}
 	WriteProvenanceAttestationsStub        func(context.Context, *imagepromotera.Options, map[promotion.Edge]any, provenance.Generator) error
 	writeProvenanceAttestationsMutex       sync.RWMutex
@@ -271,6 +444,30 @@ type FakePromoterImplementation struct {
 	writeProvenanceAttestationsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	WriteSignatureReportStub        func(*imagepromotera.Options, checkresults.Signature) error
+	writeSignatureReportMutex       sync.RWMutex
+	writeSignatureReportArgsForCall []struct {
+		arg1 *imagepromotera.Options
+		arg2 checkresults.Signature
+	}
+	writeSignatureReportReturns struct {
+		result1 error
+	}
+	writeSignatureReportReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WriteStagingGCReportStub        func(*imagepromotera.Options, *retention.Report) error
+	writeStagingGCReportMutex       sync.RWMutex
+	writeStagingGCReportArgsForCall []struct {
+		arg1 *imagepromotera.Options
+		arg2 *retention.Report
+	}
+	writeStagingGCReportReturns struct {
+		result1 error
+	}
+	writeStagingGCReportReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -345,6 +542,335 @@ func (fake *FakePromoterImplementation) AppendManifestToSnapshotReturnsOnCall(i
 	}{result1, result2}
 }
 
+func (fake *FakePromoterImplementation) AuditRegistries(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []schema.Manifest) (*audit.Report, error) {
+	var arg3Copy []schema.Manifest
+	if arg3 != nil {
+		arg3Copy = make([]schema.Manifest, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.auditRegistriesMutex.Lock()
+	ret, specificReturn := fake.auditRegistriesReturnsOnCall[len(fake.auditRegistriesArgsForCall)]
+	fake.auditRegistriesArgsForCall = append(fake.auditRegistriesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}{arg1, arg2, arg3Copy})
+	stub := fake.AuditRegistriesStub
+	fakeReturns := fake.auditRegistriesReturns
+	fake.recordInvocation("AuditRegistries", []interface{}{arg1, arg2, arg3Copy})
+	fake.auditRegistriesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromoterImplementation) AuditRegistriesCallCount() int {
+	fake.auditRegistriesMutex.RLock()
+	defer fake.auditRegistriesMutex.RUnlock()
+	return len(fake.auditRegistriesArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) AuditRegistriesCalls(stub func(context.Context, *imagepromotera.Options, []schema.Manifest) (*audit.Report, error)) {
+	fake.auditRegistriesMutex.Lock()
+	defer fake.auditRegistriesMutex.Unlock()
+	fake.AuditRegistriesStub = stub
+}
+
+func (fake *FakePromoterImplementation) AuditRegistriesArgsForCall(i int) (context.Context, *imagepromotera.Options, []schema.Manifest) {
+	fake.auditRegistriesMutex.RLock()
+	defer fake.auditRegistriesMutex.RUnlock()
+	argsForCall := fake.auditRegistriesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) AuditRegistriesReturns(result1 *audit.Report, result2 error) {
+	fake.auditRegistriesMutex.Lock()
+	defer fake.auditRegistriesMutex.Unlock()
+	fake.AuditRegistriesStub = nil
+	fake.auditRegistriesReturns = struct {
+		result1 *audit.Report
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) AuditRegistriesReturnsOnCall(i int, result1 *audit.Report, result2 error) {
+	fake.auditRegistriesMutex.Lock()
+	defer fake.auditRegistriesMutex.Unlock()
+	fake.AuditRegistriesStub = nil
+	if fake.auditRegistriesReturnsOnCall == nil {
+		fake.auditRegistriesReturnsOnCall = make(map[int]struct {
+			result1 *audit.Report
+			result2 error
+		})
+	}
+	fake.auditRegistriesReturnsOnCall[i] = struct {
+		result1 *audit.Report
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) DeleteStagingImages(arg1 context.Context, arg2 *imagepromotera.Options, arg3 *retention.Report) error {
+	fake.deleteStagingImagesMutex.Lock()
+	ret, specificReturn := fake.deleteStagingImagesReturnsOnCall[len(fake.deleteStagingImagesArgsForCall)]
+	fake.deleteStagingImagesArgsForCall = append(fake.deleteStagingImagesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 *retention.Report
+	}{arg1, arg2, arg3})
+	stub := fake.DeleteStagingImagesStub
+	fakeReturns := fake.deleteStagingImagesReturns
+	fake.recordInvocation("DeleteStagingImages", []interface{}{arg1, arg2, arg3})
+	fake.deleteStagingImagesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) DeleteStagingImagesCallCount() int {
+	fake.deleteStagingImagesMutex.RLock()
+	defer fake.deleteStagingImagesMutex.RUnlock()
+	return len(fake.deleteStagingImagesArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) DeleteStagingImagesCalls(stub func(context.Context, *imagepromotera.Options, *retention.Report) error) {
+	fake.deleteStagingImagesMutex.Lock()
+	defer fake.deleteStagingImagesMutex.Unlock()
+	fake.DeleteStagingImagesStub = stub
+}
+
+func (fake *FakePromoterImplementation) DeleteStagingImagesArgsForCall(i int) (context.Context, *imagepromotera.Options, *retention.Report) {
+	fake.deleteStagingImagesMutex.RLock()
+	defer fake.deleteStagingImagesMutex.RUnlock()
+	argsForCall := fake.deleteStagingImagesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) DeleteStagingImagesReturns(result1 error) {
+	fake.deleteStagingImagesMutex.Lock()
+	defer fake.deleteStagingImagesMutex.Unlock()
+	fake.DeleteStagingImagesStub = nil
+	fake.deleteStagingImagesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) DeleteStagingImagesReturnsOnCall(i int, result1 error) {
+	fake.deleteStagingImagesMutex.Lock()
+	defer fake.deleteStagingImagesMutex.Unlock()
+	fake.DeleteStagingImagesStub = nil
+	if fake.deleteStagingImagesReturnsOnCall == nil {
+		fake.deleteStagingImagesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteStagingImagesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) ExportImages(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []schema.Manifest) error {
+	var arg3Copy []schema.Manifest
+	if arg3 != nil {
+		arg3Copy = make([]schema.Manifest, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.exportImagesMutex.Lock()
+	ret, specificReturn := fake.exportImagesReturnsOnCall[len(fake.exportImagesArgsForCall)]
+	fake.exportImagesArgsForCall = append(fake.exportImagesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}{arg1, arg2, arg3Copy})
+	stub := fake.ExportImagesStub
+	fakeReturns := fake.exportImagesReturns
+	fake.recordInvocation("ExportImages", []interface{}{arg1, arg2, arg3Copy})
+	fake.exportImagesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) ExportImagesCallCount() int {
+	fake.exportImagesMutex.RLock()
+	defer fake.exportImagesMutex.RUnlock()
+	return len(fake.exportImagesArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) ExportImagesCalls(stub func(context.Context, *imagepromotera.Options, []schema.Manifest) error) {
+	fake.exportImagesMutex.Lock()
+	defer fake.exportImagesMutex.Unlock()
+	fake.ExportImagesStub = stub
+}
+
+func (fake *FakePromoterImplementation) ExportImagesArgsForCall(i int) (context.Context, *imagepromotera.Options, []schema.Manifest) {
+	fake.exportImagesMutex.RLock()
+	defer fake.exportImagesMutex.RUnlock()
+	argsForCall := fake.exportImagesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) ExportImagesReturns(result1 error) {
+	fake.exportImagesMutex.Lock()
+	defer fake.exportImagesMutex.Unlock()
+	fake.ExportImagesStub = nil
+	fake.exportImagesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) ExportImagesReturnsOnCall(i int, result1 error) {
+	fake.exportImagesMutex.Lock()
+	defer fake.exportImagesMutex.Unlock()
+	fake.ExportImagesStub = nil
+	if fake.exportImagesReturnsOnCall == nil {
+		fake.exportImagesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.exportImagesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) FixMirrors(arg1 context.Context, arg2 *imagepromotera.Options, arg3 *mirrorcheck.Report) error {
+	fake.fixMirrorsMutex.Lock()
+	ret, specificReturn := fake.fixMirrorsReturnsOnCall[len(fake.fixMirrorsArgsForCall)]
+	fake.fixMirrorsArgsForCall = append(fake.fixMirrorsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 *mirrorcheck.Report
+	}{arg1, arg2, arg3})
+	stub := fake.FixMirrorsStub
+	fakeReturns := fake.fixMirrorsReturns
+	fake.recordInvocation("FixMirrors", []interface{}{arg1, arg2, arg3})
+	fake.fixMirrorsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) FixMirrorsCallCount() int {
+	fake.fixMirrorsMutex.RLock()
+	defer fake.fixMirrorsMutex.RUnlock()
+	return len(fake.fixMirrorsArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) FixMirrorsCalls(stub func(context.Context, *imagepromotera.Options, *mirrorcheck.Report) error) {
+	fake.fixMirrorsMutex.Lock()
+	defer fake.fixMirrorsMutex.Unlock()
+	fake.FixMirrorsStub = stub
+}
+
+func (fake *FakePromoterImplementation) FixMirrorsArgsForCall(i int) (context.Context, *imagepromotera.Options, *mirrorcheck.Report) {
+	fake.fixMirrorsMutex.RLock()
+	defer fake.fixMirrorsMutex.RUnlock()
+	argsForCall := fake.fixMirrorsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) FixMirrorsReturns(result1 error) {
+	fake.fixMirrorsMutex.Lock()
+	defer fake.fixMirrorsMutex.Unlock()
+	fake.FixMirrorsStub = nil
+	fake.fixMirrorsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) FixMirrorsReturnsOnCall(i int, result1 error) {
+	fake.fixMirrorsMutex.Lock()
+	defer fake.fixMirrorsMutex.Unlock()
+	fake.FixMirrorsStub = nil
+	if fake.fixMirrorsReturnsOnCall == nil {
+		fake.fixMirrorsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.fixMirrorsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) FixMissingAttestations(arg1 context.Context, arg2 *imagepromotera.Options, arg3 checkresults.Signature, arg4 provenance.Generator) error {
+	fake.fixMissingAttestationsMutex.Lock()
+	ret, specificReturn := fake.fixMissingAttestationsReturnsOnCall[len(fake.fixMissingAttestationsArgsForCall)]
+	fake.fixMissingAttestationsArgsForCall = append(fake.fixMissingAttestationsArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 checkresults.Signature
+		arg4 provenance.Generator
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.FixMissingAttestationsStub
+	fakeReturns := fake.fixMissingAttestationsReturns
+	fake.recordInvocation("FixMissingAttestations", []interface{}{arg1, arg2, arg3, arg4})
+	fake.fixMissingAttestationsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) FixMissingAttestationsCallCount() int {
+	fake.fixMissingAttestationsMutex.RLock()
+	defer fake.fixMissingAttestationsMutex.RUnlock()
+	return len(fake.fixMissingAttestationsArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) FixMissingAttestationsCalls(stub func(context.Context, *imagepromotera.Options, checkresults.Signature, provenance.Generator) error) {
+	fake.fixMissingAttestationsMutex.Lock()
+	defer fake.fixMissingAttestationsMutex.Unlock()
+	fake.FixMissingAttestationsStub = stub
+}
+
+func (fake *FakePromoterImplementation) FixMissingAttestationsArgsForCall(i int) (context.Context, *imagepromotera.Options, checkresults.Signature, provenance.Generator) {
+	fake.fixMissingAttestationsMutex.RLock()
+	defer fake.fixMissingAttestationsMutex.RUnlock()
+	argsForCall := fake.fixMissingAttestationsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePromoterImplementation) FixMissingAttestationsReturns(result1 error) {
+	fake.fixMissingAttestationsMutex.Lock()
+	defer fake.fixMissingAttestationsMutex.Unlock()
+	fake.FixMissingAttestationsStub = nil
+	fake.fixMissingAttestationsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) FixMissingAttestationsReturnsOnCall(i int, result1 error) {
+	fake.fixMissingAttestationsMutex.Lock()
+	defer fake.fixMissingAttestationsMutex.Unlock()
+	fake.FixMissingAttestationsStub = nil
+	if fake.fixMissingAttestationsReturnsOnCall == nil {
+		fake.fixMissingAttestationsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.fixMissingAttestationsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePromoterImplementation) FixMissingSignatures(arg1 *imagepromotera.Options, arg2 checkresults.Signature) error {
 	fake.fixMissingSignaturesMutex.Lock()
 	ret, specificReturn := fake.fixMissingSignaturesReturnsOnCall[len(fake.fixMissingSignaturesArgsForCall)]
@@ -469,7 +995,7 @@ func (fake *FakePromoterImplementation) FixPartialSignaturesReturnsOnCall(i int,
 	}{result1}
 }
 
-func (fake *FakePromoterImplementation) GetLatestImages(arg1 *imagepromotera.Options) ([]string, error) {
+func (fake *FakePromoterImplementation) GetLatestImages(arg1 *imagepromotera.Options) ([]checkresults.Image, error) {
 	fake.getLatestImagesMutex.Lock()
 	ret, specificReturn := fake.getLatestImagesReturnsOnCall[len(fake.getLatestImagesArgsForCall)]
 	fake.getLatestImagesArgsForCall = append(fake.getLatestImagesArgsForCall, struct {
@@ -494,7 +1020,7 @@ func (fake *FakePromoterImplementation) GetLatestImagesCallCount() int {
 	return len(fake.getLatestImagesArgsForCall)
 }
 
-func (fake *FakePromoterImplementation) GetLatestImagesCalls(stub func(*imagepromotera.Options) ([]string, error)) {
+func (fake *FakePromoterImplementation) GetLatestImagesCalls(stub func(*imagepromotera.Options) ([]checkresults.Image, error)) {
 	fake.getLatestImagesMutex.Lock()
 	defer fake.getLatestImagesMutex.Unlock()
 	fake.GetLatestImagesStub = stub
@@ -507,28 +1033,99 @@ func (fake *FakePromoterImplementation) GetLatestImagesArgsForCall(i int) *image
 	return argsForCall.arg1
 }
 
-func (fake *FakePromoterImplementation) GetLatestImagesReturns(result1 []string, result2 error) {
+func (fake *FakePromoterImplementation) GetLatestImagesReturns(result1 []checkresults.Image, result2 error) {
 	fake.getLatestImagesMutex.Lock()
 	defer fake.getLatestImagesMutex.Unlock()
 	fake.GetLatestImagesStub = nil
 	fake.getLatestImagesReturns = struct {
-		result1 []string
+		result1 []checkresults.Image
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakePromoterImplementation) GetLatestImagesReturnsOnCall(i int, result1 []string, result2 error) {
+func (fake *FakePromoterImplementation) GetLatestImagesReturnsOnCall(i int, result1 []checkresults.Image, result2 error) {
 	fake.getLatestImagesMutex.Lock()
 	defer fake.getLatestImagesMutex.Unlock()
 	fake.GetLatestImagesStub = nil
 	if fake.getLatestImagesReturnsOnCall == nil {
 		fake.getLatestImagesReturnsOnCall = make(map[int]struct {
-			result1 []string
+			result1 []checkresults.Image
 			result2 error
 		})
 	}
 	fake.getLatestImagesReturnsOnCall[i] = struct {
-		result1 []string
+		result1 []checkresults.Image
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) GetMirrorStatus(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []schema.Manifest) (*mirrorcheck.Report, error) {
+	var arg3Copy []schema.Manifest
+	if arg3 != nil {
+		arg3Copy = make([]schema.Manifest, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.getMirrorStatusMutex.Lock()
+	ret, specificReturn := fake.getMirrorStatusReturnsOnCall[len(fake.getMirrorStatusArgsForCall)]
+	fake.getMirrorStatusArgsForCall = append(fake.getMirrorStatusArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}{arg1, arg2, arg3Copy})
+	stub := fake.GetMirrorStatusStub
+	fakeReturns := fake.getMirrorStatusReturns
+	fake.recordInvocation("GetMirrorStatus", []interface{}{arg1, arg2, arg3Copy})
+	fake.getMirrorStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromoterImplementation) GetMirrorStatusCallCount() int {
+	fake.getMirrorStatusMutex.RLock()
+	defer fake.getMirrorStatusMutex.RUnlock()
+	return len(fake.getMirrorStatusArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) GetMirrorStatusCalls(stub func(context.Context, *imagepromotera.Options, []schema.Manifest) (*mirrorcheck.Report, error)) {
+	fake.getMirrorStatusMutex.Lock()
+	defer fake.getMirrorStatusMutex.Unlock()
+	fake.GetMirrorStatusStub = stub
+}
+
+func (fake *FakePromoterImplementation) GetMirrorStatusArgsForCall(i int) (context.Context, *imagepromotera.Options, []schema.Manifest) {
+	fake.getMirrorStatusMutex.RLock()
+	defer fake.getMirrorStatusMutex.RUnlock()
+	argsForCall := fake.getMirrorStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) GetMirrorStatusReturns(result1 *mirrorcheck.Report, result2 error) {
+	fake.getMirrorStatusMutex.Lock()
+	defer fake.getMirrorStatusMutex.Unlock()
+	fake.GetMirrorStatusStub = nil
+	fake.getMirrorStatusReturns = struct {
+		result1 *mirrorcheck.Report
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) GetMirrorStatusReturnsOnCall(i int, result1 *mirrorcheck.Report, result2 error) {
+	fake.getMirrorStatusMutex.Lock()
+	defer fake.getMirrorStatusMutex.Unlock()
+	fake.GetMirrorStatusStub = nil
+	if fake.getMirrorStatusReturnsOnCall == nil {
+		fake.getMirrorStatusReturnsOnCall = make(map[int]struct {
+			result1 *mirrorcheck.Report
+			result2 error
+		})
+	}
+	fake.getMirrorStatusReturnsOnCall[i] = struct {
+		result1 *mirrorcheck.Report
 		result2 error
 	}{result1, result2}
 }
@@ -604,7 +1201,7 @@ func (fake *FakePromoterImplementation) GetPromotionEdgesReturnsOnCall(i int, re
 	}{result1, result2}
 }
 
-func (fake *FakePromoterImplementation) GetRegistryImageInventory(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []schema.Manifest) (registry.RegInvImage, error) {
+func (fake *FakePromoterImplementation) GetRegistryImageInventory(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []schema.Manifest) (registry.RegInvImage, map[image.Digest]registry.ManifestDetails, error) {
 	var arg3Copy []schema.Manifest
 	if arg3 != nil {
 		arg3Copy = make([]schema.Manifest, len(arg3))
@@ -625,9 +1222,9 @@ func (fake *FakePromoterImplementation) GetRegistryImageInventory(arg1 context.C
 		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *FakePromoterImplementation) GetRegistryImageInventoryCallCount() int {
@@ -636,7 +1233,7 @@ func (fake *FakePromoterImplementation) GetRegistryImageInventoryCallCount() int
 	return len(fake.getRegistryImageInventoryArgsForCall)
 }
 
-func (fake *FakePromoterImplementation) GetRegistryImageInventoryCalls(stub func(context.Context, *imagepromotera.Options, []schema.Manifest) (registry.RegInvImage, error)) {
+func (fake *FakePromoterImplementation) GetRegistryImageInventoryCalls(stub func(context.Context, *imagepromotera.Options, []schema.Manifest) (registry.RegInvImage, map[image.Digest]registry.ManifestDetails, error)) {
 	fake.getRegistryImageInventoryMutex.Lock()
 	defer fake.getRegistryImageInventoryMutex.Unlock()
 	fake.GetRegistryImageInventoryStub = stub
@@ -649,50 +1246,54 @@ func (fake *FakePromoterImplementation) GetRegistryImageInventoryArgsForCall(i i
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakePromoterImplementation) GetRegistryImageInventoryReturns(result1 registry.RegInvImage, result2 error) {
+func (fake *FakePromoterImplementation) GetRegistryImageInventoryReturns(result1 registry.RegInvImage, result2 map[image.Digest]registry.ManifestDetails, result3 error) {
 	fake.getRegistryImageInventoryMutex.Lock()
 	defer fake.getRegistryImageInventoryMutex.Unlock()
 	fake.GetRegistryImageInventoryStub = nil
 	fake.getRegistryImageInventoryReturns = struct {
 		result1 registry.RegInvImage
-		result2 error
-	}{result1, result2}
+		result2 map[image.Digest]registry.ManifestDetails
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakePromoterImplementation) GetRegistryImageInventoryReturnsOnCall(i int, result1 registry.RegInvImage, result2 error) {
+func (fake *FakePromoterImplementation) GetRegistryImageInventoryReturnsOnCall(i int, result1 registry.RegInvImage, result2 map[image.Digest]registry.ManifestDetails, result3 error) {
 	fake.getRegistryImageInventoryMutex.Lock()
 	defer fake.getRegistryImageInventoryMutex.Unlock()
 	fake.GetRegistryImageInventoryStub = nil
 	if fake.getRegistryImageInventoryReturnsOnCall == nil {
 		fake.getRegistryImageInventoryReturnsOnCall = make(map[int]struct {
 			result1 registry.RegInvImage
-			result2 error
+			result2 map[image.Digest]registry.ManifestDetails
+			result3 error
 		})
 	}
 	fake.getRegistryImageInventoryReturnsOnCall[i] = struct {
 		result1 registry.RegInvImage
-		result2 error
-	}{result1, result2}
+		result2 map[image.Digest]registry.ManifestDetails
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *FakePromoterImplementation) GetSignatureStatus(arg1 *imagepromotera.Options, arg2 []string) (checkresults.Signature, error) {
-	var arg2Copy []string
-	if arg2 != nil {
-		arg2Copy = make([]string, len(arg2))
-		copy(arg2Copy, arg2)
+func (fake *FakePromoterImplementation) GetSignatureStatus(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []checkresults.Image) (checkresults.Signature, error) {
+	var arg3Copy []checkresults.Image
+	if arg3 != nil {
+		arg3Copy = make([]checkresults.Image, len(arg3))
+		copy(arg3Copy, arg3)
 	}
 	fake.getSignatureStatusMutex.Lock()
 	ret, specificReturn := fake.getSignatureStatusReturnsOnCall[len(fake.getSignatureStatusArgsForCall)]
 	fake.getSignatureStatusArgsForCall = append(fake.getSignatureStatusArgsForCall, struct {
-		arg1 *imagepromotera.Options
-		arg2 []string
-	}{arg1, arg2Copy})
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []checkresults.Image
+	}{arg1, arg2, arg3Copy})
 	stub := fake.GetSignatureStatusStub
 	fakeReturns := fake.getSignatureStatusReturns
-	fake.recordInvocation("GetSignatureStatus", []interface{}{arg1, arg2Copy})
+	fake.recordInvocation("GetSignatureStatus", []interface{}{arg1, arg2, arg3Copy})
 	fake.getSignatureStatusMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -706,17 +1307,17 @@ func (fake *FakePromoterImplementation) GetSignatureStatusCallCount() int {
 	return len(fake.getSignatureStatusArgsForCall)
 }
 
-func (fake *FakePromoterImplementation) GetSignatureStatusCalls(stub func(*imagepromotera.Options, []string) (checkresults.Signature, error)) {
+func (fake *FakePromoterImplementation) GetSignatureStatusCalls(stub func(context.Context, *imagepromotera.Options, []checkresults.Image) (checkresults.Signature, error)) {
 	fake.getSignatureStatusMutex.Lock()
 	defer fake.getSignatureStatusMutex.Unlock()
 	fake.GetSignatureStatusStub = stub
 }
 
-func (fake *FakePromoterImplementation) GetSignatureStatusArgsForCall(i int) (*imagepromotera.Options, []string) {
+func (fake *FakePromoterImplementation) GetSignatureStatusArgsForCall(i int) (context.Context, *imagepromotera.Options, []checkresults.Image) {
 	fake.getSignatureStatusMutex.RLock()
 	defer fake.getSignatureStatusMutex.RUnlock()
 	argsForCall := fake.getSignatureStatusArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakePromoterImplementation) GetSignatureStatusReturns(result1 checkresults.Signature, result2 error) {
@@ -834,43 +1435,176 @@ func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryCallCount() int
 	return len(fake.getSnapshotSourceRegistryArgsForCall)
 }
 
-func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryCalls(stub func(*imagepromotera.Options) (*registry.Context, error)) {
-	fake.getSnapshotSourceRegistryMutex.Lock()
-	defer fake.getSnapshotSourceRegistryMutex.Unlock()
-	fake.GetSnapshotSourceRegistryStub = stub
+func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryCalls(stub func(*imagepromotera.Options) (*registry.Context, error)) {
+	fake.getSnapshotSourceRegistryMutex.Lock()
+	defer fake.getSnapshotSourceRegistryMutex.Unlock()
+	fake.GetSnapshotSourceRegistryStub = stub
+}
+
+func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryArgsForCall(i int) *imagepromotera.Options {
+	fake.getSnapshotSourceRegistryMutex.RLock()
+	defer fake.getSnapshotSourceRegistryMutex.RUnlock()
+	argsForCall := fake.getSnapshotSourceRegistryArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryReturns(result1 *registry.Context, result2 error) {
+	fake.getSnapshotSourceRegistryMutex.Lock()
+	defer fake.getSnapshotSourceRegistryMutex.Unlock()
+	fake.GetSnapshotSourceRegistryStub = nil
+	fake.getSnapshotSourceRegistryReturns = struct {
+		result1 *registry.Context
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryReturnsOnCall(i int, result1 *registry.Context, result2 error) {
+	fake.getSnapshotSourceRegistryMutex.Lock()
+	defer fake.getSnapshotSourceRegistryMutex.Unlock()
+	fake.GetSnapshotSourceRegistryStub = nil
+	if fake.getSnapshotSourceRegistryReturnsOnCall == nil {
+		fake.getSnapshotSourceRegistryReturnsOnCall = make(map[int]struct {
+			result1 *registry.Context
+			result2 error
+		})
+	}
+	fake.getSnapshotSourceRegistryReturnsOnCall[i] = struct {
+		result1 *registry.Context
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) GetUnsignedEdges(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []schema.Manifest) (map[promotion.Edge]any, error) {
+	var arg3Copy []schema.Manifest
+	if arg3 != nil {
+		arg3Copy = make([]schema.Manifest, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.getUnsignedEdgesMutex.Lock()
+	ret, specificReturn := fake.getUnsignedEdgesReturnsOnCall[len(fake.getUnsignedEdgesArgsForCall)]
+	fake.getUnsignedEdgesArgsForCall = append(fake.getUnsignedEdgesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}{arg1, arg2, arg3Copy})
+	stub := fake.GetUnsignedEdgesStub
+	fakeReturns := fake.getUnsignedEdgesReturns
+	fake.recordInvocation("GetUnsignedEdges", []interface{}{arg1, arg2, arg3Copy})
+	fake.getUnsignedEdgesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromoterImplementation) GetUnsignedEdgesCallCount() int {
+	fake.getUnsignedEdgesMutex.RLock()
+	defer fake.getUnsignedEdgesMutex.RUnlock()
+	return len(fake.getUnsignedEdgesArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) GetUnsignedEdgesCalls(stub func(context.Context, *imagepromotera.Options, []schema.Manifest) (map[promotion.Edge]any, error)) {
+	fake.getUnsignedEdgesMutex.Lock()
+	defer fake.getUnsignedEdgesMutex.Unlock()
+	fake.GetUnsignedEdgesStub = stub
+}
+
+func (fake *FakePromoterImplementation) GetUnsignedEdgesArgsForCall(i int) (context.Context, *imagepromotera.Options, []schema.Manifest) {
+	fake.getUnsignedEdgesMutex.RLock()
+	defer fake.getUnsignedEdgesMutex.RUnlock()
+	argsForCall := fake.getUnsignedEdgesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) GetUnsignedEdgesReturns(result1 map[promotion.Edge]any, result2 error) {
+	fake.getUnsignedEdgesMutex.Lock()
+	defer fake.getUnsignedEdgesMutex.Unlock()
+	fake.GetUnsignedEdgesStub = nil
+	fake.getUnsignedEdgesReturns = struct {
+		result1 map[promotion.Edge]any
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) GetUnsignedEdgesReturnsOnCall(i int, result1 map[promotion.Edge]any, result2 error) {
+	fake.getUnsignedEdgesMutex.Lock()
+	defer fake.getUnsignedEdgesMutex.Unlock()
+	fake.GetUnsignedEdgesStub = nil
+	if fake.getUnsignedEdgesReturnsOnCall == nil {
+		fake.getUnsignedEdgesReturnsOnCall = make(map[int]struct {
+			result1 map[promotion.Edge]any
+			result2 error
+		})
+	}
+	fake.getUnsignedEdgesReturnsOnCall[i] = struct {
+		result1 map[promotion.Edge]any
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) ImportImages(arg1 context.Context, arg2 *imagepromotera.Options) error {
+	fake.importImagesMutex.Lock()
+	ret, specificReturn := fake.importImagesReturnsOnCall[len(fake.importImagesArgsForCall)]
+	fake.importImagesArgsForCall = append(fake.importImagesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+	}{arg1, arg2})
+	stub := fake.ImportImagesStub
+	fakeReturns := fake.importImagesReturns
+	fake.recordInvocation("ImportImages", []interface{}{arg1, arg2})
+	fake.importImagesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) ImportImagesCallCount() int {
+	fake.importImagesMutex.RLock()
+	defer fake.importImagesMutex.RUnlock()
+	return len(fake.importImagesArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) ImportImagesCalls(stub func(context.Context, *imagepromotera.Options) error) {
+	fake.importImagesMutex.Lock()
+	defer fake.importImagesMutex.Unlock()
+	fake.ImportImagesStub = stub
 }
 
-func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryArgsForCall(i int) *imagepromotera.Options {
-	fake.getSnapshotSourceRegistryMutex.RLock()
-	defer fake.getSnapshotSourceRegistryMutex.RUnlock()
-	argsForCall := fake.getSnapshotSourceRegistryArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakePromoterImplementation) ImportImagesArgsForCall(i int) (context.Context, *imagepromotera.Options) {
+	fake.importImagesMutex.RLock()
+	defer fake.importImagesMutex.RUnlock()
+	argsForCall := fake.importImagesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryReturns(result1 *registry.Context, result2 error) {
-	fake.getSnapshotSourceRegistryMutex.Lock()
-	defer fake.getSnapshotSourceRegistryMutex.Unlock()
-	fake.GetSnapshotSourceRegistryStub = nil
-	fake.getSnapshotSourceRegistryReturns = struct {
-		result1 *registry.Context
-		result2 error
-	}{result1, result2}
+func (fake *FakePromoterImplementation) ImportImagesReturns(result1 error) {
+	fake.importImagesMutex.Lock()
+	defer fake.importImagesMutex.Unlock()
+	fake.ImportImagesStub = nil
+	fake.importImagesReturns = struct {
+		result1 error
+	}{result1}
 }
 
-func (fake *FakePromoterImplementation) GetSnapshotSourceRegistryReturnsOnCall(i int, result1 *registry.Context, result2 error) {
-	fake.getSnapshotSourceRegistryMutex.Lock()
-	defer fake.getSnapshotSourceRegistryMutex.Unlock()
-	fake.GetSnapshotSourceRegistryStub = nil
-	if fake.getSnapshotSourceRegistryReturnsOnCall == nil {
-		fake.getSnapshotSourceRegistryReturnsOnCall = make(map[int]struct {
-			result1 *registry.Context
-			result2 error
+func (fake *FakePromoterImplementation) ImportImagesReturnsOnCall(i int, result1 error) {
+	fake.importImagesMutex.Lock()
+	defer fake.importImagesMutex.Unlock()
+	fake.ImportImagesStub = nil
+	if fake.importImagesReturnsOnCall == nil {
+		fake.importImagesReturnsOnCall = make(map[int]struct {
+			result1 error
 		})
 	}
-	fake.getSnapshotSourceRegistryReturnsOnCall[i] = struct {
-		result1 *registry.Context
-		result2 error
-	}{result1, result2}
+	fake.importImagesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
 func (fake *FakePromoterImplementation) ParseManifests(arg1 *imagepromotera.Options) ([]schema.Manifest, error) {
@@ -937,6 +1671,77 @@ func (fake *FakePromoterImplementation) ParseManifestsReturnsOnCall(i int, resul
 	}{result1, result2}
 }
 
+func (fake *FakePromoterImplementation) PlanStagingGC(arg1 context.Context, arg2 *imagepromotera.Options, arg3 []schema.Manifest) (*retention.Report, error) {
+	var arg3Copy []schema.Manifest
+	if arg3 != nil {
+		arg3Copy = make([]schema.Manifest, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.planStagingGCMutex.Lock()
+	ret, specificReturn := fake.planStagingGCReturnsOnCall[len(fake.planStagingGCArgsForCall)]
+	fake.planStagingGCArgsForCall = append(fake.planStagingGCArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 []schema.Manifest
+	}{arg1, arg2, arg3Copy})
+	stub := fake.PlanStagingGCStub
+	fakeReturns := fake.planStagingGCReturns
+	fake.recordInvocation("PlanStagingGC", []interface{}{arg1, arg2, arg3Copy})
+	fake.planStagingGCMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromoterImplementation) PlanStagingGCCallCount() int {
+	fake.planStagingGCMutex.RLock()
+	defer fake.planStagingGCMutex.RUnlock()
+	return len(fake.planStagingGCArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) PlanStagingGCCalls(stub func(context.Context, *imagepromotera.Options, []schema.Manifest) (*retention.Report, error)) {
+	fake.planStagingGCMutex.Lock()
+	defer fake.planStagingGCMutex.Unlock()
+	fake.PlanStagingGCStub = stub
+}
+
+func (fake *FakePromoterImplementation) PlanStagingGCArgsForCall(i int) (context.Context, *imagepromotera.Options, []schema.Manifest) {
+	fake.planStagingGCMutex.RLock()
+	defer fake.planStagingGCMutex.RUnlock()
+	argsForCall := fake.planStagingGCArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) PlanStagingGCReturns(result1 *retention.Report, result2 error) {
+	fake.planStagingGCMutex.Lock()
+	defer fake.planStagingGCMutex.Unlock()
+	fake.PlanStagingGCStub = nil
+	fake.planStagingGCReturns = struct {
+		result1 *retention.Report
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) PlanStagingGCReturnsOnCall(i int, result1 *retention.Report, result2 error) {
+	fake.planStagingGCMutex.Lock()
+	defer fake.planStagingGCMutex.Unlock()
+	fake.PlanStagingGCStub = nil
+	if fake.planStagingGCReturnsOnCall == nil {
+		fake.planStagingGCReturnsOnCall = make(map[int]struct {
+			result1 *retention.Report
+			result2 error
+		})
+	}
+	fake.planStagingGCReturnsOnCall[i] = struct {
+		result1 *retention.Report
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePromoterImplementation) PrewarmTUFCache(arg1 context.Context) error {
 	fake.prewarmTUFCacheMutex.Lock()
 	ret, specificReturn := fake.prewarmTUFCacheReturnsOnCall[len(fake.prewarmTUFCacheArgsForCall)]
@@ -1172,6 +1977,72 @@ func (fake *FakePromoterImplementation) ScanEdgesReturnsOnCall(i int, result1 er
 	}{result1}
 }
 
+func (fake *FakePromoterImplementation) ScanPromotionEdges(arg1 context.Context, arg2 *imagepromotera.Options, arg3 map[promotion.Edge]any) (*vuln.ScanReport, error) {
+	fake.scanPromotionEdgesMutex.Lock()
+	ret, specificReturn := fake.scanPromotionEdgesReturnsOnCall[len(fake.scanPromotionEdgesArgsForCall)]
+	fake.scanPromotionEdgesArgsForCall = append(fake.scanPromotionEdgesArgsForCall, struct {
+		arg1 context.Context
+		arg2 *imagepromotera.Options
+		arg3 map[promotion.Edge]any
+	}{arg1, arg2, arg3})
+	stub := fake.ScanPromotionEdgesStub
+	fakeReturns := fake.scanPromotionEdgesReturns
+	fake.recordInvocation("ScanPromotionEdges", []interface{}{arg1, arg2, arg3})
+	fake.scanPromotionEdgesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePromoterImplementation) ScanPromotionEdgesCallCount() int {
+	fake.scanPromotionEdgesMutex.RLock()
+	defer fake.scanPromotionEdgesMutex.RUnlock()
+	return len(fake.scanPromotionEdgesArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) ScanPromotionEdgesCalls(stub func(context.Context, *imagepromotera.Options, map[promotion.Edge]any) (*vuln.ScanReport, error)) {
+	fake.scanPromotionEdgesMutex.Lock()
+	defer fake.scanPromotionEdgesMutex.Unlock()
+	fake.ScanPromotionEdgesStub = stub
+}
+
+func (fake *FakePromoterImplementation) ScanPromotionEdgesArgsForCall(i int) (context.Context, *imagepromotera.Options, map[promotion.Edge]any) {
+	fake.scanPromotionEdgesMutex.RLock()
+	defer fake.scanPromotionEdgesMutex.RUnlock()
+	argsForCall := fake.scanPromotionEdgesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePromoterImplementation) ScanPromotionEdgesReturns(result1 *vuln.ScanReport, result2 error) {
+	fake.scanPromotionEdgesMutex.Lock()
+	defer fake.scanPromotionEdgesMutex.Unlock()
+	fake.ScanPromotionEdgesStub = nil
+	fake.scanPromotionEdgesReturns = struct {
+		result1 *vuln.ScanReport
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePromoterImplementation) ScanPromotionEdgesReturnsOnCall(i int, result1 *vuln.ScanReport, result2 error) {
+	fake.scanPromotionEdgesMutex.Lock()
+	defer fake.scanPromotionEdgesMutex.Unlock()
+	fake.ScanPromotionEdgesStub = nil
+	if fake.scanPromotionEdgesReturnsOnCall == nil {
+		fake.scanPromotionEdgesReturnsOnCall = make(map[int]struct {
+			result1 *vuln.ScanReport
+			result2 error
+		})
+	}
+	fake.scanPromotionEdgesReturnsOnCall[i] = struct {
+		result1 *vuln.ScanReport
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakePromoterImplementation) SignImages(arg1 *imagepromotera.Options, arg2 map[promotion.Edge]any) error {
 	fake.signImagesMutex.Lock()
 	ret, specificReturn := fake.signImagesReturnsOnCall[len(fake.signImagesArgsForCall)]
@@ -1234,19 +2105,20 @@ func (fake *FakePromoterImplementation) SignImagesReturnsOnCall(i int, result1 e
 	}{result1}
 }
 
-func (fake *FakePromoterImplementation) Snapshot(arg1 *imagepromotera.Options, arg2 registry.RegInvImage) error {
+func (fake *FakePromoterImplementation) Snapshot(arg1 *imagepromotera.Options, arg2 registry.RegInvImage, arg3 map[image.Digest]registry.ManifestDetails) error {
 	fake.snapshotMutex.Lock()
 	ret, specificReturn := fake.snapshotReturnsOnCall[len(fake.snapshotArgsForCall)]
 	fake.snapshotArgsForCall = append(fake.snapshotArgsForCall, struct {
 		arg1 *imagepromotera.Options
 		arg2 registry.RegInvImage
-	}{arg1, arg2})
+		arg3 map[image.Digest]registry.ManifestDetails
+	}{arg1, arg2, arg3})
 	stub := fake.SnapshotStub
 	fakeReturns := fake.snapshotReturns
-	fake.recordInvocation("Snapshot", []interface{}{arg1, arg2})
+	fake.recordInvocation("Snapshot", []interface{}{arg1, arg2, arg3})
 	fake.snapshotMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -1260,17 +2132,17 @@ func (fake *FakePromoterImplementation) SnapshotCallCount() int {
 	return len(fake.snapshotArgsForCall)
 }
 
-func (fake *FakePromoterImplementation) SnapshotCalls(stub func(*imagepromotera.Options, registry.RegInvImage) error) {
+func (fake *FakePromoterImplementation) SnapshotCalls(stub func(*imagepromotera.Options, registry.RegInvImage, map[image.Digest]registry.ManifestDetails) error) {
 	fake.snapshotMutex.Lock()
 	defer fake.snapshotMutex.Unlock()
 	fake.SnapshotStub = stub
 }
 
-func (fake *FakePromoterImplementation) SnapshotArgsForCall(i int) (*imagepromotera.Options, registry.RegInvImage) {
+func (fake *FakePromoterImplementation) SnapshotArgsForCall(i int) (*imagepromotera.Options, registry.RegInvImage, map[image.Digest]registry.ManifestDetails) {
 	fake.snapshotMutex.RLock()
 	defer fake.snapshotMutex.RUnlock()
 	argsForCall := fake.snapshotArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakePromoterImplementation) SnapshotReturns(result1 error) {
@@ -1357,23 +2229,30 @@ func (fake *FakePromoterImplementation) ValidateOptionsReturnsOnCall(i int, resu
 	}{result1}
 }
 
-func (fake *FakePromoterImplementation) ValidateStagingSignatures(arg1 map[promotion.Edge]any) (map[promotion.Edge]any, error) {
+func (fake *FakePromoterImplementation) ValidateStagingSignatures(arg1 *imagepromotera.Options, arg2 []schema.Manifest, arg3 map[promotion.Edge]any) error {
+	var arg2Copy []schema.Manifest
+	if arg2 != nil {
+		arg2Copy = make([]schema.Manifest, len(arg2))
+		copy(arg2Copy, arg2)
+	}
 	fake.validateStagingSignaturesMutex.Lock()
 	ret, specificReturn := fake.validateStagingSignaturesReturnsOnCall[len(fake.validateStagingSignaturesArgsForCall)]
 	fake.validateStagingSignaturesArgsForCall = append(fake.validateStagingSignaturesArgsForCall, struct {
-		arg1 map[promotion.Edge]any
-	}{arg1})
+		arg1 *imagepromotera.Options
+		arg2 []schema.Manifest
+		arg3 map[promotion.Edge]any
+	}{arg1, arg2Copy, arg3})
 	stub := fake.ValidateStagingSignaturesStub
 	fakeReturns := fake.validateStagingSignaturesReturns
-	fake.recordInvocation("ValidateStagingSignatures", []interface{}{arg1})
+	fake.recordInvocation("ValidateStagingSignatures", []interface{}{arg1, arg2Copy, arg3})
 	fake.validateStagingSignaturesMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1
 }
 
 func (fake *FakePromoterImplementation) ValidateStagingSignaturesCallCount() int {
@@ -1382,43 +2261,164 @@ func (fake *FakePromoterImplementation) ValidateStagingSignaturesCallCount() int
 	return len(fake.validateStagingSignaturesArgsForCall)
 }
 
-func (fake *FakePromoterImplementation) ValidateStagingSignaturesCalls(stub func(map[promotion.Edge]any) (map[promotion.Edge]any, error)) {
+func (fake *FakePromoterImplementation) ValidateStagingSignaturesCalls(stub func(*imagepromotera.Options, []schema.Manifest, map[promotion.Edge]any) error) {
 	fake.validateStagingSignaturesMutex.Lock()
 	defer fake.validateStagingSignaturesMutex.Unlock()
 	fake.ValidateStagingSignaturesStub = stub
 }
 
-func (fake *FakePromoterImplementation) ValidateStagingSignaturesArgsForCall(i int) map[promotion.Edge]any {
+func (fake *FakePromoterImplementation) ValidateStagingSignaturesArgsForCall(i int) (*imagepromotera.Options, []schema.Manifest, map[promotion.Edge]any) {
 	fake.validateStagingSignaturesMutex.RLock()
 	defer fake.validateStagingSignaturesMutex.RUnlock()
 	argsForCall := fake.validateStagingSignaturesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *FakePromoterImplementation) ValidateStagingSignaturesReturns(result1 map[promotion.Edge]any, result2 error) {
+func (fake *FakePromoterImplementation) ValidateStagingSignaturesReturns(result1 error) {
 	fake.validateStagingSignaturesMutex.Lock()
 	defer fake.validateStagingSignaturesMutex.Unlock()
 	fake.ValidateStagingSignaturesStub = nil
 	fake.validateStagingSignaturesReturns = struct {
-		result1 map[promotion.Edge]any
-		result2 error
-	}{result1, result2}
+		result1 error
+	}{result1}
 }
 
-func (fake *FakePromoterImplementation) ValidateStagingSignaturesReturnsOnCall(i int, result1 map[promotion.Edge]any, result2 error) {
+func (fake *FakePromoterImplementation) ValidateStagingSignaturesReturnsOnCall(i int, result1 error) {
 	fake.validateStagingSignaturesMutex.Lock()
 	defer fake.validateStagingSignaturesMutex.Unlock()
 	fake.ValidateStagingSignaturesStub = nil
 	if fake.validateStagingSignaturesReturnsOnCall == nil {
 		fake.validateStagingSignaturesReturnsOnCall = make(map[int]struct {
-			result1 map[promotion.Edge]any
-			result2 error
+			result1 error
 		})
 	}
 	fake.validateStagingSignaturesReturnsOnCall[i] = struct {
-		result1 map[promotion.Edge]any
-		result2 error
-	}{result1, result2}
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) WriteAuditReport(arg1 *imagepromotera.Options, arg2 *audit.Report) error {
+	fake.writeAuditReportMutex.Lock()
+	ret, specificReturn := fake.writeAuditReportReturnsOnCall[len(fake.writeAuditReportArgsForCall)]
+	fake.writeAuditReportArgsForCall = append(fake.writeAuditReportArgsForCall, struct {
+		arg1 *imagepromotera.Options
+		arg2 *audit.Report
+	}{arg1, arg2})
+	stub := fake.WriteAuditReportStub
+	fakeReturns := fake.writeAuditReportReturns
+	fake.recordInvocation("WriteAuditReport", []interface{}{arg1, arg2})
+	fake.writeAuditReportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) WriteAuditReportCallCount() int {
+	fake.writeAuditReportMutex.RLock()
+	defer fake.writeAuditReportMutex.RUnlock()
+	return len(fake.writeAuditReportArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) WriteAuditReportCalls(stub func(*imagepromotera.Options, *audit.Report) error) {
+	fake.writeAuditReportMutex.Lock()
+	defer fake.writeAuditReportMutex.Unlock()
+	fake.WriteAuditReportStub = stub
+}
+
+func (fake *FakePromoterImplementation) WriteAuditReportArgsForCall(i int) (*imagepromotera.Options, *audit.Report) {
+	fake.writeAuditReportMutex.RLock()
+	defer fake.writeAuditReportMutex.RUnlock()
+	argsForCall := fake.writeAuditReportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromoterImplementation) WriteAuditReportReturns(result1 error) {
+	fake.writeAuditReportMutex.Lock()
+	defer fake.writeAuditReportMutex.Unlock()
+	fake.WriteAuditReportStub = nil
+	fake.writeAuditReportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) WriteAuditReportReturnsOnCall(i int, result1 error) {
+	fake.writeAuditReportMutex.Lock()
+	defer fake.writeAuditReportMutex.Unlock()
+	fake.WriteAuditReportStub = nil
+	if fake.writeAuditReportReturnsOnCall == nil {
+		fake.writeAuditReportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeAuditReportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) WriteMirrorReport(arg1 *imagepromotera.Options, arg2 *mirrorcheck.Report) error {
+	fake.writeMirrorReportMutex.Lock()
+	ret, specificReturn := fake.writeMirrorReportReturnsOnCall[len(fake.writeMirrorReportArgsForCall)]
+	fake.writeMirrorReportArgsForCall = append(fake.writeMirrorReportArgsForCall, struct {
+		arg1 *imagepromotera.Options
+		arg2 *mirrorcheck.Report
+	}{arg1, arg2})
+	stub := fake.WriteMirrorReportStub
+	fakeReturns := fake.writeMirrorReportReturns
+	fake.recordInvocation("WriteMirrorReport", []interface{}{arg1, arg2})
+	fake.writeMirrorReportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) WriteMirrorReportCallCount() int {
+	fake.writeMirrorReportMutex.RLock()
+	defer fake.writeMirrorReportMutex.RUnlock()
+	return len(fake.writeMirrorReportArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) WriteMirrorReportCalls(stub func(*imagepromotera.Options, *mirrorcheck.Report) error) {
+	fake.writeMirrorReportMutex.Lock()
+	defer fake.writeMirrorReportMutex.Unlock()
+	fake.WriteMirrorReportStub = stub
+}
+
+func (fake *FakePromoterImplementation) WriteMirrorReportArgsForCall(i int) (*imagepromotera.Options, *mirrorcheck.Report) {
+	fake.writeMirrorReportMutex.RLock()
+	defer fake.writeMirrorReportMutex.RUnlock()
+	argsForCall := fake.writeMirrorReportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromoterImplementation) WriteMirrorReportReturns(result1 error) {
+	fake.writeMirrorReportMutex.Lock()
+	defer fake.writeMirrorReportMutex.Unlock()
+	fake.WriteMirrorReportStub = nil
+	fake.writeMirrorReportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) WriteMirrorReportReturnsOnCall(i int, result1 error) {
+	fake.writeMirrorReportMutex.Lock()
+	defer fake.writeMirrorReportMutex.Unlock()
+	fake.WriteMirrorReportStub = nil
+	if fake.writeMirrorReportReturnsOnCall == nil {
+		fake.writeMirrorReportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeMirrorReportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
 }
 
 func (fake *FakePromoterImplementation) WriteProvenanceAttestations(arg1 context.Context, arg2 *imagepromotera.Options, arg3 map[promotion.Edge]any, arg4 provenance.Generator) error {
@@ -1485,6 +2485,130 @@ func (fake *FakePromoterImplementation) WriteProvenanceAttestationsReturnsOnCall
 	}{result1}
 }
 
+func (fake *FakePromoterImplementation) WriteSignatureReport(arg1 *imagepromotera.Options, arg2 checkresults.Signature) error {
+	fake.writeSignatureReportMutex.Lock()
+	ret, specificReturn := fake.writeSignatureReportReturnsOnCall[len(fake.writeSignatureReportArgsForCall)]
+	fake.writeSignatureReportArgsForCall = append(fake.writeSignatureReportArgsForCall, struct {
+		arg1 *imagepromotera.Options
+		arg2 checkresults.Signature
+	}{arg1, arg2})
+	stub := fake.WriteSignatureReportStub
+	fakeReturns := fake.writeSignatureReportReturns
+	fake.recordInvocation("WriteSignatureReport", []interface{}{arg1, arg2})
+	fake.writeSignatureReportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) WriteSignatureReportCallCount() int {
+	fake.writeSignatureReportMutex.RLock()
+	defer fake.writeSignatureReportMutex.RUnlock()
+	return len(fake.writeSignatureReportArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) WriteSignatureReportCalls(stub func(*imagepromotera.Options, checkresults.Signature) error) {
+	fake.writeSignatureReportMutex.Lock()
+	defer fake.writeSignatureReportMutex.Unlock()
+	fake.WriteSignatureReportStub = stub
+}
+
+func (fake *FakePromoterImplementation) WriteSignatureReportArgsForCall(i int) (*imagepromotera.Options, checkresults.Signature) {
+	fake.writeSignatureReportMutex.RLock()
+	defer fake.writeSignatureReportMutex.RUnlock()
+	argsForCall := fake.writeSignatureReportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromoterImplementation) WriteSignatureReportReturns(result1 error) {
+	fake.writeSignatureReportMutex.Lock()
+	defer fake.writeSignatureReportMutex.Unlock()
+	fake.WriteSignatureReportStub = nil
+	fake.writeSignatureReportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) WriteSignatureReportReturnsOnCall(i int, result1 error) {
+	fake.writeSignatureReportMutex.Lock()
+	defer fake.writeSignatureReportMutex.Unlock()
+	fake.WriteSignatureReportStub = nil
+	if fake.writeSignatureReportReturnsOnCall == nil {
+		fake.writeSignatureReportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeSignatureReportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) WriteStagingGCReport(arg1 *imagepromotera.Options, arg2 *retention.Report) error {
+	fake.writeStagingGCReportMutex.Lock()
+	ret, specificReturn := fake.writeStagingGCReportReturnsOnCall[len(fake.writeStagingGCReportArgsForCall)]
+	fake.writeStagingGCReportArgsForCall = append(fake.writeStagingGCReportArgsForCall, struct {
+		arg1 *imagepromotera.Options
+		arg2 *retention.Report
+	}{arg1, arg2})
+	stub := fake.WriteStagingGCReportStub
+	fakeReturns := fake.writeStagingGCReportReturns
+	fake.recordInvocation("WriteStagingGCReport", []interface{}{arg1, arg2})
+	fake.writeStagingGCReportMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakePromoterImplementation) WriteStagingGCReportCallCount() int {
+	fake.writeStagingGCReportMutex.RLock()
+	defer fake.writeStagingGCReportMutex.RUnlock()
+	return len(fake.writeStagingGCReportArgsForCall)
+}
+
+func (fake *FakePromoterImplementation) WriteStagingGCReportCalls(stub func(*imagepromotera.Options, *retention.Report) error) {
+	fake.writeStagingGCReportMutex.Lock()
+	defer fake.writeStagingGCReportMutex.Unlock()
+	fake.WriteStagingGCReportStub = stub
+}
+
+func (fake *FakePromoterImplementation) WriteStagingGCReportArgsForCall(i int) (*imagepromotera.Options, *retention.Report) {
+	fake.writeStagingGCReportMutex.RLock()
+	defer fake.writeStagingGCReportMutex.RUnlock()
+	argsForCall := fake.writeStagingGCReportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePromoterImplementation) WriteStagingGCReportReturns(result1 error) {
+	fake.writeStagingGCReportMutex.Lock()
+	defer fake.writeStagingGCReportMutex.Unlock()
+	fake.WriteStagingGCReportStub = nil
+	fake.writeStagingGCReportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePromoterImplementation) WriteStagingGCReportReturnsOnCall(i int, result1 error) {
+	fake.writeStagingGCReportMutex.Lock()
+	defer fake.writeStagingGCReportMutex.Unlock()
+	fake.WriteStagingGCReportStub = nil
+	if fake.writeStagingGCReportReturnsOnCall == nil {
+		fake.writeStagingGCReportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.writeStagingGCReportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakePromoterImplementation) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
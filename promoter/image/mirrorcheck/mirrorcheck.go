@@ -0,0 +1,272 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirrorcheck
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// Kind is the kind of a mirror inconsistency.
+type Kind string
+
+// Kinds of mirror inconsistencies.
+const (
+	// KindMissingDigest is a digest of an image found in other mirrors
+	// but not in this one.
+	KindMissingDigest Kind = "missing-digest"
+
+	// KindMissingTag is a tag of a digest found in other mirrors but not
+	// in this one, which has the digest.
+	KindMissingTag Kind = "missing-tag"
+
+	// KindTagConflict is a tag pointing to different digests in different
+	// mirrors. Conflicts are reported for every mirror with the tag and are
+	// never fixed.
+	KindTagConflict Kind = "tag-conflict"
+)
+
+// Report lists the inconsistencies between mirrors serving the same images.
+type Report struct {
+	// Mirrors are the checked mirrors, sorted by name.
+	Mirrors []image.Registry `json:"mirrors"`
+
+	// Findings lists the inconsistencies, sorted by image, digest, tag and
+	// mirror.
+	Findings []Finding `json:"findings"`
+}
+
+// Finding is an inconsistency of a mirror.
+type Finding struct {
+	Kind   Kind           `json:"kind"`
+	Mirror image.Registry `json:"mirror"`
+	Image  image.Name     `json:"image"`
+	Digest image.Digest   `json:"digest"`
+
+	// Tags are the tags a missing digest has in the other mirrors.
+	Tags registry.TagSlice `json:"tags,omitempty"`
+
+	// Tag is the missing or conflicting tag.
+	Tag image.Tag `json:"tag,omitempty"`
+
+	// Source is the mirror a missing digest or tag is copied from.
+	Source image.Registry `json:"source,omitempty"`
+
+	// Fixed is true when the missing digest or tag was copied.
+	Fixed bool `json:"fixed,omitempty"`
+
+	// Error is set when copying the missing digest or tag failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Fixable returns true if the finding can be fixed by copying the missing
+// digest or tag from Source.
+func (f *Finding) Fixable() bool {
+	return f.Kind != KindTagConflict && f.Source != ""
+}
+
+// NewReport returns an empty report.
+func NewReport() *Report {
+	return &Report{Mirrors: []image.Registry{}, Findings: []Finding{}}
+}
+
+// Check cross-compares the images of a group of mirrors serving the same
+// images, read into inventories keyed by mirror, and adds the
+// inconsistencies to the report. The first mirror in name order having a
+// missing digest is the source to copy it from.
+func (r *Report) Check(
+	mirrors []image.Registry, names []image.Name, inventories map[image.Registry]registry.RegInvImage,
+) {
+	mirrors = slices.Clone(mirrors)
+	slices.Sort(mirrors)
+
+	for _, mirror := range mirrors {
+		if !slices.Contains(r.Mirrors, mirror) {
+			r.Mirrors = append(r.Mirrors, mirror)
+		}
+	}
+
+	slices.Sort(r.Mirrors)
+
+	for _, name := range names {
+		r.checkImage(mirrors, name, inventories)
+	}
+
+	sort.Slice(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+
+		switch {
+		case a.Image != b.Image:
+			return a.Image < b.Image
+		case a.Digest != b.Digest:
+			return a.Digest < b.Digest
+		case a.Tag != b.Tag:
+			return a.Tag < b.Tag
+		default:
+			return a.Mirror < b.Mirror
+		}
+	})
+}
+
+func (r *Report) checkImage(
+	mirrors []image.Registry, name image.Name, inventories map[image.Registry]registry.RegInvImage,
+) {
+	// The mirrors having each digest, and the digests of each tag across
+	// all mirrors.
+	digestMirrors := map[image.Digest][]image.Registry{}
+	digestTags := map[image.Digest]registry.TagSet{}
+	tagDigests := map[image.Tag]map[image.Digest]bool{}
+
+	for _, mirror := range mirrors {
+		for digest, tags := range inventories[mirror][name] {
+			digestMirrors[digest] = append(digestMirrors[digest], mirror)
+
+			if digestTags[digest] == nil {
+				digestTags[digest] = registry.TagSet{}
+			}
+
+			for _, tag := range tags {
+				digestTags[digest][tag] = struct{}{}
+
+				if tagDigests[tag] == nil {
+					tagDigests[tag] = map[image.Digest]bool{}
+				}
+
+				tagDigests[tag][digest] = true
+			}
+		}
+	}
+
+	for tag, digests := range tagDigests {
+		if len(digests) < 2 {
+			continue
+		}
+
+		for _, mirror := range mirrors {
+			for digest, tags := range inventories[mirror][name] {
+				if slices.Contains(tags, tag) {
+					r.Findings = append(r.Findings, Finding{
+						Kind: KindTagConflict, Mirror: mirror, Image: name, Digest: digest, Tag: tag,
+					})
+				}
+			}
+		}
+	}
+
+	for digest, having := range digestMirrors {
+		// Conflicting tags are left alone
+		tags := registry.TagSlice{}
+
+		for _, tag := range digestTags[digest].ToSorted() {
+			if len(tagDigests[tag]) == 1 {
+				tags = append(tags, tag)
+			}
+		}
+
+		for _, mirror := range mirrors {
+			mirrorTags, ok := inventories[mirror][name][digest]
+			if !ok {
+				r.Findings = append(r.Findings, Finding{
+					Kind:   KindMissingDigest,
+					Mirror: mirror,
+					Image:  name,
+					Digest: digest,
+					Tags:   tags,
+					Source: having[0],
+				})
+
+				continue
+			}
+
+			for _, tag := range tags {
+				if !slices.Contains(mirrorTags, tag) {
+					r.Findings = append(r.Findings, Finding{
+						Kind:   KindMissingTag,
+						Mirror: mirror,
+						Image:  name,
+						Digest: digest,
+						Tag:    tag,
+						Source: sourceWithTag(inventories, having, name, digest, tag),
+					})
+				}
+			}
+		}
+	}
+}
+
+// sourceWithTag returns the first mirror having the digest with the tag.
+func sourceWithTag(
+	inventories map[image.Registry]registry.RegInvImage,
+	having []image.Registry, name image.Name, digest image.Digest, tag image.Tag,
+) image.Registry {
+	for _, mirror := range having {
+		if slices.Contains(inventories[mirror][name][digest], tag) {
+			return mirror
+		}
+	}
+
+	return having[0]
+}
+
+// Count returns the number of findings of a kind.
+func (r *Report) Count(kind Kind) int {
+	n := 0
+
+	for i := range r.Findings {
+		if r.Findings[i].Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Unfixed returns the findings that were not fixed.
+func (r *Report) Unfixed() []Finding {
+	unfixed := []Finding{}
+
+	for i := range r.Findings {
+		if !r.Findings[i].Fixed {
+			unfixed = append(unfixed, r.Findings[i])
+		}
+	}
+
+	return unfixed
+}
+
+// Err returns an error summarizing the findings that were not fixed, or nil
+// if the mirrors are consistent.
+func (r *Report) Err() error {
+	unfixed := r.Unfixed()
+	if len(unfixed) == 0 {
+		return nil
+	}
+
+	counts := map[Kind]int{}
+	for i := range unfixed {
+		counts[unfixed[i].Kind]++
+	}
+
+	return fmt.Errorf(
+		"found %d mirror inconsistencies: %d missing digests, %d missing tags, %d tag conflicts",
+		len(unfixed), counts[KindMissingDigest], counts[KindMissingTag], counts[KindTagConflict],
+	)
+}
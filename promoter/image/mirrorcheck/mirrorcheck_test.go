@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mirrorcheck_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/mirrorcheck"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+const (
+	digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	digestC = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+)
+
+func testReport() *mirrorcheck.Report {
+	report := mirrorcheck.NewReport()
+	report.Check(
+		[]image.Registry{"us.gcr.io/prod", "eu.gcr.io/prod", "asia.gcr.io/prod"},
+		[]image.Name{"foo", "bar"},
+		map[image.Registry]registry.RegInvImage{
+			"asia.gcr.io/prod": {
+				"foo": {digestA: {"v1.0", "latest"}, digestB: {"v2.0"}},
+				"bar": {digestC: {"v1.0"}},
+			},
+			"eu.gcr.io/prod": {
+				"foo": {digestA: {"v1.0"}},
+				"bar": {digestC: {"v1.0"}},
+			},
+			"us.gcr.io/prod": {
+				"foo": {digestA: {"v1.0", "latest"}, digestB: {"v2.0"}, digestC: {"v2.0"}},
+				"bar": {digestC: {"v1.0"}},
+			},
+		},
+	)
+
+	return report
+}
+
+func TestCheck(t *testing.T) {
+	report := testReport()
+
+	require.Equal(t, []image.Registry{"asia.gcr.io/prod", "eu.gcr.io/prod", "us.gcr.io/prod"}, report.Mirrors)
+	require.Equal(t, []mirrorcheck.Finding{
+		{Kind: mirrorcheck.KindMissingTag, Mirror: "eu.gcr.io/prod", Image: "foo", Digest: digestA, Tag: "latest", Source: "asia.gcr.io/prod"},
+		{Kind: mirrorcheck.KindMissingDigest, Mirror: "eu.gcr.io/prod", Image: "foo", Digest: digestB, Tags: registry.TagSlice{}, Source: "asia.gcr.io/prod"},
+		{Kind: mirrorcheck.KindTagConflict, Mirror: "asia.gcr.io/prod", Image: "foo", Digest: digestB, Tag: "v2.0"},
+		{Kind: mirrorcheck.KindTagConflict, Mirror: "us.gcr.io/prod", Image: "foo", Digest: digestB, Tag: "v2.0"},
+		{Kind: mirrorcheck.KindMissingDigest, Mirror: "asia.gcr.io/prod", Image: "foo", Digest: digestC, Tags: registry.TagSlice{}, Source: "us.gcr.io/prod"},
+		{Kind: mirrorcheck.KindMissingDigest, Mirror: "eu.gcr.io/prod", Image: "foo", Digest: digestC, Tags: registry.TagSlice{}, Source: "us.gcr.io/prod"},
+		{Kind: mirrorcheck.KindTagConflict, Mirror: "us.gcr.io/prod", Image: "foo", Digest: digestC, Tag: "v2.0"},
+	}, report.Findings)
+
+	require.False(t, report.Findings[2].Fixable())
+	require.True(t, report.Findings[0].Fixable())
+	require.ErrorContains(t, report.Err(), "3 missing digests, 1 missing tags, 3 tag conflicts")
+
+	for i := range report.Findings {
+		report.Findings[i].Fixed = true
+	}
+
+	require.NoError(t, report.Err())
+}
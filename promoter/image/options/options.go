@@ -18,6 +18,12 @@ package imagepromoter
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
 )
 
 // Options capture the switches available to run the image promoter.
@@ -43,6 +49,18 @@ type Options struct {
 	// ThinManifestDir is a directory of thin manifests
 	ThinManifestDir string
 
+	// UseServiceAccount when true, the service accounts of the registries
+	// of the manifests are impersonated to access them.
+	UseServiceAccount bool
+
+	// InsecureRegistries are the registries, or registry hosts, whose
+	// certificates are not verified.
+	InsecureRegistries []string
+
+	// PlainHTTPRegistries are the registries, or registry hosts, talked
+	// to over HTTP instead of HTTPS.
+	PlainHTTPRegistries []string
+
 	// Snapshot takes a registry reference and renders a textual representation of
 	// how the imagtes stored there look like to the promoter.
 	Snapshot string
@@ -54,9 +72,66 @@ type Options struct {
 	// SeverityThreshold is the level of security vulns to search for.
 	SeverityThreshold int
 
-	// OutputFormat is the format we will use for snapshots json/yaml
+	// VulnScanPolicy controls the vulnerability gate of the promotion
+	// pipeline: "none" skips it, "block-edges" drops the edges of images
+	// failing the check and promotes the others, and "block-run" fails the
+	// promotion if any image fails the check.
+	VulnScanPolicy string
+
+	// VulnScanner selects the vulnerability scanner: "grafeas" queries
+	// GCP Container Analysis, "report" reads pre-generated Trivy or Grype
+	// JSON reports and "exec" runs an external scanner command.
+	VulnScanner string
+
+	// VulnExceptions are files listing accepted vulnerabilities, either
+	// exceptions files or OpenVEX documents.
+	VulnExceptions []string
+
+	// VulnScannerCommand is the plugin run by the "exec" scanner, followed
+	// by its arguments. The image reference is appended to them.
+	VulnScannerCommand []string
+
+	// VulnScannerTimeout is the maximum duration of a scan by the "exec"
+	// scanner.
+	VulnScannerTimeout time.Duration
+
+	// VulnScannerMaxConcurrent limits the number of "exec" scanner
+	// processes running at the same time.
+	VulnScannerMaxConcurrent int
+
+	// VulnReportDir is the directory holding the vulnerability reports
+	// read by the "report" scanner, named after the image digests.
+	VulnReportDir string
+
+	// VulnReportReferrers when true, the "report" scanner also looks for
+	// reports attached to the staging images as OCI referrers.
+	VulnReportReferrers bool
+
+	// VulnScanReportJSON is the file the full vulnerability scan report is
+	// written to as JSON.
+	VulnScanReportJSON string
+
+	// VulnScanReportSARIF is the file the full vulnerability scan report is
+	// written to as SARIF, e.g. for upload to GitHub code scanning.
+	VulnScanReportSARIF string
+
+	// OutputFormat is the format we will use for snapshots: csv, json or yaml
 	OutputFormat string
 
+	// OutputFile is the file snapshots and the audit, mirror check and
+	// staging gc reports are written to instead of stdout.
+	OutputFile string
+
+	// SnapshotDetails when true, snapshots record the media type, size,
+	// creation and upload time of every digest and the platforms of image
+	// indexes.
+	SnapshotDetails bool
+
+	// SnapshotThinManifestDir when set, snapshots are written as the
+	// images/<project>/images.yaml files of a thin manifest directory,
+	// split by the first path element of the image names.
+	SnapshotThinManifestDir string
+
 	// MinimalSnapshot is used in snapshots. but im not sure
 	MinimalSnapshot bool
 
@@ -101,33 +176,286 @@ type Options struct {
 	// SignCheckIssuerRegexp can use a regex to match more than one signer OIDC tokens used to identify the signer
 	SignCheckIssuerRegexp string
 
+	// SignCheckAttestations when true, sigcheck also checks that every
+	// mirror has a promotion record attestation for each image
+	SignCheckAttestations bool
+
+	// SignCheckOutput is the format of the report written by sigcheck
+	// (json, yaml or markdown). When empty, results are only logged.
+	SignCheckOutput string
+
+	// AuditOutput is the format of the report written by audit (json or
+	// yaml). Defaults to json.
+	AuditOutput string
+
+	// MirrorCheckFix when true, mirrorcheck copies the digests and tags
+	// missing from some mirrors from a mirror having them.
+	MirrorCheckFix bool
+
+	// MirrorCheckOutput is the format of the report written by mirrorcheck
+	// (json or yaml). Defaults to json.
+	MirrorCheckOutput string
+
+	// StagingGCRegistry is the staging registry whose images are garbage
+	// collected by staging gc.
+	StagingGCRegistry string
+
+	// StagingGCKeepTags is the number of most recently uploaded tags kept
+	// per image by staging gc.
+	StagingGCKeepTags int
+
+	// StagingGCKeepDays makes staging gc keep the digests uploaded less
+	// than this many days ago.
+	StagingGCKeepDays int
+
+	// StagingGCOutput is the format of the report written by staging gc
+	// (json or yaml). Defaults to json.
+	StagingGCOutput string
+
+	// BundleFile is the OCI image layout archive written by export and
+	// read by import.
+	BundleFile string
+
+	// BundleImages restricts export to the images with these names.
+	BundleImages []string
+
+	// BundleTags restricts export to the digests with these tags.
+	BundleTags []string
+
+	// ImportRegistry is the registry import pushes the images of a bundle
+	// into.
+	ImportRegistry string
+
+	// ImportVerifySignatures when true, import verifies that the imported
+	// images are signed by the signature check identity.
+	ImportVerifySignatures bool
+
 	// MaxSignatureOps maximum number of concurrent signature operations
 	MaxSignatureOps int
+
+	// SignIdentityRules rewrite the destination registries of promoted
+	// images into the public reference recorded as docker-reference in
+	// their signatures (e.g. a regional mirror into the vanity domain).
+	// DefaultSignIdentityRules are used when empty.
+	SignIdentityRules []IdentityRule
+
+	// SignCanonicalRegistry is the destination registry that receives the
+	// signatures of promoted images and that sigcheck indexes to find the
+	// latest promoted images. For registry.k8s.io this must match the
+	// SIGNATURE_UPSTREAM_ENDPOINT configured in archeio so that signature
+	// lookups are routed to the correct backend.
+	// DefaultSignCanonicalRegistry is used when empty.
+	SignCanonicalRegistry string
+}
+
+// Vulnerability scanners available to the promoter.
+const (
+	VulnScannerGrafeas = "grafeas"
+	VulnScannerReport  = "report"
+	VulnScannerExec    = "exec"
+)
+
+// Policies of the vulnerability gate of the promotion pipeline.
+const (
+	VulnScanPolicyNone       = "none"
+	VulnScanPolicyBlockEdges = "block-edges"
+	VulnScanPolicyBlockRun   = "block-run"
+)
+
+// IdentityRule maps destination registries to the public identity of the
+// images promoted into them.
+type IdentityRule struct {
+	// Prefix is a registry path. When it does not start with a host, it
+	// matches the path of destination registries on any host, which covers
+	// all regional mirrors of a registry.
+	Prefix string
+
+	// Identity is the public registry that replaces the matched prefix.
+	Identity string
+}
+
+// String returns the rule in the prefix=identity form used by the CLI.
+func (r IdentityRule) String() string {
+	return r.Prefix + "=" + r.Identity
+}
+
+// FormatIdentityRules returns the rules in the prefix=identity form.
+func FormatIdentityRules(rules []IdentityRule) []string {
+	formatted := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		formatted = append(formatted, rule.String())
+	}
+
+	return formatted
+}
+
+// ParseIdentityRules parses a list of identity rules in prefix=identity form.
+func ParseIdentityRules(rules []string) ([]IdentityRule, error) {
+	parsed := make([]IdentityRule, 0, len(rules))
+
+	for _, rule := range rules {
+		prefix, identity, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid identity rule %q, expected prefix=identity", rule)
+		}
+
+		parsed = append(parsed, IdentityRule{
+			Prefix:   strings.Trim(prefix, "/"),
+			Identity: strings.TrimRight(identity, "/"),
+		})
+	}
+
+	return parsed, nil
+}
+
+// DefaultSignIdentityRules map the Kubernetes production registry to
+// registry.k8s.io.
+var DefaultSignIdentityRules = []IdentityRule{
+	{Prefix: "k8s-artifacts-prod/images", Identity: "registry.k8s.io"},
+}
+
+// DefaultSignCanonicalRegistry is the canonical registry of the Kubernetes
+// production images. It is a registry path: only the repositories under it
+// are canonical, not every repository of its host.
+const DefaultSignCanonicalRegistry = "us-central1-docker.pkg.dev/k8s-artifacts-prod/images"
+
+// IdentityRules returns the signature identity rules of the options, or
+// DefaultSignIdentityRules when none are set.
+func (o *Options) IdentityRules() []IdentityRule {
+	if len(o.SignIdentityRules) == 0 {
+		return DefaultSignIdentityRules
+	}
+
+	return o.SignIdentityRules
+}
+
+// CanonicalRegistry returns the canonical registry of the options, or
+// DefaultSignCanonicalRegistry when it is not set.
+func (o *Options) CanonicalRegistry() string {
+	if o.SignCanonicalRegistry == "" {
+		return DefaultSignCanonicalRegistry
+	}
+
+	return o.SignCanonicalRegistry
 }
 
 var DefaultOptions = &Options{
-	OutputFormat:            "yaml",
-	Threads:                 20,
-	SeverityThreshold:       -1,
-	SignImages:              true,
-	SignerAccount:           "krel-trust@k8s-releng-prod.iam.gserviceaccount.com",
-	SignCheckFix:            false,
-	SignCheckReferences:     []string{},
-	SignCheckFromDays:       5,
-	SignCheckIdentity:       "krel-trust@k8s-releng-prod.iam.gserviceaccount.com",
-	SignCheckIssuer:         "https://accounts.google.com",
-	SignCheckIdentityRegexp: "",
-	SignCheckIssuerRegexp:   "",
-	MaxSignatureOps:         50,
+	OutputFormat:             "yaml",
+	Threads:                  20,
+	SeverityThreshold:        -1,
+	VulnScanPolicy:           VulnScanPolicyNone,
+	VulnScanner:              VulnScannerGrafeas,
+	VulnScannerTimeout:       10 * time.Minute,
+	VulnScannerMaxConcurrent: 4,
+	SignImages:               true,
+	SignerAccount:            "krel-trust@k8s-releng-prod.iam.gserviceaccount.com",
+	SignCheckFix:             false,
+	ImportVerifySignatures:   true,
+	SignCheckReferences:      []string{},
+	SignCheckFromDays:        5,
+	SignCheckIdentity:        "krel-trust@k8s-releng-prod.iam.gserviceaccount.com",
+	SignCheckIssuer:          "https://accounts.google.com",
+	SignCheckIdentityRegexp:  "",
+	SignCheckIssuerRegexp:    "",
+	MaxSignatureOps:          50,
+	SignIdentityRules:        DefaultSignIdentityRules,
+	SignCanonicalRegistry:    DefaultSignCanonicalRegistry,
+	StagingGCKeepTags:        10,
+	StagingGCKeepDays:        90,
 }
 
 func (o *Options) Validate() error {
-	// If one of the snapshot options is set, manifests will not be checked
-	if o.Snapshot == "" && o.ManifestBasedSnapshotOf == "" {
+	// If one of the snapshot options is set, or a bundle is imported,
+	// manifests will not be checked
+	if o.Snapshot == "" && o.ManifestBasedSnapshotOf == "" && o.ImportRegistry == "" {
 		if o.Manifest == "" && o.ThinManifestDir == "" {
 			return errors.New("at least a manifest file or thin manifest directory have to be specified")
 		}
 	}
 
+	if o.ImportRegistry != "" {
+		if o.BundleFile == "" {
+			return errors.New("importing into a registry needs a bundle file")
+		}
+
+		if len(o.BundleImages) > 0 || len(o.BundleTags) > 0 {
+			return errors.New("the bundle image and tag filters only apply to exports")
+		}
+	}
+
+	switch o.VulnScanner {
+	case "", VulnScannerGrafeas:
+	case VulnScannerReport:
+		if o.VulnReportDir == "" && !o.VulnReportReferrers {
+			return errors.New("the report vulnerability scanner needs a report directory or referrer lookups enabled")
+		}
+	case VulnScannerExec:
+		if len(o.VulnScannerCommand) == 0 || o.VulnScannerCommand[0] == "" {
+			return errors.New("the exec vulnerability scanner needs a command")
+		}
+	default:
+		return fmt.Errorf("unknown vulnerability scanner %q", o.VulnScanner)
+	}
+
+	if o.OutputFile != "" && o.SnapshotThinManifestDir != "" {
+		return errors.New("a snapshot can be written to an output file or a thin manifest directory, not both")
+	}
+
+	if o.SnapshotDetails && (o.SnapshotThinManifestDir != "" || strings.EqualFold(o.OutputFormat, "csv")) {
+		return errors.New("detailed snapshots can only be written as json or yaml")
+	}
+
+	switch o.VulnScanPolicy {
+	case "", VulnScanPolicyNone:
+	case VulnScanPolicyBlockEdges, VulnScanPolicyBlockRun:
+		if o.SeverityThreshold <= 0 {
+			return fmt.Errorf("the %s vulnerability scan policy needs a severity threshold above 0", o.VulnScanPolicy)
+		}
+	default:
+		return fmt.Errorf("unknown vulnerability scan policy %q", o.VulnScanPolicy)
+	}
+
+	if o.SignCheckOutput != "" {
+		if err := report.ValidateFormat(o.SignCheckOutput, true); err != nil {
+			return fmt.Errorf("invalid signature report: %w", err)
+		}
+	}
+
+	if o.AuditOutput != "" {
+		if err := report.ValidateFormat(o.AuditOutput, false); err != nil {
+			return fmt.Errorf("invalid audit report: %w", err)
+		}
+	}
+
+	if o.MirrorCheckOutput != "" {
+		if err := report.ValidateFormat(o.MirrorCheckOutput, false); err != nil {
+			return fmt.Errorf("invalid mirror check report: %w", err)
+		}
+	}
+
+	if o.StagingGCKeepTags < 0 || o.StagingGCKeepDays < 0 {
+		return errors.New("the staging retention policy cannot keep a negative number of tags or days")
+	}
+
+	if o.StagingGCOutput != "" {
+		if err := report.ValidateFormat(o.StagingGCOutput, false); err != nil {
+			return fmt.Errorf("invalid staging gc report: %w", err)
+		}
+	}
+
+	if _, err := regexp.Compile(o.SignCheckIdentityRegexp); err != nil {
+		return fmt.Errorf("invalid signature check identity regexp: %w", err)
+	}
+
+	if _, err := regexp.Compile(o.SignCheckIssuerRegexp); err != nil {
+		return fmt.Errorf("invalid signature check issuer regexp: %w", err)
+	}
+
+	for _, rule := range o.SignIdentityRules {
+		if rule.Prefix == "" || rule.Identity == "" {
+			return fmt.Errorf("invalid identity rule %q: prefix and identity must be set", rule)
+		}
+	}
+
 	return nil
 }
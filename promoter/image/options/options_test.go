@@ -58,6 +58,128 @@ func TestOptionsValidate(t *testing.T) {
 			opts:      Options{},
 			shouldErr: true,
 		},
+		{
+			name:      "import bypasses manifest check",
+			opts:      Options{ImportRegistry: "registry.example.com/mirror", BundleFile: "bundle.tar"},
+			shouldErr: false,
+		},
+		{
+			name:      "import without bundle",
+			opts:      Options{ImportRegistry: "registry.example.com/mirror"},
+			shouldErr: true,
+		},
+		{
+			name: "import with export filters",
+			opts: Options{
+				ImportRegistry: "registry.example.com/mirror",
+				BundleFile:     "bundle.tar",
+				BundleTags:     []string{"v1.0"},
+			},
+			shouldErr: true,
+		},
+		{
+			name: "identity rule without identity",
+			opts: Options{
+				Manifest:          "path/to/manifest.yaml",
+				SignIdentityRules: []IdentityRule{{Prefix: "prod/images"}},
+			},
+			shouldErr: true,
+		},
+		{
+			name:      "report scanner with directory",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanner: VulnScannerReport, VulnReportDir: "reports"},
+			shouldErr: false,
+		},
+		{
+			name:      "report scanner without reports",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanner: VulnScannerReport},
+			shouldErr: true,
+		},
+		{
+			name:      "exec scanner without command",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanner: VulnScannerExec},
+			shouldErr: true,
+		},
+		{
+			name:      "exec scanner",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanner: VulnScannerExec, VulnScannerCommand: []string{"my-scanner"}},
+			shouldErr: false,
+		},
+		{
+			name:      "unknown vulnerability scanner",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanner: "clair"},
+			shouldErr: true,
+		},
+		{
+			name:      "snapshot to output file and thin manifest directory",
+			opts:      Options{Snapshot: "gcr.io/test", OutputFile: "snapshot.yaml", SnapshotThinManifestDir: "manifests"},
+			shouldErr: true,
+		},
+		{
+			name:      "detailed csv snapshot",
+			opts:      Options{Snapshot: "gcr.io/test", OutputFormat: "csv", SnapshotDetails: true},
+			shouldErr: true,
+		},
+		{
+			name:      "scan policy with threshold",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanPolicy: VulnScanPolicyBlockEdges, SeverityThreshold: 4},
+			shouldErr: false,
+		},
+		{
+			name:      "scan policy without threshold",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanPolicy: VulnScanPolicyBlockRun, SeverityThreshold: -1},
+			shouldErr: true,
+		},
+		{
+			name:      "unknown scan policy",
+			opts:      Options{Manifest: "path/to/manifest.yaml", VulnScanPolicy: "warn", SeverityThreshold: 4},
+			shouldErr: true,
+		},
+		{
+			name:      "signature report format",
+			opts:      Options{Manifest: "path/to/manifest.yaml", SignCheckOutput: "markdown"},
+			shouldErr: false,
+		},
+		{
+			name:      "invalid signature check identity regexp",
+			opts:      Options{Manifest: "path/to/manifest.yaml", SignCheckIdentityRegexp: "("},
+			shouldErr: true,
+		},
+		{
+			name:      "invalid signature check issuer regexp",
+			opts:      Options{Manifest: "path/to/manifest.yaml", SignCheckIssuerRegexp: "[a-"},
+			shouldErr: true,
+		},
+		{
+			name:      "invalid signature report format",
+			opts:      Options{Manifest: "path/to/manifest.yaml", SignCheckOutput: "csv"},
+			shouldErr: true,
+		},
+		{
+			name:      "audit report format",
+			opts:      Options{Manifest: "path/to/manifest.yaml", AuditOutput: "yaml"},
+			shouldErr: false,
+		},
+		{
+			name:      "invalid audit report format",
+			opts:      Options{Manifest: "path/to/manifest.yaml", AuditOutput: "markdown"},
+			shouldErr: true,
+		},
+		{
+			name:      "invalid mirror check report format",
+			opts:      Options{Manifest: "path/to/manifest.yaml", MirrorCheckOutput: "csv"},
+			shouldErr: true,
+		},
+		{
+			name:      "negative staging retention",
+			opts:      Options{Manifest: "path/to/manifest.yaml", StagingGCKeepDays: -1},
+			shouldErr: true,
+		},
+		{
+			name:      "invalid staging gc report format",
+			opts:      Options{Manifest: "path/to/manifest.yaml", StagingGCOutput: "csv"},
+			shouldErr: true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.opts.Validate()
@@ -69,3 +191,39 @@ func TestOptionsValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIdentityRules(t *testing.T) {
+	rules, err := ParseIdentityRules([]string{
+		"k8s-artifacts-prod/images=registry.k8s.io",
+		"/ghcr.io/example/=cr.example.com/",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []IdentityRule{
+		{Prefix: "k8s-artifacts-prod/images", Identity: "registry.k8s.io"},
+		{Prefix: "ghcr.io/example", Identity: "cr.example.com"},
+	}, rules)
+
+	require.Equal(t, []string{
+		"k8s-artifacts-prod/images=registry.k8s.io",
+		"ghcr.io/example=cr.example.com",
+	}, FormatIdentityRules(rules))
+
+	_, err = ParseIdentityRules([]string{"registry.k8s.io"})
+	require.Error(t, err)
+}
+
+func TestIdentityDefaults(t *testing.T) {
+	t.Parallel()
+
+	// The zero value falls back to the defaults
+	opts := &Options{}
+	require.Equal(t, DefaultSignIdentityRules, opts.IdentityRules())
+	require.Equal(t, DefaultSignCanonicalRegistry, opts.CanonicalRegistry())
+
+	opts = &Options{
+		SignIdentityRules:     []IdentityRule{{Prefix: "ghcr.io/example", Identity: "cr.example.com"}},
+		SignCanonicalRegistry: "ghcr.io/example",
+	}
+	require.Equal(t, opts.SignIdentityRules, opts.IdentityRules())
+	require.Equal(t, "ghcr.io/example", opts.CanonicalRegistry())
+}
@@ -22,24 +22,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"net/http"
+	"strings"
 
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/gcrane"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sirupsen/logrus"
 
 	impl "sigs.k8s.io/promo-tools/v4/internal/promoter/image"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/audit"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/auth"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/mirrorcheck"
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/pipeline"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/provenance"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/retention"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/vuln"
+	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
 var AllowedOutputFormats = []string{
 	"csv",
+	"json",
 	"yaml",
 }
 
@@ -52,16 +63,25 @@ type Promoter struct {
 
 func New(opts *options.Options) *Promoter {
 	// All pipeline phases run sequentially, so a single rate limiter
-	// with the full budget is sufficient.
-	rt := ratelimit.NewRoundTripper(ratelimit.MaxEvents)
+	// with the full budget is sufficient. Its base applies the transport
+	// settings of the registries, registered when the manifests are parsed.
+	transports := registry.NewTransports(http.DefaultTransport)
+	rt := ratelimit.NewRoundTripperWithBase(ratelimit.MaxEvents, transports)
+
+	// The credentials of the registries are registered in the keychain
+	// when the manifests are parsed.
+	kc := registry.NewKeychain(gcrane.Keychain)
 
 	di := impl.NewDefaultPromoterImplementation(opts)
+	di.SetKeychain(kc)
+	di.SetTransports(transports)
 	di.SetTransport(rt)
 	di.SetRegistryProvider(registry.NewCraneProvider(
 		registry.WithTransport(rt),
+		registry.WithKeychain(kc),
 	))
 	di.SetIdentityTokenProvider(&auth.GCPIdentityTokenProvider{})
-	di.SetVulnScanner(&vuln.GrafeasScanner{FixableOnly: true})
+	di.SetVulnScanner(newVulnScanner(opts, rt, kc))
 
 	p := &Promoter{
 		Options: opts,
@@ -78,6 +98,35 @@ func New(opts *options.Options) *Promoter {
 	return p
 }
 
+// newVulnScanner returns the vulnerability scanner selected in the options.
+func newVulnScanner(opts *options.Options, rt http.RoundTripper, kc authn.Keychain) vuln.Scanner {
+	switch opts.VulnScanner {
+	case options.VulnScannerReport:
+		return &vuln.ReportScanner{
+			Dir:       opts.VulnReportDir,
+			Referrers: opts.VulnReportReferrers,
+			RemoteOptions: []remote.Option{
+				remote.WithAuthFromKeychain(kc),
+				remote.WithTransport(rt),
+			},
+		}
+	case options.VulnScannerExec:
+		scanner := &vuln.ExecScanner{
+			Timeout:       opts.VulnScannerTimeout,
+			MaxConcurrent: opts.VulnScannerMaxConcurrent,
+		}
+
+		if len(opts.VulnScannerCommand) > 0 {
+			scanner.Command = opts.VulnScannerCommand[0]
+			scanner.Args = opts.VulnScannerCommand[1:]
+		}
+
+		return scanner
+	default:
+		return &vuln.GrafeasScanner{FixableOnly: true}
+	}
+}
+
 func (p *Promoter) SetImplementation(pi promoterImplementation) {
 	p.impl = pi
 }
@@ -109,23 +158,45 @@ type promoterImplementation interface {
 	GetSnapshotSourceRegistry(*options.Options) (*registry.Context, error)
 	GetSnapshotManifests(*options.Options) ([]schema.Manifest, error)
 	AppendManifestToSnapshot(*options.Options, []schema.Manifest) ([]schema.Manifest, error)
-	GetRegistryImageInventory(context.Context, *options.Options, []schema.Manifest) (registry.RegInvImage, error)
-	Snapshot(*options.Options, registry.RegInvImage) error
+	GetRegistryImageInventory(context.Context, *options.Options, []schema.Manifest) (registry.RegInvImage, map[image.Digest]registry.ManifestDetails, error)
+	Snapshot(*options.Options, registry.RegInvImage, map[image.Digest]registry.ManifestDetails) error
 
 	// Methods for image vulnerability scans:
 	ScanEdges(context.Context, *options.Options, map[promotion.Edge]any) error
+	ScanPromotionEdges(context.Context, *options.Options, map[promotion.Edge]any) (*vuln.ScanReport, error)
 
 	// Methods for image signing
 	PrewarmTUFCache(context.Context) error
-	ValidateStagingSignatures(map[promotion.Edge]any) (map[promotion.Edge]any, error)
+	ValidateStagingSignatures(*options.Options, []schema.Manifest, map[promotion.Edge]any) error
+	GetUnsignedEdges(context.Context, *options.Options, []schema.Manifest) (map[promotion.Edge]any, error)
 	SignImages(*options.Options, map[promotion.Edge]any) error
 	WriteProvenanceAttestations(context.Context, *options.Options, map[promotion.Edge]any, provenance.Generator) error
 
 	// Methods for checking signatures
-	GetLatestImages(*options.Options) ([]string, error)
-	GetSignatureStatus(*options.Options, []string) (checkresults.Signature, error)
+	GetLatestImages(*options.Options) ([]checkresults.Image, error)
+	GetSignatureStatus(context.Context, *options.Options, []checkresults.Image) (checkresults.Signature, error)
 	FixMissingSignatures(*options.Options, checkresults.Signature) error
 	FixPartialSignatures(*options.Options, checkresults.Signature) error
+	FixMissingAttestations(context.Context, *options.Options, checkresults.Signature, provenance.Generator) error
+	WriteSignatureReport(*options.Options, checkresults.Signature) error
+
+	// Methods for auditing the destination registries
+	AuditRegistries(context.Context, *options.Options, []schema.Manifest) (*audit.Report, error)
+	WriteAuditReport(*options.Options, *audit.Report) error
+
+	// Methods for checking mirror consistency
+	GetMirrorStatus(context.Context, *options.Options, []schema.Manifest) (*mirrorcheck.Report, error)
+	FixMirrors(context.Context, *options.Options, *mirrorcheck.Report) error
+	WriteMirrorReport(*options.Options, *mirrorcheck.Report) error
+
+	// Methods for garbage collecting staging registries
+	PlanStagingGC(context.Context, *options.Options, []schema.Manifest) (*retention.Report, error)
+	DeleteStagingImages(context.Context, *options.Options, *retention.Report) error
+	WriteStagingGCReport(*options.Options, *retention.Report) error
+
+	// Methods for exporting and importing air-gapped bundles
+	ExportImages(context.Context, *options.Options, []schema.Manifest) error
+	ImportImages(context.Context, *options.Options) error
 
 	// Utility functions
 	PrintVersion()
@@ -214,9 +285,28 @@ func (p *Promoter) PromoteImages(ctx context.Context, opts *options.Options) err
 		return nil
 	}))
 
-	// Validate phase: check staging signatures.
+	// Scan phase: enforce the vulnerability gate on the source images.
+	pipe.AddPhase(pipeline.NewPhase("scan", func(ctx context.Context) error {
+		filtered, err := p.scanPromotionEdges(ctx, opts, promotionEdges)
+		if err != nil {
+			return err
+		}
+
+		if len(filtered) == 0 && len(promotionEdges) > 0 {
+			logrus.Info("No promotion edges left after the vulnerability check")
+
+			return pipeline.ErrStopPipeline
+		}
+
+		promotionEdges = filtered
+
+		return nil
+	}))
+
+	// Validate phase: check staging signatures against the trusted
+	// signers of each manifest.
 	pipe.AddPhase(pipeline.NewPhase("validate", func(_ context.Context) error {
-		if _, err := p.impl.ValidateStagingSignatures(promotionEdges); err != nil {
+		if err := p.impl.ValidateStagingSignatures(opts, mfests, promotionEdges); err != nil {
 			return fmt.Errorf("checking signatures in staging images: %w", err)
 		}
 
@@ -238,9 +328,22 @@ func (p *Promoter) PromoteImages(ctx context.Context, opts *options.Options) err
 		return nil
 	}))
 
-	// Sign phase: sign promoted images (primary registry only).
-	pipe.AddPhase(pipeline.NewPhase("sign", func(_ context.Context) error {
-		if err := p.impl.SignImages(opts, promotionEdges); err != nil {
+	// Sign phase: sign promoted images (primary registry only), along
+	// with the images copied by earlier runs that failed before signing.
+	pipe.AddPhase(pipeline.NewPhase("sign", func(ctx context.Context) error {
+		signEdges := make(map[promotion.Edge]any, len(promotionEdges))
+		maps.Copy(signEdges, promotionEdges)
+
+		if opts.SignImages {
+			unsigned, err := p.impl.GetUnsignedEdges(ctx, opts, mfests)
+			if err != nil {
+				return fmt.Errorf("looking for unsigned images: %w", err)
+			}
+
+			maps.Copy(signEdges, unsigned)
+		}
+
+		if err := p.impl.SignImages(opts, signEdges); err != nil {
 			return fmt.Errorf("signing images: %w", err)
 		}
 
@@ -263,6 +366,52 @@ func (p *Promoter) PromoteImages(ctx context.Context, opts *options.Options) err
 	return nil
 }
 
+// scanPromotionEdges runs the vulnerability gate according to the scan
+// policy and returns the edges allowed to be promoted.
+func (p *Promoter) scanPromotionEdges(
+	ctx context.Context, opts *options.Options, edges map[promotion.Edge]any,
+) (map[promotion.Edge]any, error) {
+	switch opts.VulnScanPolicy {
+	case options.VulnScanPolicyBlockEdges, options.VulnScanPolicyBlockRun:
+	default:
+		return edges, nil
+	}
+
+	report, err := p.impl.ScanPromotionEdges(ctx, opts, edges)
+	if err != nil {
+		return nil, fmt.Errorf("running vulnerability scan: %w", err)
+	}
+
+	if report.Passed {
+		return edges, nil
+	}
+
+	if opts.VulnScanPolicy == options.VulnScanPolicyBlockRun {
+		return nil, fmt.Errorf("vulnerability gate: %w", report.Err())
+	}
+
+	blocked := map[string]bool{}
+	for _, img := range report.Failed() {
+		blocked[img.Digest] = true
+	}
+
+	filtered := make(map[promotion.Edge]any, len(edges))
+
+	for edge, v := range edges {
+		if blocked[string(edge.Digest)] {
+			logrus.WithField("image", edge.DstReference()).Warn("Not promoting image failing the vulnerability check")
+
+			continue
+		}
+
+		filtered[edge] = v
+	}
+
+	logrus.Warnf("Blocked %d of %d promotion edges failing the vulnerability check", len(edges)-len(filtered), len(edges))
+
+	return filtered, nil
+}
+
 // Snapshot runs the steps to output a representation in json or yaml of a registry.
 func (p *Promoter) Snapshot(ctx context.Context, opts *options.Options) error {
 	if err := p.impl.ValidateOptions(opts); err != nil {
@@ -281,18 +430,99 @@ func (p *Promoter) Snapshot(ctx context.Context, opts *options.Options) error {
 		return fmt.Errorf("adding the specified manifest to the snapshot context: %w", err)
 	}
 
-	rii, err := p.impl.GetRegistryImageInventory(ctx, opts, mfests)
+	rii, details, err := p.impl.GetRegistryImageInventory(ctx, opts, mfests)
 	if err != nil {
 		return fmt.Errorf("getting registry image inventory: %w", err)
 	}
 
-	if err := p.impl.Snapshot(opts, rii); err != nil {
+	if err := p.impl.Snapshot(opts, rii, details); err != nil {
 		return fmt.Errorf("generating snapshot: %w", err)
 	}
 
 	return nil
 }
 
+// Prefixes of the inventory sources compared by Diff. Sources without a
+// prefix are registries.
+const (
+	DiffSourceRegistry  = "registry:"
+	DiffSourceManifests = "manifests:"
+	DiffSourceFile      = "file:"
+)
+
+// Diff compares the images of two sources, each of them a registry
+// ("registry:<registry>"), the images the manifests promote to a registry
+// ("manifests:<registry>") or a YAML or JSON snapshot file
+// ("file:<path>").
+func (p *Promoter) Diff(ctx context.Context, opts *options.Options, left, right string) (*registry.InventoryDiff, error) {
+	leftRii, err := p.readInventorySource(ctx, opts, left)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", left, err)
+	}
+
+	rightRii, err := p.readInventorySource(ctx, opts, right)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", right, err)
+	}
+
+	return leftRii.Diff(rightRii), nil
+}
+
+// readInventorySource returns the images of a Diff source.
+func (p *Promoter) readInventorySource(
+	ctx context.Context, opts *options.Options, source string,
+) (registry.RegInvImage, error) {
+	// Reuse the snapshot options without changing the caller's options.
+	snapOpts := *opts
+	snapOpts.Snapshot = ""
+	snapOpts.ManifestBasedSnapshotOf = ""
+	snapOpts.SnapshotDetails = false
+
+	switch {
+	case strings.HasPrefix(source, DiffSourceFile):
+		images, err := schema.ParseImagesFromFile(strings.TrimPrefix(source, DiffSourceFile))
+		if err != nil {
+			return nil, fmt.Errorf("parsing snapshot file: %w", err)
+		}
+
+		mfest := schema.Manifest{Images: images}
+
+		return mfest.ToRegInvImage(), nil
+
+	case strings.HasPrefix(source, DiffSourceManifests):
+		mfests, err := p.impl.ParseManifests(&snapOpts)
+		if err != nil {
+			return nil, fmt.Errorf("parsing manifests: %w", err)
+		}
+
+		if len(mfests) == 0 {
+			return nil, errors.New("no manifests found, set a manifest or thin manifest directory")
+		}
+
+		edges, err := promotion.ToEdges(mfests)
+		if err != nil {
+			return nil, fmt.Errorf("converting manifests to edges: %w", err)
+		}
+
+		return promotion.EdgesToRegInvImage(edges, strings.TrimPrefix(source, DiffSourceManifests)), nil
+
+	default:
+		snapOpts.Snapshot = strings.TrimPrefix(source, DiffSourceRegistry)
+
+		mfests, err := p.impl.GetSnapshotManifests(&snapOpts)
+		if err != nil {
+			return nil, fmt.Errorf("getting snapshot manifests: %w", err)
+		}
+
+		rii, _, err := p.impl.GetRegistryImageInventory(ctx, &snapOpts, mfests)
+		if err != nil {
+			return nil, fmt.Errorf("getting registry image inventory: %w", err)
+		}
+
+		return rii, nil
+	}
+}
+
 // SecurityScan runs just like an image promotion, but instead of
 // actually copying the new detected images, it will run a vulnerability
 // scan on them.
@@ -335,7 +565,11 @@ func (p *Promoter) SecurityScan(ctx context.Context, opts *options.Options) erro
 }
 
 // CheckSignatures checks the consistency of a set of images.
-func (p *Promoter) CheckSignatures(_ context.Context, opts *options.Options) error {
+func (p *Promoter) CheckSignatures(ctx context.Context, opts *options.Options) error {
+	if err := p.impl.ValidateOptions(opts); err != nil {
+		return fmt.Errorf("validating options: %w", err)
+	}
+
 	logrus.Info("Fetching latest promoted images")
 
 	images, err := p.impl.GetLatestImages(opts)
@@ -345,15 +579,15 @@ func (p *Promoter) CheckSignatures(_ context.Context, opts *options.Options) err
 
 	logrus.Info("Checking signatures")
 
-	results, err := p.impl.GetSignatureStatus(opts, images)
+	results, err := p.impl.GetSignatureStatus(ctx, opts, images)
 	if err != nil {
 		return fmt.Errorf("checking signature status in images: %w", err)
 	}
 
-	if results.TotalPartial() == 0 && results.TotalUnsigned() == 0 {
+	if results.TotalPartial() == 0 && results.TotalUnsigned() == 0 && results.TotalUnattested() == 0 {
 		logrus.Info("Signature consistency OK!")
 
-		return nil
+		return p.writeSignatureReport(opts, results)
 	}
 
 	logrus.Infof("Fixing %d unsigned images", results.TotalUnsigned())
@@ -368,5 +602,169 @@ func (p *Promoter) CheckSignatures(_ context.Context, opts *options.Options) err
 		return fmt.Errorf("fixing partial signatures: %w", err)
 	}
 
+	if total := results.TotalUnattested(); total > 0 {
+		logrus.Infof("Fixing %d images with missing attestations", total)
+
+		if err := p.impl.FixMissingAttestations(ctx, opts, results, p.provenanceGenerator); err != nil {
+			return fmt.Errorf("fixing missing attestations: %w", err)
+		}
+	}
+
+	return p.writeSignatureReport(opts, results)
+}
+
+// Audit checks that the manifests account for every image in their
+// destination registries. It writes a report of the unknown digests,
+// unknown tags and tags pointing to another digest than the declared one,
+// and returns an error if there is any.
+func (p *Promoter) Audit(ctx context.Context, opts *options.Options) error {
+	if err := p.impl.ValidateOptions(opts); err != nil {
+		return fmt.Errorf("validating options: %w", err)
+	}
+
+	p.impl.PrintVersion()
+
+	mfests, err := p.impl.ParseManifests(opts)
+	if err != nil {
+		return fmt.Errorf("parsing manifests: %w", err)
+	}
+
+	report, err := p.impl.AuditRegistries(ctx, opts, mfests)
+	if err != nil {
+		return fmt.Errorf("auditing registries: %w", err)
+	}
+
+	if err := p.impl.WriteAuditReport(opts, report); err != nil {
+		return fmt.Errorf("writing audit report: %w", err)
+	}
+
+	return report.Err()
+}
+
+// CheckMirrors cross-compares the destination registries of each manifest
+// and reports the digests and tags found in some of them but not in others.
+// With opts.MirrorCheckFix, the missing ones are copied from a mirror having
+// them. It returns an error if any inconsistency is left.
+func (p *Promoter) CheckMirrors(ctx context.Context, opts *options.Options) error {
+	if err := p.impl.ValidateOptions(opts); err != nil {
+		return fmt.Errorf("validating options: %w", err)
+	}
+
+	p.impl.PrintVersion()
+
+	mfests, err := p.impl.ParseManifests(opts)
+	if err != nil {
+		return fmt.Errorf("parsing manifests: %w", err)
+	}
+
+	report, err := p.impl.GetMirrorStatus(ctx, opts, mfests)
+	if err != nil {
+		return fmt.Errorf("checking mirrors: %w", err)
+	}
+
+	if opts.MirrorCheckFix && len(report.Findings) > 0 {
+		logrus.Infof("Fixing %d mirror inconsistencies", len(report.Findings))
+
+		if err := p.impl.FixMirrors(ctx, opts, report); err != nil {
+			return fmt.Errorf("fixing mirrors: %w", err)
+		}
+	}
+
+	if err := p.impl.WriteMirrorReport(opts, report); err != nil {
+		return fmt.Errorf("writing mirror check report: %w", err)
+	}
+
+	return report.Err()
+}
+
+// StagingGC applies the retention policy of the options to a staging
+// registry and writes a report of the digests it does not keep. Digests
+// promoted by the manifests are always kept. The deletable digests are only
+// deleted when opts.Confirm is set.
+func (p *Promoter) StagingGC(ctx context.Context, opts *options.Options) error {
+	if err := p.impl.ValidateOptions(opts); err != nil {
+		return fmt.Errorf("validating options: %w", err)
+	}
+
+	p.impl.PrintVersion()
+
+	mfests, err := p.impl.ParseManifests(opts)
+	if err != nil {
+		return fmt.Errorf("parsing manifests: %w", err)
+	}
+
+	report, err := p.impl.PlanStagingGC(ctx, opts, mfests)
+	if err != nil {
+		return fmt.Errorf("applying retention policy: %w", err)
+	}
+
+	switch {
+	case len(report.Deletable) == 0:
+		logrus.Info("Nothing to delete")
+	case !opts.Confirm:
+		logrus.Infof("Dry run: %d digests would be deleted, use --confirm to delete them", len(report.Deletable))
+	default:
+		if err := p.impl.DeleteStagingImages(ctx, opts, report); err != nil {
+			return fmt.Errorf("deleting staging images: %w", err)
+		}
+	}
+
+	if err := p.impl.WriteStagingGCReport(opts, report); err != nil {
+		return fmt.Errorf("writing staging gc report: %w", err)
+	}
+
+	return report.Err()
+}
+
+// Export writes the images of the manifests, with their signatures and
+// attestations, into the OCI image layout archive opts.BundleFile so they
+// can be carried into an air-gapped environment.
+func (p *Promoter) Export(ctx context.Context, opts *options.Options) error {
+	if err := p.impl.ValidateOptions(opts); err != nil {
+		return fmt.Errorf("validating options: %w", err)
+	}
+
+	p.impl.PrintVersion()
+
+	mfests, err := p.impl.ParseManifests(opts)
+	if err != nil {
+		return fmt.Errorf("parsing manifests: %w", err)
+	}
+
+	if err := p.impl.ExportImages(ctx, opts, mfests); err != nil {
+		return fmt.Errorf("exporting images: %w", err)
+	}
+
+	return nil
+}
+
+// Import pushes the images of the bundle opts.BundleFile into
+// opts.ImportRegistry, preserving their digests. The images are only pushed
+// when opts.Confirm is set.
+func (p *Promoter) Import(ctx context.Context, opts *options.Options) error {
+	if err := p.impl.ValidateOptions(opts); err != nil {
+		return fmt.Errorf("validating options: %w", err)
+	}
+
+	p.impl.PrintVersion()
+
+	if err := p.impl.ImportImages(ctx, opts); err != nil {
+		return fmt.Errorf("importing images: %w", err)
+	}
+
+	return nil
+}
+
+// writeSignatureReport writes the signature check results if a report
+// format is configured.
+func (p *Promoter) writeSignatureReport(opts *options.Options, results checkresults.Signature) error {
+	if opts.SignCheckOutput == "" {
+		return nil
+	}
+
+	if err := p.impl.WriteSignatureReport(opts, results); err != nil {
+		return fmt.Errorf("writing signature report: %w", err)
+	}
+
 	return nil
 }
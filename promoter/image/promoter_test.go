@@ -24,13 +24,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	imagepromoter "sigs.k8s.io/promo-tools/v4/promoter/image"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/audit"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/checkresults"
 	imagefakes "sigs.k8s.io/promo-tools/v4/promoter/image/imagefakes"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/mirrorcheck"
 	options "sigs.k8s.io/promo-tools/v4/promoter/image/options"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/promotion"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/provenance"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/retention"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/schema"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/vuln"
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
@@ -96,7 +100,7 @@ func TestPromoteImages(t *testing.T) {
 			shouldErr: true,
 			prepare: func(fpi *imagefakes.FakePromoterImplementation) {
 				fpi.ParseManifestsReturns(nonEmptyManifests(), nil)
-				fpi.ValidateStagingSignaturesReturns(nil, testErr)
+				fpi.ValidateStagingSignaturesReturns(testErr)
 			},
 		},
 		{
@@ -107,6 +111,14 @@ func TestPromoteImages(t *testing.T) {
 				fpi.PromoteImagesReturns(testErr)
 			},
 		},
+		{
+			// GetUnsignedEdges fails
+			shouldErr: true,
+			prepare: func(fpi *imagefakes.FakePromoterImplementation) {
+				fpi.ParseManifestsReturns(nonEmptyManifests(), nil)
+				fpi.GetUnsignedEdgesReturns(nil, testErr)
+			},
+		},
 		{
 			// SignImages fails
 			shouldErr: true,
@@ -130,13 +142,44 @@ func TestPromoteImages(t *testing.T) {
 		sut.SetImplementation(&mock)
 
 		if tc.shouldErr {
-			require.Error(t, sut.PromoteImages(context.Background(), &options.Options{Confirm: true}), tc.msg)
+			require.Error(t, sut.PromoteImages(context.Background(), &options.Options{Confirm: true, SignImages: true}), tc.msg)
 		} else {
-			require.NoError(t, sut.PromoteImages(context.Background(), &options.Options{Confirm: true}), tc.msg)
+			require.NoError(t, sut.PromoteImages(context.Background(), &options.Options{Confirm: true, SignImages: true}), tc.msg)
 		}
 	}
 }
 
+func TestPromoteImagesSignsUnsignedImages(t *testing.T) {
+	sut := imagepromoter.Promoter{}
+	mock := imagefakes.FakePromoterImplementation{}
+	mock.ParseManifestsReturns(nonEmptyManifests(), nil)
+
+	promoted := testEdge()
+	unsigned := testEdge()
+	unsigned.SrcImageTag.Name = "unsigned-image"
+
+	mock.GetPromotionEdgesReturns(map[promotion.Edge]any{promoted: nil}, nil)
+	mock.GetUnsignedEdgesReturns(map[promotion.Edge]any{unsigned: nil}, nil)
+	sut.SetImplementation(&mock)
+	sut.SetProvenanceVerifier(&fakeVerifier{result: &provenance.Result{Verified: true}})
+
+	opts := &options.Options{Confirm: true, SignImages: true}
+	require.NoError(t, sut.PromoteImages(context.Background(), opts))
+
+	// The images promoted by earlier runs are signed, but not promoted again
+	_, _, edges := mock.PromoteImagesArgsForCall(0)
+	require.Equal(t, map[promotion.Edge]any{promoted: nil}, edges)
+
+	require.Equal(t, 1, mock.SignImagesCallCount())
+	_, edges = mock.SignImagesArgsForCall(0)
+	require.Equal(t, map[promotion.Edge]any{promoted: nil, unsigned: nil}, edges)
+
+	// Nothing is looked up when images are not signed
+	opts.SignImages = false
+	require.NoError(t, sut.PromoteImages(context.Background(), opts))
+	require.Equal(t, 1, mock.GetUnsignedEdgesCallCount())
+}
+
 func TestPromoteImagesParseOnly(t *testing.T) {
 	sut := imagepromoter.Promoter{}
 	mock := imagefakes.FakePromoterImplementation{}
@@ -270,6 +313,76 @@ func TestPromoteImagesProvenanceVerifierError(t *testing.T) {
 	require.Error(t, sut.PromoteImages(context.Background(), opts))
 }
 
+func TestPromoteImagesScanPolicy(t *testing.T) {
+	clean := testEdge()
+	vulnerable := testEdge()
+	vulnerable.SrcImageTag.Name = "vulnerable-image"
+	vulnerable.Digest = image.Digest("sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb")
+
+	report := vuln.NewScanReport(vuln.SeverityHigh)
+	report.Add(clean.SrcReference(), string(clean.Digest), nil, &vuln.ScanResult{}, nil)
+	report.Add(vulnerable.SrcReference(), string(vulnerable.Digest), nil, &vuln.ScanResult{
+		HighestSeverity: vuln.SeverityCritical,
+	}, nil)
+
+	for _, tc := range []struct {
+		policy       string
+		shouldErr    bool
+		scanCalls    int
+		promoteCalls int
+		promoted     int
+	}{
+		{policy: options.VulnScanPolicyNone, scanCalls: 0, promoteCalls: 1, promoted: 2},
+		{policy: options.VulnScanPolicyBlockEdges, scanCalls: 1, promoteCalls: 1, promoted: 1},
+		{policy: options.VulnScanPolicyBlockRun, shouldErr: true, scanCalls: 1, promoteCalls: 0},
+	} {
+		sut := imagepromoter.Promoter{}
+		mock := imagefakes.FakePromoterImplementation{}
+		mock.ParseManifestsReturns(nonEmptyManifests(), nil)
+		mock.GetPromotionEdgesReturns(map[promotion.Edge]any{clean: nil, vulnerable: nil}, nil)
+		mock.ScanPromotionEdgesReturns(report, nil)
+		sut.SetImplementation(&mock)
+		sut.SetProvenanceVerifier(&fakeVerifier{result: &provenance.Result{Verified: true}})
+
+		opts := &options.Options{Confirm: true, SeverityThreshold: int(vuln.SeverityHigh), VulnScanPolicy: tc.policy}
+
+		err := sut.PromoteImages(context.Background(), opts)
+		if tc.shouldErr {
+			require.Error(t, err, tc.policy)
+		} else {
+			require.NoError(t, err, tc.policy)
+		}
+
+		require.Equal(t, tc.scanCalls, mock.ScanPromotionEdgesCallCount(), tc.policy)
+		require.Equal(t, tc.promoteCalls, mock.PromoteImagesCallCount(), tc.policy)
+
+		if tc.promoteCalls > 0 {
+			_, _, edges := mock.PromoteImagesArgsForCall(0)
+			require.Len(t, edges, tc.promoted, tc.policy)
+		}
+	}
+
+	// Blocking every edge stops the promotion
+	sut := imagepromoter.Promoter{}
+	mock := imagefakes.FakePromoterImplementation{}
+	mock.ParseManifestsReturns(nonEmptyManifests(), nil)
+	mock.GetPromotionEdgesReturns(map[promotion.Edge]any{vulnerable: nil}, nil)
+	mock.ScanPromotionEdgesReturns(report, nil)
+	sut.SetImplementation(&mock)
+	sut.SetProvenanceVerifier(&fakeVerifier{result: &provenance.Result{Verified: true}})
+
+	opts := &options.Options{
+		Confirm: true, SeverityThreshold: int(vuln.SeverityHigh), VulnScanPolicy: options.VulnScanPolicyBlockEdges,
+	}
+	require.NoError(t, sut.PromoteImages(context.Background(), opts))
+	require.Equal(t, 0, mock.ValidateStagingSignaturesCallCount())
+	require.Equal(t, 0, mock.PromoteImagesCallCount())
+
+	// Scan errors fail the run
+	mock.ScanPromotionEdgesReturns(nil, errors.New("scanner unavailable"))
+	require.Error(t, sut.PromoteImages(context.Background(), opts))
+}
+
 func TestNewPromoter(t *testing.T) {
 	p := imagepromoter.New(options.DefaultOptions)
 	require.NotNil(t, p)
@@ -323,7 +436,7 @@ func TestSnapshot(t *testing.T) {
 			shouldErr: true,
 			msg:       "GetRegistryImageInventory fails",
 			prepare: func(fpi *imagefakes.FakePromoterImplementation) {
-				fpi.GetRegistryImageInventoryReturns(nil, testErr)
+				fpi.GetRegistryImageInventoryReturns(nil, nil, testErr)
 			},
 		},
 		{
@@ -450,6 +563,13 @@ func TestCheckSignatures(t *testing.T) {
 			msg:       "All signed",
 			prepare:   func(_ *imagefakes.FakePromoterImplementation) {},
 		},
+		{
+			shouldErr: true,
+			msg:       "ValidateOptions fails",
+			prepare: func(fpi *imagefakes.FakePromoterImplementation) {
+				fpi.ValidateOptionsReturns(testErr)
+			},
+		},
 		{
 			shouldErr: true,
 			msg:       "GetLatestImages fails",
@@ -484,6 +604,16 @@ func TestCheckSignatures(t *testing.T) {
 				fpi.FixPartialSignaturesReturns(testErr)
 			},
 		},
+		{
+			shouldErr: true,
+			msg:       "FixMissingAttestations fails",
+			prepare: func(fpi *imagefakes.FakePromoterImplementation) {
+				fpi.GetSignatureStatusReturns(checkresults.Signature{
+					"img@sha256:abc": {Signed: []string{"primary"}, MissingAttestation: []string{"mirror1"}},
+				}, nil)
+				fpi.FixMissingAttestationsReturns(testErr)
+			},
+		},
 	} {
 		t.Run(tc.msg, func(t *testing.T) {
 			sut := imagepromoter.Promoter{}
@@ -516,3 +646,191 @@ func TestCheckSignaturesAllConsistent(t *testing.T) {
 	require.Equal(t, 0, mock.FixMissingSignaturesCallCount())
 	require.Equal(t, 0, mock.FixPartialSignaturesCallCount())
 }
+
+func TestCheckSignaturesReport(t *testing.T) {
+	results := checkresults.Signature{
+		"img@sha256:abc": {Signed: []string{"primary"}, Missing: []string{"mirror1"}},
+	}
+
+	// No report is written unless a format is set
+	mock := imagefakes.FakePromoterImplementation{}
+	mock.GetSignatureStatusReturns(results, nil)
+	sut := imagepromoter.Promoter{}
+	sut.SetImplementation(&mock)
+	require.NoError(t, sut.CheckSignatures(context.Background(), &options.Options{}))
+	require.Equal(t, 0, mock.WriteSignatureReportCallCount())
+
+	// The report is written after the fixes
+	opts := &options.Options{SignCheckOutput: "json"}
+	require.NoError(t, sut.CheckSignatures(context.Background(), opts))
+	require.Equal(t, 1, mock.WriteSignatureReportCallCount())
+	require.Equal(t, 2, mock.FixPartialSignaturesCallCount())
+
+	mock.WriteSignatureReportReturns(errors.New("synthetic error"))
+	require.Error(t, sut.CheckSignatures(context.Background(), opts))
+}
+
+func TestDiff(t *testing.T) {
+	const digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	mfest := schema.Manifest{
+		Registries: []registry.Context{
+			{Name: "gcr.io/staging", Src: true},
+			{Name: "gcr.io/prod"},
+		},
+		Images: []registry.Image{{Name: "foo", Dmap: registry.DigestTags{digest: {"v1.0"}}}},
+	}
+	require.NoError(t, mfest.Finalize())
+
+	sut := imagepromoter.Promoter{}
+	mock := imagefakes.FakePromoterImplementation{}
+	mock.ParseManifestsReturns([]schema.Manifest{mfest}, nil)
+	mock.GetRegistryImageInventoryReturns(registry.RegInvImage{
+		"foo": {digest: {"v1.0", "latest"}},
+	}, nil, nil)
+	sut.SetImplementation(&mock)
+
+	opts := &options.Options{ThinManifestDir: "manifests"}
+
+	diff, err := sut.Diff(context.Background(), opts, "manifests:gcr.io/prod", "gcr.io/prod")
+	require.NoError(t, err)
+	require.Empty(t, diff.Added)
+	require.Empty(t, diff.Removed)
+	require.Equal(t, registry.TagSlice{"latest"}, diff.Retagged[0].AddedTags)
+
+	// The registry source is read as a snapshot, without changing the options
+	snapOpts := mock.GetSnapshotManifestsArgsForCall(0)
+	require.Equal(t, "gcr.io/prod", snapOpts.Snapshot)
+	require.Empty(t, opts.Snapshot)
+
+	mock.ParseManifestsReturns(nil, nil)
+	_, err = sut.Diff(context.Background(), opts, "manifests:gcr.io/prod", "gcr.io/prod")
+	require.Error(t, err)
+
+	_, err = sut.Diff(context.Background(), opts, "file:does-not-exist.yaml", "gcr.io/prod")
+	require.Error(t, err)
+}
+
+func TestAudit(t *testing.T) {
+	sut := imagepromoter.Promoter{}
+	mock := imagefakes.FakePromoterImplementation{}
+	mock.AuditRegistriesReturns(&audit.Report{Findings: []audit.Finding{}}, nil)
+	sut.SetImplementation(&mock)
+
+	opts := &options.Options{ThinManifestDir: "manifests"}
+
+	require.NoError(t, sut.Audit(context.Background(), opts))
+	require.Equal(t, 1, mock.WriteAuditReportCallCount())
+
+	// Findings are reported and fail the audit
+	mock.AuditRegistriesReturns(&audit.Report{Findings: []audit.Finding{
+		{Kind: audit.KindUnknownDigest, Registry: "gcr.io/prod", Image: "foo"},
+	}}, nil)
+	require.ErrorContains(t, sut.Audit(context.Background(), opts), "1 unknown digests")
+	require.Equal(t, 2, mock.WriteAuditReportCallCount())
+
+	mock.AuditRegistriesReturns(nil, errors.New("reading registries"))
+	require.Error(t, sut.Audit(context.Background(), opts))
+	require.Equal(t, 2, mock.WriteAuditReportCallCount())
+}
+
+func TestCheckMirrors(t *testing.T) {
+	sut := imagepromoter.Promoter{}
+	mock := imagefakes.FakePromoterImplementation{}
+	mock.GetMirrorStatusReturns(&mirrorcheck.Report{Findings: []mirrorcheck.Finding{}}, nil)
+	sut.SetImplementation(&mock)
+
+	opts := &options.Options{ThinManifestDir: "manifests", MirrorCheckFix: true}
+
+	// Nothing to fix
+	require.NoError(t, sut.CheckMirrors(context.Background(), opts))
+	require.Equal(t, 0, mock.FixMirrorsCallCount())
+	require.Equal(t, 1, mock.WriteMirrorReportCallCount())
+
+	report := &mirrorcheck.Report{Findings: []mirrorcheck.Finding{
+		{Kind: mirrorcheck.KindMissingDigest, Mirror: "eu.gcr.io/prod", Image: "foo", Source: "us.gcr.io/prod"},
+	}}
+	mock.GetMirrorStatusReturns(report, nil)
+	mock.FixMirrorsCalls(func(_ context.Context, _ *options.Options, r *mirrorcheck.Report) error {
+		r.Findings[0].Fixed = true
+
+		return nil
+	})
+
+	require.NoError(t, sut.CheckMirrors(context.Background(), opts))
+	require.Equal(t, 1, mock.FixMirrorsCallCount())
+
+	// Without --fix, inconsistencies fail the check
+	report.Findings[0].Fixed = false
+	opts.MirrorCheckFix = false
+
+	require.ErrorContains(t, sut.CheckMirrors(context.Background(), opts), "1 missing digests")
+	require.Equal(t, 1, mock.FixMirrorsCallCount())
+	require.Equal(t, 3, mock.WriteMirrorReportCallCount())
+}
+
+func TestStagingGC(t *testing.T) {
+	sut := imagepromoter.Promoter{}
+	mock := imagefakes.FakePromoterImplementation{}
+	mock.PlanStagingGCReturns(&retention.Report{Deletable: []retention.Digest{}}, nil)
+	sut.SetImplementation(&mock)
+
+	opts := &options.Options{ThinManifestDir: "manifests", StagingGCRegistry: "gcr.io/staging"}
+
+	// Nothing to delete
+	require.NoError(t, sut.StagingGC(context.Background(), opts))
+	require.Equal(t, 1, mock.WriteStagingGCReportCallCount())
+
+	report := &retention.Report{
+		Registry:  "gcr.io/staging",
+		Deletable: []retention.Digest{{Image: "foo", Digest: "sha256:old"}},
+	}
+	mock.PlanStagingGCReturns(report, nil)
+
+	// Without --confirm, nothing is deleted
+	require.NoError(t, sut.StagingGC(context.Background(), opts))
+	require.Equal(t, 0, mock.DeleteStagingImagesCallCount())
+
+	opts.Confirm = true
+	mock.DeleteStagingImagesCalls(func(_ context.Context, _ *options.Options, r *retention.Report) error {
+		r.Deletable[0].Error = "denied"
+
+		return nil
+	})
+
+	require.ErrorContains(t, sut.StagingGC(context.Background(), opts), "1 of 1 digests could not be deleted")
+	require.Equal(t, 1, mock.DeleteStagingImagesCallCount())
+	require.Equal(t, 3, mock.WriteStagingGCReportCallCount())
+
+	mock.PlanStagingGCReturns(nil, errors.New("reading registry"))
+	require.Error(t, sut.StagingGC(context.Background(), opts))
+	require.Equal(t, 3, mock.WriteStagingGCReportCallCount())
+}
+
+func TestExportImport(t *testing.T) {
+	sut := imagepromoter.Promoter{}
+	mock := imagefakes.FakePromoterImplementation{}
+	sut.SetImplementation(&mock)
+
+	opts := &options.Options{ThinManifestDir: "manifests", BundleFile: "bundle.tar"}
+
+	require.NoError(t, sut.Export(context.Background(), opts))
+	require.Equal(t, 1, mock.ParseManifestsCallCount())
+	require.Equal(t, 1, mock.ExportImagesCallCount())
+
+	mock.ExportImagesReturns(errors.New("no images to export"))
+	require.Error(t, sut.Export(context.Background(), opts))
+
+	// Import does not need manifests
+	require.NoError(t, sut.Import(context.Background(), opts))
+	require.Equal(t, 2, mock.ParseManifestsCallCount())
+	require.Equal(t, 1, mock.ImportImagesCallCount())
+
+	mock.ImportImagesReturns(errors.New("not signed"))
+	require.Error(t, sut.Import(context.Background(), opts))
+
+	// Invalid options stop the import before anything is pushed
+	mock.ValidateOptionsReturns(errors.New("no bundle file"))
+	require.Error(t, sut.Import(context.Background(), opts))
+	require.Equal(t, 2, mock.ImportImagesCallCount())
+}
@@ -29,6 +29,15 @@ type Context struct {
 	ServiceAccount string         `yaml:"service-account,omitempty"` //nolint:tagliatelle // API field
 	Token          string         `yaml:"-"`
 	Src            bool           `yaml:"src,omitempty"`
+	API            string         `yaml:"api,omitempty"`
+
+	// Credentials authenticate the operations on the registry instead of
+	// the ambient credentials of the promoter.
+	Credentials *Credentials `yaml:"credentials,omitempty"`
+
+	// Transport configures the connections to the host of the registry,
+	// e.g. its certificate authorities or plain HTTP.
+	Transport *TransportConfig `yaml:"transport,omitempty"`
 }
 
 // GetSrcRegistry gets the source registry.
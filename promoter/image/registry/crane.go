@@ -23,10 +23,13 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/gcrane"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	ggcrV1Google "github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	cr "github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -41,10 +44,16 @@ import (
 const readRegistryConcurrency = 20
 
 // CraneProvider implements Provider using go-containerregistry/crane and the
-// Google-specific extensions for optimized registry walking.
+// Google-specific extensions for optimized registry walking. References of
+// OCI image layouts are handled by a LayoutProvider, and copies between a
+// layout and a registry stream the image through the provider.
 type CraneProvider struct {
 	transport http.RoundTripper
+	keychain  authn.Keychain
 	craneOpts []crane.Option
+
+	// layout handles the references of OCI image layouts.
+	layout *LayoutProvider
 }
 
 // CraneOption configures a CraneProvider.
@@ -57,6 +66,14 @@ func WithTransport(rt http.RoundTripper) CraneOption {
 	}
 }
 
+// WithKeychain sets the keychain authenticating registry operations, which
+// defaults to the gcrane keychain.
+func WithKeychain(kc authn.Keychain) CraneOption {
+	return func(p *CraneProvider) {
+		p.keychain = kc
+	}
+}
+
 // WithCraneOptions sets additional crane options for registry operations.
 // This can be used to pass options like crane.Insecure for non-TLS registries.
 func WithCraneOptions(opts ...crane.Option) CraneOption {
@@ -67,7 +84,7 @@ func WithCraneOptions(opts ...crane.Option) CraneOption {
 
 // NewCraneProvider creates a new CraneProvider with the given options.
 func NewCraneProvider(opts ...CraneOption) *CraneProvider {
-	p := &CraneProvider{}
+	p := &CraneProvider{keychain: gcrane.Keychain, layout: NewLayoutProvider()}
 	for _, o := range opts {
 		o(p)
 	}
@@ -76,7 +93,9 @@ func NewCraneProvider(opts ...CraneOption) *CraneProvider {
 }
 
 // ReadRegistries reads the image inventory from one or more registries using
-// google.Walk (recursive) or google.List (non-recursive).
+// google.Walk (recursive) or google.List (non-recursive) for the registries
+// of the Google API, and the OCI distribution API for the others. OCI image
+// layouts are read from disk.
 // baseRegistries, when non-nil, provides the base registry paths used to
 // key the returned inventory. This is needed when registries contain
 // full image paths (e.g. "gcr.io/staging/image") but the inventory must
@@ -106,16 +125,40 @@ func (p *CraneProvider) ReadRegistries(
 
 	for _, r := range registries {
 		g.Go(func() error {
+			if IsLayout(string(r.Name)) {
+				if err := p.layout.readRegistry(r, recurse, inv, &mu, splitRegs); err != nil {
+					return fmt.Errorf("reading registry %s: %w", r.Name, err)
+				}
+
+				logrus.Infof("Read registry %d/%d: %s", completed.Add(1), total, r.Name)
+
+				return nil
+			}
+
+			if registryAPI(r) == APIOCI {
+				if err := p.readOCIRegistry(gctx, r, recurse, inv, &mu, splitRegs); err != nil {
+					return fmt.Errorf("reading registry %s: %w", r.Name, err)
+				}
+
+				logrus.Infof("Read registry %d/%d: %s", completed.Add(1), total, r.Name)
+
+				return nil
+			}
+
 			repo, err := name.NewRepository(string(r.Name))
 			if err != nil {
 				return fmt.Errorf("parsing repo name %s: %w", r.Name, err)
 			}
 
 			walkOpts := []ggcrV1Google.Option{
-				ggcrV1Google.WithAuthFromKeychain(gcrane.Keychain),
+				ggcrV1Google.WithAuthFromKeychain(p.keychain),
 				ggcrV1Google.WithContext(gctx),
 			}
 
+			if p.transport != nil {
+				walkOpts = append(walkOpts, ggcrV1Google.WithTransport(p.transport))
+			}
+
 			recordTags := makeTagRecorder(inv, &mu, splitRegs)
 
 			if recurse {
@@ -158,27 +201,218 @@ func (p *CraneProvider) ReadRegistries(
 	return inv, nil
 }
 
-// CopyImage copies a container image from src to dst using crane.
-// No per-request timeout is applied here because promoted images can
-// have large layers whose transfer time is unpredictable.
-func (p *CraneProvider) CopyImage(_ context.Context, src, dst string) error {
-	opts := []crane.Option{
-		crane.WithAuthFromKeychain(gcrane.Keychain),
-		crane.WithUserAgent(image.UserAgent),
+// CopyImage copies a container image from src to dst using crane. The
+// copy stops when ctx is cancelled, but no timeout is applied here because
+// promoted images can have large layers whose transfer time is
+// unpredictable.
+func (p *CraneProvider) CopyImage(ctx context.Context, src, dst string) error {
+	if IsLayout(src) || IsLayout(dst) {
+		return p.copyLayout(ctx, src, dst)
 	}
-	if p.transport != nil {
-		opts = append(opts, crane.WithTransport(p.transport))
+
+	if err := crane.Copy(src, dst, p.options(ctx)...); err != nil {
+		return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
 	}
 
-	opts = append(opts, p.craneOpts...)
+	return nil
+}
+
+// DeleteTag removes a tag using crane.
+func (p *CraneProvider) DeleteTag(ctx context.Context, ref string) error {
+	if IsLayout(ref) {
+		return p.layout.DeleteTag(ctx, ref)
+	}
+
+	tag, err := name.NewTag(ref)
+	if err != nil {
+		return fmt.Errorf("parsing tag reference %s: %w", ref, err)
+	}
 
-	if err := crane.Copy(src, dst, opts...); err != nil {
+	if err := crane.Delete(tag.String(), p.options(ctx)...); err != nil {
+		return fmt.Errorf("deleting tag %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// DeleteManifest deletes the manifest of a digest reference using crane.
+func (p *CraneProvider) DeleteManifest(ctx context.Context, ref string) error {
+	if IsLayout(ref) {
+		return p.layout.DeleteManifest(ctx, ref)
+	}
+
+	digest, err := name.NewDigest(ref)
+	if err != nil {
+		return fmt.Errorf("parsing digest reference %s: %w", ref, err)
+	}
+
+	if err := crane.Delete(digest.String(), p.options(ctx)...); err != nil {
+		return fmt.Errorf("deleting manifest %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// GetManifest fetches the manifest of a reference.
+func (p *CraneProvider) GetManifest(ctx context.Context, ref string) ([]byte, cr.MediaType, error) {
+	if IsLayout(ref) {
+		return p.layout.GetManifest(ctx, ref)
+	}
+
+	o := crane.GetOptions(p.options(ctx)...)
+
+	r, err := name.ParseReference(ref, o.Name...)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing reference %s: %w", ref, err)
+	}
+
+	desc, err := remote.Get(r, o.Remote...)
+	if err != nil {
+		return nil, "", fmt.Errorf("getting manifest %s: %w", ref, err)
+	}
+
+	return desc.Manifest, desc.MediaType, nil
+}
+
+// HeadDigest resolves a reference to its digest with a HEAD request.
+func (p *CraneProvider) HeadDigest(ctx context.Context, ref string) (image.Digest, error) {
+	if IsLayout(ref) {
+		return p.layout.HeadDigest(ctx, ref)
+	}
+
+	digest, err := crane.Digest(ref, p.options(ctx)...)
+	if err != nil {
+		return "", fmt.Errorf("getting digest of %s: %w", ref, err)
+	}
+
+	return image.Digest(digest), nil
+}
+
+// ListReferrers lists the referrers of a digest using the OCI referrers
+// API, or the referrers tag schema on registries that do not support it.
+func (p *CraneProvider) ListReferrers(ctx context.Context, ref, artifactType string) ([]v1.Descriptor, error) {
+	if IsLayout(ref) {
+		return p.layout.ListReferrers(ctx, ref, artifactType)
+	}
+
+	o := crane.GetOptions(p.options(ctx)...)
+
+	digest, err := name.NewDigest(ref, o.Name...)
+	if err != nil {
+		return nil, fmt.Errorf("parsing digest reference %s: %w", ref, err)
+	}
+
+	remoteOpts := o.Remote
+	if artifactType != "" {
+		remoteOpts = append(remoteOpts, remote.WithFilter("artifactType", artifactType))
+	}
+
+	idx, err := remote.Referrers(digest, remoteOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("listing referrers of %s: %w", ref, err)
+	}
+
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("reading referrers of %s: %w", ref, err)
+	}
+
+	return manifest.Manifests, nil
+}
+
+// ListTags lists the tags of a repository using crane.
+func (p *CraneProvider) ListTags(ctx context.Context, repo string) ([]image.Tag, error) {
+	if IsLayout(repo) {
+		return p.layout.ListTags(ctx, repo)
+	}
+
+	tags, err := crane.ListTags(repo, p.options(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags of %s: %w", repo, err)
+	}
+
+	result := make([]image.Tag, 0, len(tags))
+	for _, tag := range tags {
+		result = append(result, image.Tag(tag))
+	}
+
+	return result, nil
+}
+
+// copyLayout copies an image or image index into or out of an OCI image
+// layout.
+func (p *CraneProvider) copyLayout(ctx context.Context, src, dst string) error {
+	o := crane.GetOptions(p.options(ctx)...)
+
+	var a artifact
+
+	if IsLayout(src) {
+		var err error
+		if a, err = p.layout.read(src); err != nil {
+			return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
+		}
+	} else {
+		ref, err := name.ParseReference(src, o.Name...)
+		if err != nil {
+			return fmt.Errorf("parsing reference %s: %w", src, err)
+		}
+
+		desc, err := remote.Get(ref, o.Remote...)
+		if err != nil {
+			return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
+		}
+
+		if desc.MediaType.IsIndex() {
+			a.index, err = desc.ImageIndex()
+		} else {
+			a.image, err = desc.Image()
+		}
+
+		if err != nil {
+			return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
+		}
+	}
+
+	if IsLayout(dst) {
+		if err := p.layout.write(dst, a); err != nil {
+			return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
+		}
+
+		return nil
+	}
+
+	ref, err := name.ParseReference(dst, o.Name...)
+	if err != nil {
+		return fmt.Errorf("parsing reference %s: %w", dst, err)
+	}
+
+	if a.index != nil {
+		err = remote.WriteIndex(ref, a.index, o.Remote...)
+	} else {
+		err = remote.Write(ref, a.image, o.Remote...)
+	}
+
+	if err != nil {
 		return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
 	}
 
 	return nil
 }
 
+// options returns the crane options of the registry operations.
+func (p *CraneProvider) options(ctx context.Context) []crane.Option {
+	opts := []crane.Option{
+		crane.WithAuthFromKeychain(p.keychain),
+		crane.WithUserAgent(image.UserAgent),
+		crane.WithContext(ctx),
+	}
+	if p.transport != nil {
+		opts = append(opts, crane.WithTransport(p.transport))
+	}
+
+	return append(opts, p.craneOpts...)
+}
+
 // makeTagRecorder creates a callback function for google.Walk that records
 // found tags into the inventory.
 func makeTagRecorder(
@@ -216,6 +450,12 @@ func makeTagRecorder(
 
 				mu.Lock()
 				inv.MediaTypes[image.Digest(digest)] = mediaType
+				inv.Details[image.Digest(digest)] = ManifestDetails{
+					MediaType: cr.MediaType(manifest.MediaType),
+					Size:      int64(manifest.Size), //nolint:gosec // image sizes fit in an int64
+					Created:   manifest.Created,
+					Uploaded:  manifest.Uploaded,
+				}
 				mu.Unlock()
 			}
 		}
@@ -18,17 +18,24 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
 	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-containerregistry/pkg/crane"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/stretchr/testify/require"
 
 	"sigs.k8s.io/promo-tools/v4/types/image"
@@ -355,6 +362,221 @@ func TestCraneProviderCopyImageIndex(t *testing.T) {
 	require.Equal(t, srcDigest, dstDigest)
 }
 
+func TestCraneProviderDelete(t *testing.T) {
+	t.Parallel()
+
+	host := newTestRegistry(t)
+
+	tagRef := host + "/staging/myimage:v1.0"
+	digest := pushRandomImage(t, tagRef)
+
+	p := newInsecureCraneProvider()
+	require.NoError(t, p.DeleteTag(context.Background(), tagRef))
+
+	_, err := crane.Digest(tagRef, crane.Insecure)
+	require.Error(t, err)
+
+	digestRef := host + "/staging/myimage@" + digest
+	require.NoError(t, p.DeleteManifest(context.Background(), digestRef))
+
+	_, err = crane.Digest(digestRef, crane.Insecure)
+	require.Error(t, err)
+
+	// References of the wrong kind are rejected
+	require.Error(t, p.DeleteTag(context.Background(), digestRef))
+	require.Error(t, p.DeleteManifest(context.Background(), tagRef))
+}
+
+func TestCraneProviderManifestOperations(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(s.Close)
+
+	host := s.Listener.Addr().String()
+
+	tagRef := host + "/staging/myimage:v1.0"
+	digest := pushRandomImage(t, tagRef)
+	pushRandomImage(t, host+"/staging/myimage:v1.1")
+
+	p := newInsecureCraneProvider()
+	ctx := context.Background()
+
+	headDigest, err := p.HeadDigest(ctx, tagRef)
+	require.NoError(t, err)
+	require.EqualValues(t, digest, headDigest)
+
+	raw, mediaType, err := p.GetManifest(ctx, tagRef)
+	require.NoError(t, err)
+	require.NotEmpty(t, raw)
+	require.Equal(t, cr.DockerManifestSchema2, mediaType)
+
+	_, _, err = p.GetManifest(ctx, host+"/staging/myimage:missing")
+	require.Error(t, err)
+
+	tags, err := p.ListTags(ctx, host+"/staging/myimage")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []image.Tag{"v1.0", "v1.1"}, tags)
+
+	// Attach an artifact to the image
+	digestRef := host + "/staging/myimage@" + digest
+	ref, err := name.ParseReference(digestRef, name.Insecure)
+	require.NoError(t, err)
+
+	subject, err := remote.Head(ref)
+	require.NoError(t, err)
+
+	artifact, err := random.Image(1024, 1)
+	require.NoError(t, err)
+
+	artifact = mutate.ConfigMediaType(
+		mutate.MediaType(artifact, cr.OCIManifestSchema1), "application/vnd.example+json",
+	)
+	artifact, ok := mutate.Subject(artifact, *subject).(v1.Image)
+	require.True(t, ok)
+
+	artifactDigest, err := artifact.Digest()
+	require.NoError(t, err)
+	require.NoError(t, crane.Push(artifact, host+"/staging/myimage@"+artifactDigest.String(), crane.Insecure))
+
+	referrers, err := p.ListReferrers(ctx, digestRef, "")
+	require.NoError(t, err)
+	require.Len(t, referrers, 1)
+	require.Equal(t, artifactDigest, referrers[0].Digest)
+
+	referrers, err = p.ListReferrers(ctx, digestRef, "application/vnd.other+json")
+	require.NoError(t, err)
+	require.Empty(t, referrers)
+
+	// Referrers are listed by digest only
+	_, err = p.ListReferrers(ctx, tagRef, "")
+	require.Error(t, err)
+}
+
+func TestCraneProviderReadRegistriesOCI(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewServer(paginate(registry.New()))
+	t.Cleanup(s.Close)
+
+	host := s.Listener.Addr().String()
+
+	digestA := pushRandomImage(t, host+"/staging/foo:v1.0")
+	require.NoError(t, crane.Tag(host+"/staging/foo:v1.0", "latest", crane.Insecure))
+	digestB := pushRandomImage(t, host+"/staging/foo:v1.1")
+	digestC := pushRandomImage(t, host+"/staging/nested/bar:v2.0")
+	pushRandomImage(t, host+"/other/baz:v1.0")
+
+	p := newInsecureCraneProvider()
+	staging := image.Registry(host + "/staging")
+
+	inv, err := p.ReadRegistries(context.Background(), []RegistryConfig{{Name: staging, Src: true}}, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, RegInvImage{
+		"foo": {
+			image.Digest(digestA): {"latest", "v1.0"},
+			image.Digest(digestB): {"v1.1"},
+		},
+		"nested/bar": {image.Digest(digestC): {"v2.0"}},
+	}, sortedTags(inv.Images[staging]))
+	require.Equal(t, cr.DockerManifestSchema2, inv.MediaTypes[image.Digest(digestA)])
+	require.Positive(t, inv.Details[image.Digest(digestA)].Size)
+
+	// Repositories read by name only, as promotion does, may not exist yet
+	inv, err = p.ReadRegistries(
+		context.Background(),
+		[]RegistryConfig{{Name: staging + "/foo"}, {Name: image.Registry(host + "/production/foo")}},
+		false,
+		[]RegistryConfig{{Name: staging}, {Name: image.Registry(host + "/production")}},
+	)
+	require.NoError(t, err)
+	require.Len(t, inv.Images[staging]["foo"], 2)
+	require.Empty(t, inv.Images[image.Registry(host+"/production")])
+}
+
+// paginate wraps a registry to serve its catalog and tag lists one entry
+// per page, linking each page to the next one like Docker Hub or Harbor.
+func paginate(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := "tags"
+
+		switch {
+		case r.URL.Path == "/v2/_catalog":
+			key = "repositories"
+		case strings.HasSuffix(r.URL.Path, "/tags/list"):
+		default:
+			h.ServeHTTP(w, r)
+
+			return
+		}
+
+		full := r.Clone(r.Context())
+		full.URL.RawQuery = ""
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, full)
+
+		body := map[string]any{}
+		if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &body) != nil {
+			w.WriteHeader(rec.Code)
+			_, _ = w.Write(rec.Body.Bytes())
+
+			return
+		}
+
+		items := []string{}
+		for _, item := range body[key].([]any) {
+			items = append(items, item.(string))
+		}
+
+		sort.Strings(items)
+
+		start := 0
+		if last := r.URL.Query().Get("last"); last != "" {
+			start = sort.SearchStrings(items, last) + 1
+		}
+
+		page := items[min(start, len(items)):min(start+1, len(items))]
+		if start+1 < len(items) {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?n=1&last=%s>; rel="next"`, r.URL.Path, url.QueryEscape(page[0])))
+		}
+
+		body[key] = page
+
+		_ = json.NewEncoder(w).Encode(body)
+	})
+}
+
+// sortedTags returns rii with the tags of every digest sorted.
+func sortedTags(rii RegInvImage) RegInvImage {
+	for _, dmap := range rii {
+		for digest, tags := range dmap {
+			dmap[digest] = tags.ToTagSet().ToSorted()
+		}
+	}
+
+	return rii
+}
+
+func TestRegistryAPI(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		config RegistryConfig
+		want   string
+	}{
+		{RegistryConfig{Name: "gcr.io/k8s-staging-foo"}, APIGoogle},
+		{RegistryConfig{Name: "us.gcr.io/k8s-artifacts-prod"}, APIGoogle},
+		{RegistryConfig{Name: "us-central1-docker.pkg.dev/k8s-artifacts-prod/images"}, APIGoogle},
+		{RegistryConfig{Name: "ghcr.io/kubernetes"}, APIOCI},
+		{RegistryConfig{Name: "localhost:5000/staging"}, APIOCI},
+		{RegistryConfig{Name: "mirror.example.com/gcr", API: APIGoogle}, APIGoogle},
+		{RegistryConfig{Name: "gcr.io/k8s-staging-foo", API: APIOCI}, APIOCI},
+	} {
+		require.Equal(t, tc.want, registryAPI(tc.config), tc.config.Name)
+	}
+}
+
 // countingTransport wraps an http.RoundTripper and counts requests.
 type countingTransport struct {
 	base  http.RoundTripper
@@ -0,0 +1,390 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/docker/cli/cli/config"
+	"github.com/docker/cli/cli/config/configfile"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	ggcrV1Google "github.com/google/go-containerregistry/pkg/v1/google"
+	"golang.org/x/oauth2"
+	googauth "golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// cloudPlatformScope is the OAuth2 scope of the Google access tokens used
+// to authenticate to Google registries.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// Credentials configures how the promoter authenticates to a registry in
+// place of its ambient credentials. Secrets are never written in manifests,
+// they are read from environment variables or files. Exactly one source
+// must be set.
+type Credentials struct {
+	// DockerConfig is the path of a Docker config.json file holding the
+	// credentials of the registry, or of its host.
+	DockerConfig string `yaml:"dockerConfig,omitempty"`
+
+	// Username is the user name of basic authentication. UsernameEnv is
+	// an environment variable holding it instead.
+	Username    string `yaml:"username,omitempty"`
+	UsernameEnv string `yaml:"usernameEnv,omitempty"`
+
+	// PasswordEnv and PasswordFile are the environment variable or file
+	// holding the password of basic authentication.
+	PasswordEnv  string `yaml:"passwordEnv,omitempty"`
+	PasswordFile string `yaml:"passwordFile,omitempty"`
+
+	// TokenEnv and TokenFile are the environment variable or file holding
+	// a bearer token sent to the registry.
+	TokenEnv  string `yaml:"tokenEnv,omitempty"`
+	TokenFile string `yaml:"tokenFile,omitempty"`
+
+	// WorkloadIdentity authenticates with the Google application default
+	// credentials, e.g. the GKE workload identity of the promoter.
+	WorkloadIdentity bool `yaml:"workloadIdentity,omitempty"`
+}
+
+// Validate checks that exactly one source of credentials is set.
+func (c *Credentials) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	sources := []string{}
+
+	if c.DockerConfig != "" {
+		sources = append(sources, "dockerConfig")
+	}
+
+	basic := c.Username != "" || c.UsernameEnv != "" || c.PasswordEnv != "" || c.PasswordFile != ""
+	if basic {
+		sources = append(sources, "basic")
+
+		if (c.Username == "") == (c.UsernameEnv == "") {
+			return errors.New("basic credentials need one of username or usernameEnv")
+		}
+
+		if (c.PasswordEnv == "") == (c.PasswordFile == "") {
+			return errors.New("basic credentials need one of passwordEnv or passwordFile")
+		}
+	}
+
+	if c.TokenEnv != "" || c.TokenFile != "" {
+		sources = append(sources, "token")
+
+		if c.TokenEnv != "" && c.TokenFile != "" {
+			return errors.New("tokenEnv and tokenFile are mutually exclusive")
+		}
+	}
+
+	if c.WorkloadIdentity {
+		sources = append(sources, "workloadIdentity")
+	}
+
+	switch len(sources) {
+	case 0:
+		return errors.New("no credentials set")
+	case 1:
+		return nil
+	default:
+		return fmt.Errorf("only one source of credentials can be set, found: %s", strings.Join(sources, ", "))
+	}
+}
+
+// Keychain resolves the credentials of the registries declared in
+// manifests, and of any other registry with a fallback keychain. A
+// resource uses the credentials of the longest registry it belongs to, so
+// registries of the same host can be owned by different principals.
+type Keychain struct {
+	fallback authn.Keychain
+
+	// tokenSource returns the Google token source impersonating a service
+	// account, or of the application default credentials when empty.
+	tokenSource func(ctx context.Context, serviceAccount string) (oauth2.TokenSource, error)
+
+	mu         sync.RWMutex
+	registries map[image.Registry]registryKeychain
+}
+
+// registryKeychain is the keychain of a registry and the context it was
+// built from.
+type registryKeychain struct {
+	keychain authn.Keychain
+	context  Context
+}
+
+// NewKeychain creates a Keychain resolving the registries without
+// credentials with a fallback keychain.
+func NewKeychain(fallback authn.Keychain) *Keychain {
+	return &Keychain{
+		fallback:    fallback,
+		tokenSource: googleTokenSource,
+		registries:  map[image.Registry]registryKeychain{},
+	}
+}
+
+// AddContexts registers the credentials of registry contexts. The service
+// account of a registry is impersonated only when useServiceAccount is
+// true, to keep the ambient credentials of existing manifests naming the
+// account the promoter already runs as.
+func (k *Keychain) AddContexts(ctx context.Context, rcs []Context, useServiceAccount bool) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for _, rc := range rcs {
+		rc.ServiceAccount = serviceAccount(rc, useServiceAccount)
+		if rc.ServiceAccount == "" && rc.Token == "" && rc.Credentials == nil {
+			continue
+		}
+
+		if existing, ok := k.registries[rc.Name]; ok {
+			if !sameCredentials(existing.context, rc) {
+				return fmt.Errorf("conflicting credentials for registry %s", rc.Name)
+			}
+
+			continue
+		}
+
+		kc, err := k.keychainFor(ctx, rc)
+		if err != nil {
+			return fmt.Errorf("credentials of registry %s: %w", rc.Name, err)
+		}
+
+		k.registries[rc.Name] = registryKeychain{keychain: kc, context: rc}
+	}
+
+	return nil
+}
+
+// Resolve implements authn.Keychain.
+func (k *Keychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	k.mu.RLock()
+
+	var (
+		match string
+		kc    = k.fallback
+	)
+
+	resource := target.String()
+
+	for reg, rk := range k.registries {
+		prefix := string(reg)
+		if len(prefix) > len(match) && (resource == prefix || strings.HasPrefix(resource, prefix+"/")) {
+			match, kc = prefix, rk.keychain
+		}
+	}
+
+	k.mu.RUnlock()
+
+	auth, err := kc.Resolve(target)
+	if err != nil {
+		return nil, fmt.Errorf("resolving credentials of %s: %w", resource, err)
+	}
+
+	return auth, nil
+}
+
+// keychainFor builds the keychain of a registry context.
+func (k *Keychain) keychainFor(ctx context.Context, rc Context) (authn.Keychain, error) {
+	switch {
+	case rc.Credentials != nil:
+		return k.credentialsKeychain(ctx, rc.Credentials)
+	case rc.Token != "":
+		// Tokens are OAuth2 access tokens of Google registries
+		return staticKeychain{&authn.Basic{Username: "oauth2accesstoken", Password: rc.Token}}, nil
+	default:
+		ts, err := k.tokenSource(ctx, rc.ServiceAccount)
+		if err != nil {
+			return nil, fmt.Errorf("impersonating %s: %w", rc.ServiceAccount, err)
+		}
+
+		return staticKeychain{ggcrV1Google.NewTokenSourceAuthenticator(ts)}, nil
+	}
+}
+
+// credentialsKeychain builds the keychain of the credentials of a
+// registry.
+func (k *Keychain) credentialsKeychain(ctx context.Context, c *Credentials) (authn.Keychain, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c.DockerConfig != "":
+		f, err := os.Open(c.DockerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("opening docker config: %w", err)
+		}
+		defer f.Close()
+
+		cf, err := config.LoadFromReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("loading docker config %s: %w", c.DockerConfig, err)
+		}
+
+		return &dockerConfigKeychain{config: cf}, nil
+	case c.TokenEnv != "" || c.TokenFile != "":
+		token, err := readSecret(c.TokenEnv, c.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading token: %w", err)
+		}
+
+		return staticKeychain{&authn.Bearer{Token: token}}, nil
+	case c.WorkloadIdentity:
+		ts, err := k.tokenSource(ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("finding application default credentials: %w", err)
+		}
+
+		return staticKeychain{ggcrV1Google.NewTokenSourceAuthenticator(ts)}, nil
+	default:
+		username := c.Username
+		if c.UsernameEnv != "" {
+			var err error
+			if username, err = readSecret(c.UsernameEnv, ""); err != nil {
+				return nil, fmt.Errorf("reading username: %w", err)
+			}
+		}
+
+		password, err := readSecret(c.PasswordEnv, c.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading password: %w", err)
+		}
+
+		return staticKeychain{&authn.Basic{Username: username, Password: password}}, nil
+	}
+}
+
+// serviceAccount returns the service account impersonated for a registry
+// context, if any.
+func serviceAccount(rc Context, useServiceAccount bool) string {
+	if !useServiceAccount || rc.Credentials != nil || rc.Token != "" {
+		return ""
+	}
+
+	return rc.ServiceAccount
+}
+
+// sameCredentials returns whether two registry contexts authenticate the
+// same way.
+func sameCredentials(a, b Context) bool {
+	return a.ServiceAccount == b.ServiceAccount &&
+		a.Token == b.Token &&
+		reflect.DeepEqual(a.Credentials, b.Credentials)
+}
+
+// readSecret reads a secret from an environment variable or, when env is
+// empty, from a file. Surrounding whitespace is trimmed.
+func readSecret(env, file string) (string, error) {
+	if env != "" {
+		value, ok := os.LookupEnv(env)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", env)
+		}
+
+		return strings.TrimSpace(value), nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", file, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// googleTokenSource returns a Google token source impersonating a service
+// account, or of the application default credentials when it is empty.
+func googleTokenSource(ctx context.Context, serviceAccount string) (oauth2.TokenSource, error) {
+	if serviceAccount == "" {
+		ts, err := googauth.DefaultTokenSource(ctx, cloudPlatformScope)
+		if err != nil {
+			return nil, fmt.Errorf("getting default token source: %w", err)
+		}
+
+		return ts, nil
+	}
+
+	ts, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+		TargetPrincipal: serviceAccount,
+		Scopes:          []string{cloudPlatformScope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating impersonated token source: %w", err)
+	}
+
+	return ts, nil
+}
+
+// staticKeychain resolves every resource with the same authenticator.
+type staticKeychain struct {
+	auth authn.Authenticator
+}
+
+func (s staticKeychain) Resolve(authn.Resource) (authn.Authenticator, error) {
+	return s.auth, nil
+}
+
+// dockerConfigKeychain resolves resources with the credentials of a Docker
+// config file, looked up by repository and then by host.
+type dockerConfigKeychain struct {
+	mu     sync.Mutex
+	config *configfile.ConfigFile
+}
+
+func (d *dockerConfigKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range []string{target.String(), target.RegistryStr()} {
+		if key == name.DefaultRegistry {
+			key = authn.DefaultAuthKey
+		}
+
+		cfg, err := d.config.GetAuthConfig(key)
+		if err != nil {
+			return nil, fmt.Errorf("getting credentials of %s: %w", key, err)
+		}
+
+		if cfg.Username == "" && cfg.Password == "" && cfg.Auth == "" &&
+			cfg.IdentityToken == "" && cfg.RegistryToken == "" {
+			continue
+		}
+
+		return authn.FromConfig(authn.AuthConfig{
+			Username:      cfg.Username,
+			Password:      cfg.Password,
+			Auth:          cfg.Auth,
+			IdentityToken: cfg.IdentityToken,
+			RegistryToken: cfg.RegistryToken,
+		}), nil
+	}
+
+	return authn.Anonymous, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestCredentialsValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name        string
+		credentials *Credentials
+		wantErr     string
+	}{
+		{name: "unset"},
+		{name: "empty", credentials: &Credentials{}, wantErr: "no credentials set"},
+		{name: "docker config", credentials: &Credentials{DockerConfig: "config.json"}},
+		{name: "basic", credentials: &Credentials{Username: "user", PasswordEnv: "PASSWORD"}},
+		{
+			name:        "basic without username",
+			credentials: &Credentials{PasswordFile: "password"},
+			wantErr:     "need one of username or usernameEnv",
+		},
+		{
+			name:        "basic with two passwords",
+			credentials: &Credentials{UsernameEnv: "USER", PasswordEnv: "PASSWORD", PasswordFile: "password"},
+			wantErr:     "need one of passwordEnv or passwordFile",
+		},
+		{
+			name:        "two tokens",
+			credentials: &Credentials{TokenEnv: "TOKEN", TokenFile: "token"},
+			wantErr:     "mutually exclusive",
+		},
+		{
+			name:        "two sources",
+			credentials: &Credentials{TokenEnv: "TOKEN", WorkloadIdentity: true},
+			wantErr:     "found: token, workloadIdentity",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.credentials.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeychain(t *testing.T) {
+	dir := t.TempDir()
+
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("s3cret\n"), 0o600))
+
+	dockerConfig := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(dockerConfig, []byte(
+		`{"auths": {"registry.example.com": {"username": "docker", "password": "pass"}}}`,
+	), 0o600))
+
+	t.Setenv("PROMO_TEST_TOKEN", "bearer-token")
+
+	impersonated := []string{}
+
+	kc := NewKeychain(staticKeychain{authn.Anonymous})
+	kc.tokenSource = func(_ context.Context, serviceAccount string) (oauth2.TokenSource, error) {
+		impersonated = append(impersonated, serviceAccount)
+
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access-" + serviceAccount}), nil
+	}
+
+	require.NoError(t, kc.AddContexts(context.Background(), []Context{
+		{Name: "ghcr.io/staging", Src: true},
+		{Name: "ghcr.io/prod", Credentials: &Credentials{Username: "user", PasswordFile: passwordFile}},
+		{Name: "ghcr.io/prod/nested", Credentials: &Credentials{TokenEnv: "PROMO_TEST_TOKEN"}},
+		{Name: "registry.example.com/prod", Credentials: &Credentials{DockerConfig: dockerConfig}},
+		{Name: "gcr.io/prod", ServiceAccount: "prod@example.iam.gserviceaccount.com"},
+		{Name: "gcr.io/legacy", ServiceAccount: "legacy@example.iam.gserviceaccount.com", Token: "access-token"},
+		{Name: "us-docker.pkg.dev/prod", Credentials: &Credentials{WorkloadIdentity: true}},
+	}, true))
+
+	// Registering the same credentials again is a no-op
+	require.NoError(t, kc.AddContexts(context.Background(), []Context{
+		{Name: "gcr.io/prod", ServiceAccount: "prod@example.iam.gserviceaccount.com"},
+	}, true))
+	require.Equal(t, []string{"prod@example.iam.gserviceaccount.com", ""}, impersonated)
+
+	for _, tc := range []struct {
+		repo string
+		want authn.AuthConfig
+	}{
+		{repo: "ghcr.io/staging/foo", want: authn.AuthConfig{}},
+		{repo: "ghcr.io/prod/foo", want: authn.AuthConfig{Username: "user", Password: "s3cret"}},
+		{repo: "ghcr.io/prod/nested/foo", want: authn.AuthConfig{RegistryToken: "bearer-token"}},
+		{repo: "ghcr.io/prodx/foo", want: authn.AuthConfig{}},
+		{repo: "registry.example.com/prod/foo", want: authn.AuthConfig{Username: "docker", Password: "pass"}},
+		{
+			repo: "gcr.io/prod/foo",
+			want: authn.AuthConfig{Username: "_token", Password: "access-prod@example.iam.gserviceaccount.com"},
+		},
+		{repo: "gcr.io/legacy/foo", want: authn.AuthConfig{Username: "oauth2accesstoken", Password: "access-token"}},
+		{repo: "us-docker.pkg.dev/prod/foo", want: authn.AuthConfig{Username: "_token", Password: "access-"}},
+	} {
+		repo, err := name.NewRepository(tc.repo)
+		require.NoError(t, err)
+
+		auth, err := kc.Resolve(repo)
+		require.NoError(t, err)
+
+		cfg, err := auth.Authorization()
+		require.NoError(t, err)
+		require.Equal(t, tc.want, *cfg, tc.repo)
+	}
+}
+
+func TestKeychainAddContextsErrors(t *testing.T) {
+	kc := NewKeychain(authn.DefaultKeychain)
+	kc.tokenSource = func(context.Context, string) (oauth2.TokenSource, error) {
+		return oauth2.StaticTokenSource(&oauth2.Token{}), nil
+	}
+
+	// Service accounts are only impersonated on demand
+	require.NoError(t, kc.AddContexts(context.Background(), []Context{
+		{Name: "gcr.io/prod", ServiceAccount: "prod@example.iam.gserviceaccount.com"},
+	}, false))
+	require.Empty(t, kc.registries)
+
+	require.ErrorContains(t, kc.AddContexts(context.Background(), []Context{
+		{Name: "ghcr.io/prod", Credentials: &Credentials{TokenEnv: "PROMO_TEST_UNSET_TOKEN"}},
+	}, false), "environment variable PROMO_TEST_UNSET_TOKEN is not set")
+
+	require.NoError(t, kc.AddContexts(context.Background(), []Context{
+		{Name: "gcr.io/prod", ServiceAccount: "prod@example.iam.gserviceaccount.com"},
+	}, true))
+	require.ErrorContains(t, kc.AddContexts(context.Background(), []Context{
+		{Name: "gcr.io/prod", ServiceAccount: "other@example.iam.gserviceaccount.com"},
+	}, true), "conflicting credentials for registry gcr.io/prod")
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// InventoryDiff lists the differences between two RegInvImages, from the
+// left one to the right one.
+type InventoryDiff struct {
+	// Added are the digests only found on the right.
+	Added []DigestDiff `json:"added"`
+
+	// Removed are the digests only found on the left.
+	Removed []DigestDiff `json:"removed"`
+
+	// Retagged are the digests found on both sides with different tags.
+	Retagged []DigestDiff `json:"retagged"`
+}
+
+// DigestDiff is a digest of an image that differs between two RegInvImages.
+type DigestDiff struct {
+	Name   image.Name   `json:"name"`
+	Digest image.Digest `json:"digest"`
+
+	// Tags are the tags of an added or removed digest.
+	Tags TagSlice `json:"tags,omitempty"`
+
+	// AddedTags are the tags of a retagged digest only found on the right.
+	AddedTags TagSlice `json:"addedTags,omitempty"`
+
+	// RemovedTags are the tags of a retagged digest only found on the left.
+	RemovedTags TagSlice `json:"removedTags,omitempty"`
+}
+
+// Diff compares a RegInvImage (left) with another one (right). The
+// results are sorted by image name and digest.
+func (a RegInvImage) Diff(b RegInvImage) *InventoryDiff {
+	diff := &InventoryDiff{
+		Added:    []DigestDiff{},
+		Removed:  []DigestDiff{},
+		Retagged: []DigestDiff{},
+	}
+
+	for name, dmap := range a {
+		for digest, tags := range dmap {
+			otherTags, ok := b[name][digest]
+			if !ok {
+				diff.Removed = append(diff.Removed, DigestDiff{
+					Name: name, Digest: digest, Tags: tags.ToTagSet().ToSorted(),
+				})
+
+				continue
+			}
+
+			added := otherTags.Minus(tags)
+			removed := tags.Minus(otherTags)
+
+			if len(added) > 0 || len(removed) > 0 {
+				diff.Retagged = append(diff.Retagged, DigestDiff{
+					Name:        name,
+					Digest:      digest,
+					AddedTags:   added.ToSorted(),
+					RemovedTags: removed.ToSorted(),
+				})
+			}
+		}
+	}
+
+	for name, dmap := range b {
+		for digest, tags := range dmap {
+			if _, ok := a[name][digest]; !ok {
+				diff.Added = append(diff.Added, DigestDiff{
+					Name: name, Digest: digest, Tags: tags.ToTagSet().ToSorted(),
+				})
+			}
+		}
+	}
+
+	for _, list := range [][]DigestDiff{diff.Added, diff.Removed, diff.Retagged} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Name != list[j].Name {
+				return list[i].Name < list[j].Name
+			}
+
+			return list[i].Digest < list[j].Digest
+		})
+	}
+
+	return diff
+}
+
+// Empty returns true if there are no differences.
+func (d *InventoryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Retagged) == 0
+}
+
+// ToJSON renders the differences as indented JSON.
+func (d *InventoryDiff) ToJSON() (string, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling diff to JSON: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// ToText renders the differences one digest per line, prefixed with "+"
+// when added, "-" when removed and "~" when retagged.
+//
+// Example:
+// + a@sha256:0000000000000000000000000000000000000000000000000000000000000000 [1.0]
+// - b@sha256:1111111111111111111111111111111111111111111111111111111111111111 []
+// ~ c@sha256:2222222222222222222222222222222222222222222222222222222222222222 +[1.1] -[latest].
+func (d *InventoryDiff) ToText() string {
+	var b strings.Builder
+
+	for _, e := range d.Added {
+		fmt.Fprintf(&b, "+ %s@%s %s\n", e.Name, e.Digest, formatTags(e.Tags))
+	}
+
+	for _, e := range d.Removed {
+		fmt.Fprintf(&b, "- %s@%s %s\n", e.Name, e.Digest, formatTags(e.Tags))
+	}
+
+	for _, e := range d.Retagged {
+		fmt.Fprintf(&b, "~ %s@%s +%s -%s\n", e.Name, e.Digest, formatTags(e.AddedTags), formatTags(e.RemovedTags))
+	}
+
+	return b.String()
+}
+
+func formatTags(tags TagSlice) string {
+	s := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		s = append(s, string(tag))
+	}
+
+	return "[" + strings.Join(s, " ") + "]"
+}
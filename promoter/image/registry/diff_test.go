@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+)
+
+func TestRegInvImageDiff(t *testing.T) {
+	left := registry.RegInvImage{
+		"foo": {digestA: {"v1.0", "latest"}},
+		"bar": {digestB: {"v2.0"}},
+	}
+	right := registry.RegInvImage{
+		"foo": {digestA: {"v1.0", "v1.1"}, digestB: {}},
+	}
+
+	diff := left.Diff(right)
+	require.False(t, diff.Empty())
+	require.Equal(t, []registry.DigestDiff{{Name: "foo", Digest: digestB, Tags: registry.TagSlice{}}}, diff.Added)
+	require.Equal(t, []registry.DigestDiff{{Name: "bar", Digest: digestB, Tags: registry.TagSlice{"v2.0"}}}, diff.Removed)
+	require.Equal(t, []registry.DigestDiff{{
+		Name:        "foo",
+		Digest:      digestA,
+		AddedTags:   registry.TagSlice{"v1.1"},
+		RemovedTags: registry.TagSlice{"latest"},
+	}}, diff.Retagged)
+
+	require.Equal(t, "+ foo@"+digestB+" []\n"+
+		"- bar@"+digestB+" [v2.0]\n"+
+		"~ foo@"+digestA+" +[v1.1] -[latest]\n", diff.ToText())
+
+	out, err := diff.ToJSON()
+	require.NoError(t, err)
+
+	decoded := &registry.InventoryDiff{}
+	require.NoError(t, json.Unmarshal([]byte(out), decoded))
+	require.Len(t, decoded.Retagged, 1)
+
+	require.True(t, left.Diff(left).Empty())
+}
@@ -18,13 +18,27 @@ package registry
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
 
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
 // FakeProvider is an in-memory implementation of Provider for testing.
+// Errors can be injected globally, per call or per reference, where a
+// reference matches an exact reference or any reference of the registry or
+// repository it names, e.g. "gcr.io/foo" matches "gcr.io/foo/bar:v1.0".
 type FakeProvider struct {
 	mu sync.Mutex
 
@@ -39,6 +53,42 @@ type FakeProvider struct {
 
 	// CopyImageErr forces CopyImage to return this error.
 	CopyImageErr error
+
+	// CopyImageErrs forces CopyImage to return an error for the
+	// destinations matching a reference.
+	CopyImageErrs map[string]error
+
+	// CopyImageErrOnCall forces the Nth CopyImage call, counting from 0,
+	// to return an error.
+	CopyImageErrOnCall map[int]error
+
+	// DeletedRefs records the references passed to DeleteTag and
+	// DeleteManifest, in call order.
+	DeletedRefs []string
+
+	// DeleteErr forces DeleteTag and DeleteManifest to return this error.
+	DeleteErr error
+
+	// DeleteErrs forces DeleteTag and DeleteManifest to return an error for
+	// the references matching a reference.
+	DeleteErrs map[string]error
+
+	// GetManifestErrs forces GetManifest to return an error for the
+	// references matching a reference.
+	GetManifestErrs map[string]error
+
+	// Latency delays every operation, which fails early if its context is
+	// canceled meanwhile.
+	Latency time.Duration
+
+	// Calls records every operation, in call order.
+	Calls []Call
+
+	// Manifests maps digests to the raw manifests returned by GetManifest.
+	Manifests map[image.Digest][]byte
+
+	// Referrers maps digests to the descriptors returned by ListReferrers.
+	Referrers map[image.Digest][]v1.Descriptor
 }
 
 // CopyRecord records the arguments to a CopyImage call.
@@ -46,14 +96,32 @@ type CopyRecord struct {
 	Src, Dst string
 }
 
+// Call records an operation of a FakeProvider and its reference
+// arguments.
+type Call struct {
+	Method string
+	Args   []string
+}
+
+// IndexChild is a child manifest of an image index added with AddIndex.
+type IndexChild struct {
+	Digest image.Digest
+
+	// Platform is the platform of the child, e.g. "linux/arm64/v8".
+	Platform string
+}
+
 // NewFakeProvider creates a FakeProvider with an empty inventory.
 func NewFakeProvider() *FakeProvider {
 	return &FakeProvider{
 		Inventory: NewInventory(),
+		Manifests: make(map[image.Digest][]byte),
+		Referrers: make(map[image.Digest][]v1.Descriptor),
 	}
 }
 
-// AddImage adds an image to the fake inventory.
+// AddImage adds an image to the fake inventory. Its media type defaults to
+// a Docker image manifest unless set with AddManifest.
 func (f *FakeProvider) AddImage(
 	reg image.Registry, name image.Name,
 	digest image.Digest, tags ...image.Tag,
@@ -61,6 +129,65 @@ func (f *FakeProvider) AddImage(
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	f.addImage(reg, name, digest, cr.DockerManifestSchema2, tags)
+}
+
+// AddIndex adds an image index and its children to the fake inventory. The
+// children are added untagged next to the index, as registries list them,
+// and GetManifest returns an index manifest listing them. It panics on
+// invalid child platforms.
+func (f *FakeProvider) AddIndex(
+	reg image.Registry, name image.Name,
+	digest image.Digest, children []IndexChild, tags ...image.Tag,
+) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	idx := v1.IndexManifest{
+		SchemaVersion: 2,
+		MediaType:     cr.OCIImageIndex,
+		Manifests:     []v1.Descriptor{},
+	}
+
+	for _, child := range children {
+		algorithm, hex, _ := strings.Cut(string(child.Digest), ":")
+		desc := v1.Descriptor{
+			MediaType: cr.OCIManifestSchema1,
+			Digest:    v1.Hash{Algorithm: algorithm, Hex: hex},
+		}
+
+		if child.Platform != "" {
+			platform, err := v1.ParsePlatform(child.Platform)
+			if err != nil {
+				panic(fmt.Sprintf("fake index %s: %v", digest, err))
+			}
+
+			desc.Platform = platform
+		}
+
+		idx.Manifests = append(idx.Manifests, desc)
+
+		if _, ok := f.Inventory.Images[reg][name][child.Digest]; !ok {
+			f.addImage(reg, name, child.Digest, cr.OCIManifestSchema1, nil)
+		}
+	}
+
+	raw, err := json.Marshal(idx)
+	if err != nil {
+		panic(fmt.Sprintf("fake index %s: %v", digest, err))
+	}
+
+	f.addImage(reg, name, digest, cr.OCIImageIndex, tags)
+	f.Inventory.MediaTypes[digest] = cr.OCIImageIndex
+	f.Manifests[digest] = raw
+}
+
+// addImage adds an image to the inventory, setting its media type if it
+// is not known yet. f.mu must be held.
+func (f *FakeProvider) addImage(
+	reg image.Registry, name image.Name,
+	digest image.Digest, mediaType cr.MediaType, tags []image.Tag,
+) {
 	if _, ok := f.Inventory.Images[reg]; !ok {
 		f.Inventory.Images[reg] = make(RegInvImage)
 	}
@@ -70,12 +197,37 @@ func (f *FakeProvider) AddImage(
 	}
 
 	f.Inventory.Images[reg][name][digest] = tags
+
+	if _, ok := f.Inventory.MediaTypes[digest]; !ok {
+		f.Inventory.MediaTypes[digest] = mediaType
+	}
+}
+
+// AddManifest sets the raw manifest and media type of a digest.
+func (f *FakeProvider) AddManifest(digest image.Digest, mediaType cr.MediaType, raw []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Manifests[digest] = raw
+	f.Inventory.MediaTypes[digest] = mediaType
+}
+
+// AddReferrer attaches a referrer to a subject digest.
+func (f *FakeProvider) AddReferrer(subject image.Digest, desc v1.Descriptor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Referrers[subject] = append(f.Referrers[subject], desc)
 }
 
 // ReadRegistries returns the pre-populated inventory.
 func (f *FakeProvider) ReadRegistries(
-	_ context.Context, _ []RegistryConfig, _ bool, _ []RegistryConfig,
+	ctx context.Context, _ []RegistryConfig, _ bool, _ []RegistryConfig,
 ) (*Inventory, error) {
+	if err := f.call(ctx, "ReadRegistries"); err != nil {
+		return nil, err
+	}
+
 	if f.ReadRegistriesErr != nil {
 		return nil, f.ReadRegistriesErr
 	}
@@ -84,14 +236,248 @@ func (f *FakeProvider) ReadRegistries(
 }
 
 // CopyImage records the copy and returns the configured error.
-func (f *FakeProvider) CopyImage(_ context.Context, src, dst string) error {
+func (f *FakeProvider) CopyImage(ctx context.Context, src, dst string) error {
+	if err := f.call(ctx, "CopyImage", src, dst); err != nil {
+		return err
+	}
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
+	n := len(f.CopiedImages)
+
 	f.CopiedImages = append(f.CopiedImages, CopyRecord{Src: src, Dst: dst})
 	if f.CopyImageErr != nil {
 		return fmt.Errorf("fake copy error: %w", f.CopyImageErr)
 	}
 
+	if err, ok := f.CopyImageErrOnCall[n]; ok {
+		return fmt.Errorf("fake copy error: %w", err)
+	}
+
+	if err := matchRef(f.CopyImageErrs, dst); err != nil {
+		return fmt.Errorf("fake copy error: %w", err)
+	}
+
 	return nil
 }
+
+// DeleteTag records the deletion and returns the configured error.
+func (f *FakeProvider) DeleteTag(ctx context.Context, ref string) error {
+	return f.recordDelete(ctx, "DeleteTag", ref)
+}
+
+// DeleteManifest records the deletion and returns the configured error.
+func (f *FakeProvider) DeleteManifest(ctx context.Context, ref string) error {
+	return f.recordDelete(ctx, "DeleteManifest", ref)
+}
+
+func (f *FakeProvider) recordDelete(ctx context.Context, method, ref string) error {
+	if err := f.call(ctx, method, ref); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.DeletedRefs = append(f.DeletedRefs, ref)
+	if f.DeleteErr != nil {
+		return fmt.Errorf("fake delete error: %w", f.DeleteErr)
+	}
+
+	if err := matchRef(f.DeleteErrs, ref); err != nil {
+		return fmt.Errorf("fake delete error: %w", err)
+	}
+
+	return nil
+}
+
+// GetManifest returns the manifest added with AddManifest or AddIndex for
+// the digest the reference resolves to.
+func (f *FakeProvider) GetManifest(ctx context.Context, ref string) ([]byte, cr.MediaType, error) {
+	if err := f.call(ctx, "GetManifest", ref); err != nil {
+		return nil, "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := matchRef(f.GetManifestErrs, ref); err != nil {
+		return nil, "", fmt.Errorf("fake manifest error: %w", err)
+	}
+
+	digest, err := f.resolve(ref)
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw, ok := f.Manifests[digest]
+	if !ok {
+		return nil, "", notFound("manifest " + ref)
+	}
+
+	return raw, f.Inventory.MediaTypes[digest], nil
+}
+
+// HeadDigest resolves a reference with the inventory.
+func (f *FakeProvider) HeadDigest(ctx context.Context, ref string) (image.Digest, error) {
+	if err := f.call(ctx, "HeadDigest", ref); err != nil {
+		return "", err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.resolve(ref)
+}
+
+// ListReferrers returns the referrers added with AddReferrer.
+func (f *FakeProvider) ListReferrers(ctx context.Context, ref, artifactType string) ([]v1.Descriptor, error) {
+	if err := f.call(ctx, "ListReferrers", ref); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	digest, err := name.NewDigest(ref)
+	if err != nil {
+		return nil, fmt.Errorf("parsing digest reference %s: %w", ref, err)
+	}
+
+	referrers := []v1.Descriptor{}
+
+	for _, desc := range f.Referrers[image.Digest(digest.DigestStr())] {
+		if artifactType == "" || desc.ArtifactType == artifactType {
+			referrers = append(referrers, desc)
+		}
+	}
+
+	return referrers, nil
+}
+
+// ListTags returns the sorted tags of a repository of the inventory.
+func (f *FakeProvider) ListTags(ctx context.Context, repo string) ([]image.Tag, error) {
+	if err := f.call(ctx, "ListTags", repo); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dmap, ok := f.repository(repo)
+	if !ok {
+		return nil, notFound("repository " + repo)
+	}
+
+	tags := []image.Tag{}
+	for _, digestTags := range dmap {
+		tags = append(tags, digestTags...)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	return tags, nil
+}
+
+// CallsTo returns the arguments of the calls to a method, in call order.
+func (f *FakeProvider) CallsTo(method string) [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	args := [][]string{}
+
+	for _, c := range f.Calls {
+		if c.Method == method {
+			args = append(args, c.Args)
+		}
+	}
+
+	return args
+}
+
+// call records an operation and waits for the configured latency.
+func (f *FakeProvider) call(ctx context.Context, method string, args ...string) error {
+	f.mu.Lock()
+	f.Calls = append(f.Calls, Call{Method: method, Args: args})
+	latency := f.Latency
+	f.mu.Unlock()
+
+	if latency == 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("fake %s: %w", method, ctx.Err())
+	case <-time.After(latency):
+		return nil
+	}
+}
+
+// matchRef returns the error of the longest key of errs matching a
+// reference: the reference itself, or a registry or repository it belongs
+// to.
+func matchRef(errs map[string]error, ref string) error {
+	var (
+		match string
+		err   error
+	)
+
+	for key, keyErr := range errs {
+		rest, ok := strings.CutPrefix(ref, key)
+		if !ok || len(key) <= len(match) {
+			continue
+		}
+
+		if rest == "" || strings.ContainsRune("/:@", rune(rest[0])) {
+			match, err = key, keyErr
+		}
+	}
+
+	return err
+}
+
+// resolve returns the digest of a reference, looking tags up in the
+// inventory. f.mu must be held.
+func (f *FakeProvider) resolve(ref string) (image.Digest, error) {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return "", fmt.Errorf("parsing reference %s: %w", ref, err)
+	}
+
+	if digest, ok := r.(name.Digest); ok {
+		return image.Digest(digest.DigestStr()), nil
+	}
+
+	dmap, _ := f.repository(r.Context().Name())
+	for digest, tags := range dmap {
+		for _, tag := range tags {
+			if string(tag) == r.Identifier() {
+				return digest, nil
+			}
+		}
+	}
+
+	return "", notFound("tag " + ref)
+}
+
+// repository returns the digests of a repository of the inventory. f.mu
+// must be held.
+func (f *FakeProvider) repository(repo string) (DigestTags, bool) {
+	for reg, rii := range f.Inventory.Images {
+		for imageName, dmap := range rii {
+			if path.Join(string(reg), string(imageName)) == repo {
+				return dmap, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// notFound returns the error a registry returns for a missing object, so
+// callers checking for it behave as with a real registry.
+func notFound(what string) error {
+	return fmt.Errorf("fake %s: %w", what, &transport.Error{StatusCode: http.StatusNotFound})
+}
@@ -0,0 +1,650 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// LayoutScheme prefixes the registry names and references of OCI image
+// layouts, e.g. "oci-layout:///path/to/registry".
+const LayoutScheme = "oci-layout://"
+
+// annotationRefName is the index.json annotation holding the tag of a
+// manifest in an OCI image layout.
+const annotationRefName = "org.opencontainers.image.ref.name"
+
+// IsLayout returns true if a registry name or reference points to an OCI
+// image layout.
+func IsLayout(ref string) bool {
+	return strings.HasPrefix(ref, LayoutScheme)
+}
+
+// LayoutProvider implements Provider for OCI image layouts on disk. A
+// registry named "oci-layout:///path" holds every image in a layout of its
+// own directory, e.g. the image "foo/bar" is stored in the layout at
+// /path/foo/bar. The tags of an image are the ref.name annotations of the
+// manifests listed in its index.json.
+type LayoutProvider struct {
+	// mu serializes the updates of index.json files.
+	mu sync.Mutex
+}
+
+var _ Provider = &LayoutProvider{}
+
+// NewLayoutProvider creates a new LayoutProvider.
+func NewLayoutProvider() *LayoutProvider {
+	return &LayoutProvider{}
+}
+
+// artifact is an image or an image index to copy.
+type artifact struct {
+	image v1.Image
+	index v1.ImageIndex
+}
+
+// layoutRef is a parsed reference to a manifest of an OCI image layout.
+type layoutRef struct {
+	// path is the directory of the layout.
+	path string
+
+	// tag is the tag of the reference, if any.
+	tag string
+
+	// digest is the digest of the reference, if any.
+	digest string
+}
+
+// parseLayoutRef parses an "oci-layout://<path>[:tag|@digest]" reference.
+func parseLayoutRef(ref string) (layoutRef, error) {
+	if !IsLayout(ref) {
+		return layoutRef{}, fmt.Errorf("%s is not an OCI image layout reference", ref)
+	}
+
+	r := layoutRef{path: strings.TrimPrefix(ref, LayoutScheme)}
+
+	if before, after, found := strings.Cut(r.path, "@"); found {
+		r.path, r.digest = before, after
+	} else if i := strings.LastIndex(r.path, ":"); i > strings.LastIndex(r.path, "/") {
+		r.path, r.tag = r.path[:i], r.path[i+1:]
+	}
+
+	if r.path == "" {
+		return layoutRef{}, fmt.Errorf("missing path in OCI image layout reference %s", ref)
+	}
+
+	return r, nil
+}
+
+// ReadRegistries reads the images of OCI image layouts. When recursing,
+// every layout nested in the directory of a registry is read. Registries
+// whose directory does not exist have no images.
+func (p *LayoutProvider) ReadRegistries(
+	_ context.Context, registries []RegistryConfig, recurse bool, baseRegistries []RegistryConfig,
+) (*Inventory, error) {
+	inv := NewInventory()
+
+	var mu sync.Mutex
+
+	splitRegs := baseRegistries
+	if len(splitRegs) == 0 {
+		splitRegs = registries
+	}
+
+	for _, r := range registries {
+		if err := p.readRegistry(r, recurse, inv, &mu, splitRegs); err != nil {
+			return nil, fmt.Errorf("reading registry %s: %w", r.Name, err)
+		}
+	}
+
+	return inv, nil
+}
+
+// readRegistry records the images of the layouts of a registry in the
+// inventory.
+func (p *LayoutProvider) readRegistry(
+	r RegistryConfig,
+	recurse bool,
+	inv *Inventory,
+	mu *sync.Mutex,
+	splitRegs []RegistryConfig,
+) error {
+	root := strings.TrimPrefix(string(r.Name), LayoutScheme)
+
+	dirs := []string{root}
+	if recurse {
+		dirs = []string{}
+
+		if err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case !d.IsDir():
+			case d.Name() == "blobs":
+				return filepath.SkipDir
+			default:
+				dirs = append(dirs, path)
+			}
+
+			return nil
+		}); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("walking %s: %w", root, err)
+		}
+	}
+
+	for _, dir := range dirs {
+		index, err := readIndex(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			return fmt.Errorf("finding the image of %s: %w", dir, err)
+		}
+
+		fullName := r.Name
+		if rel != "." {
+			fullName = image.Registry(string(r.Name) + "/" + filepath.ToSlash(rel))
+		}
+
+		recordLayout(fullName, index, inv, mu, splitRegs)
+	}
+
+	return nil
+}
+
+// recordLayout records the manifests listed in the index.json of a layout
+// in the inventory.
+func recordLayout(
+	fullName image.Registry, index *v1.IndexManifest, inv *Inventory, mu *sync.Mutex, splitRegs []RegistryConfig,
+) {
+	regName, imageName, err := splitByKnownRegistries(fullName, splitRegs)
+	if err != nil {
+		logrus.Warnf("Skipping layout %s: %v", fullName, err)
+
+		return
+	}
+
+	digestTags := make(DigestTags)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	for i := range index.Manifests {
+		desc := &index.Manifests[i]
+		digest := image.Digest(desc.Digest.String())
+
+		if _, ok := digestTags[digest]; !ok {
+			digestTags[digest] = TagSlice{}
+		}
+
+		if tag := desc.Annotations[annotationRefName]; tag != "" {
+			digestTags[digest] = append(digestTags[digest], image.Tag(tag))
+		}
+
+		mediaType, err := supportedMediaType(string(desc.MediaType))
+		if err != nil {
+			logrus.Errorf("Processing digest %s: %v", digest, err)
+		}
+
+		inv.MediaTypes[digest] = mediaType
+		inv.Details[digest] = ManifestDetails{MediaType: desc.MediaType, Size: desc.Size}
+	}
+
+	logrus.Debugf("Layout: %s Image: %s Got: %d digests", regName, imageName, len(digestTags))
+
+	if _, ok := inv.Images[regName]; !ok {
+		inv.Images[regName] = make(RegInvImage)
+	}
+
+	if len(digestTags) > 0 {
+		inv.Images[regName][imageName] = digestTags
+	}
+}
+
+// CopyImage copies an image or image index between OCI image layouts.
+func (p *LayoutProvider) CopyImage(_ context.Context, src, dst string) error {
+	a, err := p.read(src)
+	if err != nil {
+		return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
+	}
+
+	if err := p.write(dst, a); err != nil {
+		return fmt.Errorf("copying image %s to %s: %w", src, dst, err)
+	}
+
+	return nil
+}
+
+// DeleteTag removes the tag annotation of a manifest. The manifest stays in
+// the layout untagged.
+func (p *LayoutProvider) DeleteTag(_ context.Context, ref string) error {
+	r, err := parseLayoutRef(ref)
+	if err != nil {
+		return err
+	}
+
+	if r.tag == "" {
+		return fmt.Errorf("deleting tag %s: not a tag reference", ref)
+	}
+
+	if err := p.updateIndex(r.path, func(index *v1.IndexManifest) error {
+		i := slices.IndexFunc(index.Manifests, func(desc v1.Descriptor) bool {
+			return desc.Annotations[annotationRefName] == r.tag
+		})
+		if i < 0 {
+			return fmt.Errorf("tag %s: %w", r.tag, fs.ErrNotExist)
+		}
+
+		untagged := index.Manifests[i]
+		index.Manifests = slices.Delete(index.Manifests, i, i+1)
+
+		if !slices.ContainsFunc(index.Manifests, func(desc v1.Descriptor) bool {
+			return desc.Digest == untagged.Digest
+		}) {
+			delete(untagged.Annotations, annotationRefName)
+			index.Manifests = append(index.Manifests, untagged)
+		}
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("deleting tag %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// DeleteManifest removes a manifest and its tags from the index.json of a
+// layout, along with the blob of the manifest. The blobs of its config and
+// layers are left in place.
+func (p *LayoutProvider) DeleteManifest(_ context.Context, ref string) error {
+	r, err := parseLayoutRef(ref)
+	if err != nil {
+		return err
+	}
+
+	if r.digest == "" {
+		return fmt.Errorf("deleting manifest %s: not a digest reference", ref)
+	}
+
+	if err := p.updateIndex(r.path, func(index *v1.IndexManifest) error {
+		index.Manifests = slices.DeleteFunc(index.Manifests, func(desc v1.Descriptor) bool {
+			return desc.Digest.String() == r.digest
+		})
+
+		return nil
+	}); err != nil {
+		return fmt.Errorf("deleting manifest %s: %w", ref, err)
+	}
+
+	digest, err := v1.NewHash(r.digest)
+	if err != nil {
+		return fmt.Errorf("parsing digest %s: %w", r.digest, err)
+	}
+
+	if err := layout.Path(r.path).RemoveBlob(digest); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("deleting manifest %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// GetManifest reads the manifest of a reference from its layout.
+func (p *LayoutProvider) GetManifest(_ context.Context, ref string) ([]byte, cr.MediaType, error) {
+	r, desc, err := resolveLayoutRef(ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("getting manifest %s: %w", ref, err)
+	}
+
+	raw, err := layout.Path(r.path).Bytes(desc.Digest)
+	if err != nil {
+		return nil, "", fmt.Errorf("getting manifest %s: %w", ref, err)
+	}
+
+	return raw, desc.MediaType, nil
+}
+
+// HeadDigest resolves a reference to its digest in its layout.
+func (p *LayoutProvider) HeadDigest(_ context.Context, ref string) (image.Digest, error) {
+	_, desc, err := resolveLayoutRef(ref)
+	if err != nil {
+		return "", fmt.Errorf("getting digest of %s: %w", ref, err)
+	}
+
+	return image.Digest(desc.Digest.String()), nil
+}
+
+// ListReferrers returns the manifests of a layout whose subject is the
+// digest reference.
+func (p *LayoutProvider) ListReferrers(_ context.Context, ref, artifactType string) ([]v1.Descriptor, error) {
+	r, err := parseLayoutRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := readIndex(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("listing referrers of %s: %w", ref, err)
+	}
+
+	referrers := []v1.Descriptor{}
+
+	for _, desc := range index.Manifests {
+		raw, err := layout.Path(r.path).Bytes(desc.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("listing referrers of %s: %w", ref, err)
+		}
+
+		var m struct {
+			ArtifactType string         `json:"artifactType"`
+			Config       v1.Descriptor  `json:"config"`
+			Subject      *v1.Descriptor `json:"subject"`
+		}
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil, fmt.Errorf("parsing manifest %s: %w", desc.Digest, err)
+		}
+
+		if m.Subject == nil || m.Subject.Digest.String() != r.digest {
+			continue
+		}
+
+		desc.ArtifactType = m.ArtifactType
+		if desc.ArtifactType == "" {
+			desc.ArtifactType = string(m.Config.MediaType)
+		}
+
+		if artifactType != "" && desc.ArtifactType != artifactType {
+			continue
+		}
+
+		desc.Annotations = nil
+
+		if !slices.ContainsFunc(referrers, func(d v1.Descriptor) bool { return d.Digest == desc.Digest }) {
+			referrers = append(referrers, desc)
+		}
+	}
+
+	return referrers, nil
+}
+
+// ListTags returns the tags of a layout.
+func (p *LayoutProvider) ListTags(_ context.Context, repo string) ([]image.Tag, error) {
+	r, err := parseLayoutRef(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := readIndex(r.path)
+	if err != nil {
+		return nil, fmt.Errorf("listing tags of %s: %w", repo, err)
+	}
+
+	tags := []image.Tag{}
+
+	for _, desc := range index.Manifests {
+		if tag := desc.Annotations[annotationRefName]; tag != "" {
+			tags = append(tags, image.Tag(tag))
+		}
+	}
+
+	return tags, nil
+}
+
+// read returns the image or image index of a layout reference. Children of
+// image indexes can be read by digest.
+func (p *LayoutProvider) read(ref string) (artifact, error) {
+	r, desc, err := resolveLayoutRef(ref)
+	if err != nil {
+		return artifact{}, err
+	}
+
+	root, err := layout.Path(r.path).ImageIndex()
+	if err != nil {
+		return artifact{}, fmt.Errorf("reading layout %s: %w", r.path, err)
+	}
+
+	a, found, err := findArtifact(root, desc.Digest)
+	if err != nil {
+		return artifact{}, fmt.Errorf("reading %s: %w", ref, err)
+	}
+
+	if !found {
+		return artifact{}, fmt.Errorf("reading %s: %w", ref, fs.ErrNotExist)
+	}
+
+	return a, nil
+}
+
+// findArtifact looks up a digest in an image index and its children.
+func findArtifact(idx v1.ImageIndex, digest v1.Hash) (artifact, bool, error) {
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return artifact{}, false, fmt.Errorf("reading index: %w", err)
+	}
+
+	for _, desc := range manifest.Manifests {
+		if !desc.MediaType.IsIndex() {
+			if desc.Digest != digest {
+				continue
+			}
+
+			img, err := idx.Image(digest)
+			if err != nil {
+				return artifact{}, false, fmt.Errorf("reading image %s: %w", digest, err)
+			}
+
+			return artifact{image: img}, true, nil
+		}
+
+		child, err := idx.ImageIndex(desc.Digest)
+		if err != nil {
+			return artifact{}, false, fmt.Errorf("reading index %s: %w", desc.Digest, err)
+		}
+
+		if desc.Digest == digest {
+			return artifact{index: child}, true, nil
+		}
+
+		if a, found, err := findArtifact(child, digest); err != nil || found {
+			return a, found, err
+		}
+	}
+
+	return artifact{}, false, nil
+}
+
+// write stores an image or image index in the layout of a reference,
+// creating the layout if needed. Tag references move the tag to the
+// written manifest.
+func (p *LayoutProvider) write(ref string, a artifact) error {
+	r, err := parseLayoutRef(ref)
+	if err != nil {
+		return err
+	}
+
+	path := layout.Path(r.path)
+
+	p.mu.Lock()
+	_, err = readIndex(r.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		_, err = layout.Write(r.path, empty.Index)
+	}
+	p.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("creating layout %s: %w", r.path, err)
+	}
+
+	var desc *v1.Descriptor
+
+	if a.index != nil {
+		if err := path.WriteIndex(a.index); err != nil {
+			return fmt.Errorf("writing index: %w", err)
+		}
+
+		desc, err = partial.Descriptor(a.index)
+	} else {
+		if err := path.WriteImage(a.image); err != nil {
+			return fmt.Errorf("writing image: %w", err)
+		}
+
+		desc, err = partial.Descriptor(a.image)
+	}
+
+	if err != nil {
+		return fmt.Errorf("describing manifest: %w", err)
+	}
+
+	if r.digest != "" && desc.Digest.String() != r.digest {
+		return fmt.Errorf("manifest digest %s does not match %s", desc.Digest, r.digest)
+	}
+
+	desc.Annotations = nil
+	desc.Platform = nil
+
+	return p.updateIndex(r.path, func(index *v1.IndexManifest) error {
+		if r.tag == "" {
+			if !slices.ContainsFunc(index.Manifests, func(d v1.Descriptor) bool { return d.Digest == desc.Digest }) {
+				index.Manifests = append(index.Manifests, *desc)
+			}
+
+			return nil
+		}
+
+		index.Manifests = slices.DeleteFunc(index.Manifests, func(d v1.Descriptor) bool {
+			tag := d.Annotations[annotationRefName]
+
+			return tag == r.tag || (tag == "" && d.Digest == desc.Digest)
+		})
+
+		desc.Annotations = map[string]string{annotationRefName: r.tag}
+		index.Manifests = append(index.Manifests, *desc)
+
+		return nil
+	})
+}
+
+// updateIndex applies a change to the index.json of a layout.
+func (p *LayoutProvider) updateIndex(dir string, update func(*v1.IndexManifest) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index, err := readIndex(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := update(index); err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(index, "", "   ")
+	if err != nil {
+		return fmt.Errorf("marshaling index.json: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "index.json"), raw, 0o644); err != nil { //nolint:gosec // layouts are shared like registries
+		return fmt.Errorf("writing index.json: %w", err)
+	}
+
+	return nil
+}
+
+// readIndex reads the index.json of a layout.
+func readIndex(dir string) (*v1.IndexManifest, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading layout %s: %w", dir, err)
+	}
+
+	index := &v1.IndexManifest{}
+	if err := json.Unmarshal(raw, index); err != nil {
+		return nil, fmt.Errorf("parsing index.json of %s: %w", dir, err)
+	}
+
+	return index, nil
+}
+
+// resolveLayoutRef returns the descriptor of the manifest a layout
+// reference points to. Digests of index children, which are not listed in
+// index.json, resolve to a descriptor read from their blob.
+func resolveLayoutRef(ref string) (layoutRef, v1.Descriptor, error) {
+	r, err := parseLayoutRef(ref)
+	if err != nil {
+		return layoutRef{}, v1.Descriptor{}, err
+	}
+
+	index, err := readIndex(r.path)
+	if err != nil {
+		return layoutRef{}, v1.Descriptor{}, err
+	}
+
+	for _, desc := range index.Manifests {
+		if (r.tag != "" && desc.Annotations[annotationRefName] == r.tag) ||
+			(r.digest != "" && desc.Digest.String() == r.digest) {
+			return r, desc, nil
+		}
+	}
+
+	if r.digest == "" {
+		return layoutRef{}, v1.Descriptor{}, fmt.Errorf("%s: %w", ref, fs.ErrNotExist)
+	}
+
+	digest, err := v1.NewHash(r.digest)
+	if err != nil {
+		return layoutRef{}, v1.Descriptor{}, fmt.Errorf("parsing digest %s: %w", r.digest, err)
+	}
+
+	raw, err := layout.Path(r.path).Bytes(digest)
+	if err != nil {
+		return layoutRef{}, v1.Descriptor{}, fmt.Errorf("%s: %w", ref, err)
+	}
+
+	var m struct {
+		MediaType cr.MediaType `json:"mediaType"`
+	}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return layoutRef{}, v1.Descriptor{}, fmt.Errorf("parsing manifest %s: %w", digest, err)
+	}
+
+	return r, v1.Descriptor{MediaType: m.MediaType, Digest: digest, Size: int64(len(raw))}, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+func TestParseLayoutRef(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		ref      string
+		expected layoutRef
+		err      bool
+	}{
+		{ref: "oci-layout:///tmp/reg/foo", expected: layoutRef{path: "/tmp/reg/foo"}},
+		{ref: "oci-layout:///tmp/reg/foo:v1.0", expected: layoutRef{path: "/tmp/reg/foo", tag: "v1.0"}},
+		{ref: "oci-layout:///tmp/reg:5000/foo", expected: layoutRef{path: "/tmp/reg:5000/foo"}},
+		{
+			ref:      "oci-layout://reg/foo@sha256:abc",
+			expected: layoutRef{path: "reg/foo", digest: "sha256:abc"},
+		},
+		{ref: "oci-layout://", err: true},
+		{ref: "gcr.io/foo:v1.0", err: true},
+	} {
+		r, err := parseLayoutRef(tc.ref)
+		if tc.err {
+			require.Error(t, err, tc.ref)
+
+			continue
+		}
+
+		require.NoError(t, err, tc.ref)
+		require.Equal(t, tc.expected, r, tc.ref)
+	}
+}
+
+func TestCraneProviderLayout(t *testing.T) {
+	t.Parallel()
+
+	host := newTestRegistry(t)
+	ctx := context.Background()
+	p := newInsecureCraneProvider()
+	reg := LayoutScheme + t.TempDir()
+
+	digestA := pushRandomImage(t, host+"/staging/foo:v1.0")
+
+	idx, err := random.Index(1024, 1, 2)
+	require.NoError(t, err)
+
+	idxRef, err := name.ParseReference(host+"/staging/nested/bar:v2.0", name.Insecure)
+	require.NoError(t, err)
+	require.NoError(t, remote.WriteIndex(idxRef, idx, remote.WithTransport(http.DefaultTransport)))
+
+	idxDigest, err := idx.Digest()
+	require.NoError(t, err)
+
+	idxManifest, err := idx.IndexManifest()
+	require.NoError(t, err)
+
+	childDigest := idxManifest.Manifests[0].Digest.String()
+
+	// Registries are copied into layouts by tag and by digest
+	require.NoError(t, p.CopyImage(ctx, host+"/staging/foo:v1.0", reg+"/foo:v1.0"))
+	require.NoError(t, p.CopyImage(ctx, host+"/staging/foo@"+digestA, reg+"/foo:latest"))
+	require.NoError(t, p.CopyImage(ctx, host+"/staging/nested/bar@"+idxDigest.String(), reg+"/nested/bar@"+idxDigest.String()))
+
+	// Copies are idempotent
+	require.NoError(t, p.CopyImage(ctx, host+"/staging/foo:v1.0", reg+"/foo:v1.0"))
+
+	inv, err := p.ReadRegistries(ctx, []RegistryConfig{{Name: image.Registry(reg)}}, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, RegInvImage{
+		"foo":        {image.Digest(digestA): {"latest", "v1.0"}},
+		"nested/bar": {image.Digest(idxDigest.String()): {}},
+	}, sortedTags(inv.Images[image.Registry(reg)]))
+	require.Equal(t, cr.OCIImageIndex, inv.MediaTypes[image.Digest(idxDigest.String())])
+
+	// Missing layouts have no images
+	inv, err = p.ReadRegistries(ctx, []RegistryConfig{{Name: image.Registry(reg + "/missing")}}, false,
+		[]RegistryConfig{{Name: image.Registry(reg)}})
+	require.NoError(t, err)
+	require.Empty(t, inv.Images[image.Registry(reg)])
+
+	digest, err := p.HeadDigest(ctx, reg+"/foo:latest")
+	require.NoError(t, err)
+	require.EqualValues(t, digestA, digest)
+
+	raw, mediaType, err := p.GetManifest(ctx, reg+"/nested/bar@"+childDigest)
+	require.NoError(t, err)
+	require.NotEmpty(t, raw)
+	require.Equal(t, cr.DockerManifestSchema2, mediaType)
+
+	tags, err := p.ListTags(ctx, reg+"/foo")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []image.Tag{"v1.0", "latest"}, tags)
+
+	// Layouts are copied back into registries with their digests
+	require.NoError(t, p.CopyImage(ctx, reg+"/nested/bar@"+idxDigest.String(), host+"/production/bar:v2.0"))
+	require.NoError(t, p.CopyImage(ctx, reg+"/nested/bar@"+childDigest, host+"/production/bar@"+childDigest))
+	require.NoError(t, p.CopyImage(ctx, reg+"/foo:v1.0", reg+"/copy:v1.0"))
+
+	prodDigest, err := crane.Digest(host+"/production/bar:v2.0", crane.Insecure)
+	require.NoError(t, err)
+	require.Equal(t, idxDigest.String(), prodDigest)
+
+	copyDigest, err := p.HeadDigest(ctx, reg+"/copy:v1.0")
+	require.NoError(t, err)
+	require.EqualValues(t, digestA, copyDigest)
+
+	require.Error(t, p.CopyImage(ctx, reg+"/foo:missing", reg+"/copy:v1.1"))
+
+	// Tags move and are deleted without deleting their manifest
+	digestB := pushRandomImage(t, host+"/staging/foo:v1.1")
+	require.NoError(t, p.CopyImage(ctx, host+"/staging/foo:v1.1", reg+"/foo:latest"))
+	require.NoError(t, p.DeleteTag(ctx, reg+"/foo:v1.0"))
+
+	inv, err = p.ReadRegistries(ctx, []RegistryConfig{{Name: image.Registry(reg + "/foo")}}, false,
+		[]RegistryConfig{{Name: image.Registry(reg)}})
+	require.NoError(t, err)
+	require.Equal(t, DigestTags{
+		image.Digest(digestA): {},
+		image.Digest(digestB): {"latest"},
+	}, inv.Images[image.Registry(reg)]["foo"])
+
+	require.NoError(t, p.DeleteManifest(ctx, reg+"/foo@"+digestA))
+
+	_, err = p.HeadDigest(ctx, reg+"/foo@"+digestA)
+	require.Error(t, err)
+	require.Error(t, p.DeleteTag(ctx, reg+"/foo:v1.0"))
+}
+
+func TestLayoutProviderReferrers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	p := NewLayoutProvider()
+	reg := LayoutScheme + t.TempDir()
+
+	img, err := random.Image(1024, 1)
+	require.NoError(t, err)
+
+	desc, err := partial.Descriptor(img)
+	require.NoError(t, err)
+
+	artifact, err := random.Image(128, 1)
+	require.NoError(t, err)
+
+	artifact = mutate.ConfigMediaType(
+		mutate.MediaType(artifact, cr.OCIManifestSchema1), "application/vnd.example+json",
+	)
+	artifact, ok := mutate.Subject(artifact, *desc).(v1.Image)
+	require.True(t, ok)
+
+	artifactDigest, err := artifact.Digest()
+	require.NoError(t, err)
+
+	require.NoError(t, p.write(reg+"/foo:v1.0", artifactImage(img)))
+	require.NoError(t, p.write(reg+"/foo@"+artifactDigest.String(), artifactImage(artifact)))
+
+	subject := reg + "/foo@" + desc.Digest.String()
+
+	referrers, err := p.ListReferrers(ctx, subject, "")
+	require.NoError(t, err)
+	require.Len(t, referrers, 1)
+	require.Equal(t, artifactDigest, referrers[0].Digest)
+	require.Equal(t, "application/vnd.example+json", referrers[0].ArtifactType)
+
+	referrers, err = p.ListReferrers(ctx, subject, "application/vnd.other+json")
+	require.NoError(t, err)
+	require.Empty(t, referrers)
+
+	// Digests must match the written manifest
+	require.Error(t, p.write(reg+"/foo@"+artifactDigest.String(), artifactImage(img)))
+}
+
+func artifactImage(img v1.Image) artifact {
+	return artifact{image: img}
+}
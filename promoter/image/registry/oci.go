@@ -0,0 +1,267 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/ratelimit"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// Registry APIs used to read the inventory of a registry.
+const (
+	// APIGoogle reads registries with the extensions Google Container
+	// Registry and Artifact Registry add to the tags/list endpoint, which
+	// list every manifest of a repository and its child repositories.
+	APIGoogle = "google"
+
+	// APIOCI reads registries with the OCI distribution API: repositories
+	// are listed with /v2/_catalog, tags with tags/list and every tag is
+	// resolved to its digest with a HEAD request. Untagged manifests are
+	// not listed by this API.
+	APIOCI = "oci"
+)
+
+// APIs lists the registry APIs that can be set in a manifest.
+var APIs = []string{APIGoogle, APIOCI}
+
+// OCIProvider implements Provider for any OCI conformant registry, like
+// Docker Hub, GHCR, Harbor, Zot or registry:2, by reading every registry
+// with the OCI distribution API regardless of its host.
+type OCIProvider struct {
+	*CraneProvider
+}
+
+// NewOCIProvider creates a new OCIProvider with the given options.
+func NewOCIProvider(opts ...CraneOption) *OCIProvider {
+	return &OCIProvider{CraneProvider: NewCraneProvider(opts...)}
+}
+
+// ReadRegistries reads the image inventory of the registries with the OCI
+// distribution API.
+func (p *OCIProvider) ReadRegistries(
+	ctx context.Context, registries []RegistryConfig, recurse bool, baseRegistries []RegistryConfig,
+) (*Inventory, error) {
+	ociRegistries := make([]RegistryConfig, len(registries))
+	for i, r := range registries {
+		r.API = APIOCI
+		ociRegistries[i] = r
+	}
+
+	return p.CraneProvider.ReadRegistries(ctx, ociRegistries, recurse, baseRegistries)
+}
+
+// registryAPI returns the API used to read a registry: the configured one,
+// or the Google API for Google Container Registry and Artifact Registry
+// hosts and the OCI distribution API for any other host.
+func registryAPI(r RegistryConfig) string {
+	if r.API != "" {
+		return r.API
+	}
+
+	host, _, _ := strings.Cut(string(r.Name), "/")
+	if host == "gcr.io" || strings.HasSuffix(host, ".gcr.io") || strings.HasSuffix(host, ".pkg.dev") {
+		return APIGoogle
+	}
+
+	return APIOCI
+}
+
+// readOCIRegistry records the repositories of a registry in the inventory
+// with the OCI distribution API. When recursing, the repositories nested in
+// the registry are found in the catalog of its host. Registries that do not
+// serve a catalog, like Docker Hub, only have the repository of their name
+// read.
+func (p *CraneProvider) readOCIRegistry(
+	ctx context.Context,
+	r RegistryConfig,
+	recurse bool,
+	inv *Inventory,
+	mu *sync.Mutex,
+	splitRegs []RegistryConfig,
+) error {
+	o := crane.GetOptions(p.options(ctx)...)
+
+	host, prefix, _ := strings.Cut(string(r.Name), "/")
+
+	reg, err := name.NewRegistry(host, o.Name...)
+	if err != nil {
+		return fmt.Errorf("parsing registry %s: %w", host, err)
+	}
+
+	repos := []string{}
+	if prefix != "" {
+		repos = append(repos, prefix)
+	}
+
+	if recurse {
+		var catalog []string
+
+		if err := ratelimit.WithRetry(func() error {
+			var catalogErr error
+
+			catalog, catalogErr = remote.Catalog(ctx, reg, o.Remote...)
+			if catalogErr != nil {
+				return fmt.Errorf("listing catalog: %w", catalogErr)
+			}
+
+			return nil
+		}); err != nil {
+			if prefix == "" {
+				return fmt.Errorf("listing repositories of %s: %w", host, err)
+			}
+
+			logrus.Warnf("Unable to list the repositories of %s, only reading %s: %v", host, r.Name, err)
+		}
+
+		for _, repo := range catalog {
+			if prefix == "" || strings.HasPrefix(repo, prefix+"/") {
+				repos = append(repos, repo)
+			}
+		}
+	}
+
+	for _, repo := range repos {
+		fullName := image.Registry(host + "/" + repo)
+		if err := p.readOCIRepository(ctx, o, reg.Repo(repo), fullName, inv, mu, splitRegs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readOCIRepository lists the tags of a repository, resolves them to their
+// digests and records them in the inventory. A missing repository has no
+// images.
+func (p *CraneProvider) readOCIRepository(
+	ctx context.Context,
+	o crane.Options,
+	repo name.Repository,
+	fullName image.Registry,
+	inv *Inventory,
+	mu *sync.Mutex,
+	splitRegs []RegistryConfig,
+) error {
+	regName, imageName, err := splitByKnownRegistries(fullName, splitRegs)
+	if err != nil {
+		return fmt.Errorf("splitting repo and image name: %w", err)
+	}
+
+	var tags []string
+
+	if err := ratelimit.WithRetry(func() error {
+		var listErr error
+
+		tags, listErr = remote.List(repo, o.Remote...)
+		if listErr != nil {
+			return fmt.Errorf("listing tags: %w", listErr)
+		}
+
+		return nil
+	}); err != nil {
+		var terr *transport.Error
+		if !errors.As(err, &terr) || terr.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("listing tags of %s: %w", fullName, err)
+		}
+
+		tags = nil
+	}
+
+	digestTags := make(DigestTags)
+	details := make(map[image.Digest]ManifestDetails)
+
+	var repoMu sync.Mutex
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(readRegistryConcurrency)
+
+	headOpts := append(slices.Clip(o.Remote), remote.WithContext(gctx))
+
+	for _, tag := range tags {
+		g.Go(func() error {
+			var desc *v1.Descriptor
+
+			if err := ratelimit.WithRetry(func() error {
+				var headErr error
+
+				desc, headErr = remote.Head(repo.Tag(tag), headOpts...)
+				if headErr != nil {
+					return fmt.Errorf("resolving tag: %w", headErr)
+				}
+
+				return nil
+			}); err != nil {
+				return fmt.Errorf("resolving %s:%s: %w", fullName, tag, err)
+			}
+
+			digest := image.Digest(desc.Digest.String())
+
+			repoMu.Lock()
+			defer repoMu.Unlock()
+
+			digestTags[digest] = append(digestTags[digest], image.Tag(tag))
+			details[digest] = ManifestDetails{MediaType: desc.MediaType, Size: desc.Size}
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err //nolint:wrapcheck // errors are wrapped in the goroutines
+	}
+
+	logrus.Debugf("Registry: %s Image: %s Got: %d digests", regName, imageName, len(digestTags))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := inv.Images[regName]; !ok {
+		inv.Images[regName] = make(RegInvImage)
+	}
+
+	if len(digestTags) > 0 {
+		inv.Images[regName][imageName] = digestTags
+	}
+
+	for digest, md := range details {
+		mediaType, err := supportedMediaType(string(md.MediaType))
+		if err != nil {
+			logrus.Errorf("Processing digest %s: %v", digest, err)
+		}
+
+		inv.MediaTypes[digest] = mediaType
+		inv.Details[digest] = md
+	}
+
+	return nil
+}
@@ -18,7 +18,9 @@ package registry
 
 import (
 	"context"
+	"time"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	cr "github.com/google/go-containerregistry/pkg/v1/types"
 
 	"sigs.k8s.io/promo-tools/v4/types/image"
@@ -40,6 +42,31 @@ type Provider interface {
 	// CopyImage copies a container image from the source reference to the
 	// destination reference. References can be by digest (FQIN) or tag (PQIN).
 	CopyImage(ctx context.Context, src, dst string) error
+
+	// DeleteTag removes a tag (PQIN) from a registry, leaving the manifest
+	// it points to in place where the registry supports it.
+	DeleteTag(ctx context.Context, ref string) error
+
+	// DeleteManifest deletes the manifest of a digest reference (FQIN).
+	// Registries may refuse to delete manifests that are still tagged or
+	// referenced by an image index.
+	DeleteManifest(ctx context.Context, ref string) error
+
+	// GetManifest returns the raw manifest of a reference, by digest or
+	// tag, along with its media type.
+	GetManifest(ctx context.Context, ref string) ([]byte, cr.MediaType, error)
+
+	// HeadDigest resolves a reference to the digest of its manifest
+	// without downloading the manifest.
+	HeadDigest(ctx context.Context, ref string) (image.Digest, error)
+
+	// ListReferrers returns the descriptors of the manifests whose subject
+	// is the digest reference (FQIN). When artifactType is not empty, only
+	// the referrers of that artifact type are returned.
+	ListReferrers(ctx context.Context, ref, artifactType string) ([]v1.Descriptor, error)
+
+	// ListTags returns the tags of a repository.
+	ListTags(ctx context.Context, repo string) ([]image.Tag, error)
 }
 
 // RegistryConfig describes a container image registry endpoint.
@@ -49,6 +76,10 @@ type RegistryConfig struct {
 
 	// Src marks this registry as a source (staging) registry.
 	Src bool
+
+	// API is the API used to read the registry, one of APIs. When empty,
+	// it is detected from the registry host.
+	API string
 }
 
 // RegistryConfigFromContext converts a legacy registry.Context to a RegistryConfig.
@@ -56,6 +87,7 @@ func RegistryConfigFromContext(rc Context) RegistryConfig {
 	return RegistryConfig{
 		Name: rc.Name,
 		Src:  rc.Src,
+		API:  rc.API,
 	}
 }
 
@@ -76,6 +108,29 @@ type Inventory struct {
 
 	// MediaTypes maps digests to their OCI media types.
 	MediaTypes map[image.Digest]cr.MediaType
+
+	// Details maps digests to the manifest metadata reported by the
+	// registry, when available.
+	Details map[image.Digest]ManifestDetails
+}
+
+// ManifestDetails holds metadata of an image manifest.
+type ManifestDetails struct {
+	// MediaType is the media type of the manifest.
+	MediaType cr.MediaType
+
+	// Size is the size of the image in bytes.
+	Size int64
+
+	// Created is the creation time of the image.
+	Created time.Time
+
+	// Uploaded is the time the manifest was pushed to the registry.
+	Uploaded time.Time
+
+	// Platforms lists the platforms of an image index, e.g.
+	// "linux/amd64".
+	Platforms []string
 }
 
 // NewInventory creates an empty Inventory.
@@ -83,5 +138,6 @@ func NewInventory() *Inventory {
 	return &Inventory{
 		Images:     make(map[image.Registry]RegInvImage),
 		MediaTypes: make(map[image.Digest]cr.MediaType),
+		Details:    make(map[image.Digest]ManifestDetails),
 	}
 }
@@ -17,9 +17,17 @@ limitations under the License.
 package registry
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"net/http"
+	"reflect"
 	"testing"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
 
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
@@ -77,6 +85,10 @@ func TestNewInventory(t *testing.T) {
 	if inv.MediaTypes == nil {
 		t.Error("MediaTypes map is nil")
 	}
+
+	if inv.Details == nil {
+		t.Error("Details map is nil")
+	}
 }
 
 func TestFakeProviderAddImage(t *testing.T) {
@@ -154,6 +166,198 @@ func TestFakeProviderCopyImageError(t *testing.T) {
 	}
 }
 
+func TestFakeProviderDelete(t *testing.T) {
+	f := NewFakeProvider()
+
+	if err := f.DeleteTag(context.Background(), "gcr.io/foo/bar:v1.0"); err != nil {
+		t.Fatalf("DeleteTag() error = %v", err)
+	}
+
+	if err := f.DeleteManifest(context.Background(), "gcr.io/foo/bar@sha256:abc"); err != nil {
+		t.Fatalf("DeleteManifest() error = %v", err)
+	}
+
+	want := []string{"gcr.io/foo/bar:v1.0", "gcr.io/foo/bar@sha256:abc"}
+	if len(f.DeletedRefs) != 2 || f.DeletedRefs[0] != want[0] || f.DeletedRefs[1] != want[1] {
+		t.Errorf("DeletedRefs = %v, want %v", f.DeletedRefs, want)
+	}
+
+	f.DeleteErr = errors.New("delete failed")
+	if err := f.DeleteManifest(context.Background(), "gcr.io/foo/bar@sha256:abc"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestFakeProviderManifests(t *testing.T) {
+	const (
+		digest   = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		referrer = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	)
+
+	ctx := context.Background()
+
+	f := NewFakeProvider()
+	f.AddImage("gcr.io/foo", "bar", digest, "v1.0", "latest")
+	f.AddManifest(digest, cr.OCIManifestSchema1, []byte(`{}`))
+	f.AddReferrer(digest, v1.Descriptor{
+		Digest:       v1.Hash{Algorithm: "sha256", Hex: referrer[len("sha256:"):]},
+		ArtifactType: "application/vnd.example+json",
+	})
+
+	got, err := f.HeadDigest(ctx, "gcr.io/foo/bar:v1.0")
+	if err != nil || got != digest {
+		t.Errorf("HeadDigest() = %v, %v, want %v", got, err, digest)
+	}
+
+	raw, mediaType, err := f.GetManifest(ctx, "gcr.io/foo/bar@"+digest)
+	if err != nil || string(raw) != "{}" || mediaType != cr.OCIManifestSchema1 {
+		t.Errorf("GetManifest() = %s, %v, %v", raw, mediaType, err)
+	}
+
+	// Missing objects fail like on a registry
+	_, _, err = f.GetManifest(ctx, "gcr.io/foo/bar:v2.0")
+
+	var terr *transport.Error
+	if !errors.As(err, &terr) || terr.StatusCode != http.StatusNotFound {
+		t.Errorf("GetManifest() error = %v, want not found", err)
+	}
+
+	tags, err := f.ListTags(ctx, "gcr.io/foo/bar")
+	if err != nil || len(tags) != 2 || tags[0] != "latest" || tags[1] != "v1.0" {
+		t.Errorf("ListTags() = %v, %v", tags, err)
+	}
+
+	referrers, err := f.ListReferrers(ctx, "gcr.io/foo/bar@"+digest, "application/vnd.example+json")
+	if err != nil || len(referrers) != 1 || referrers[0].Digest.String() != referrer {
+		t.Errorf("ListReferrers() = %v, %v", referrers, err)
+	}
+
+	referrers, err = f.ListReferrers(ctx, "gcr.io/foo/bar@"+digest, "application/vnd.other+json")
+	if err != nil || len(referrers) != 0 {
+		t.Errorf("ListReferrers() = %v, %v, want none", referrers, err)
+	}
+}
+
+func TestFakeProviderAddIndex(t *testing.T) {
+	const (
+		index = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		amd64 = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+		arm64 = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	)
+
+	f := NewFakeProvider()
+	f.AddImage("gcr.io/foo", "bar", amd64, "v0.9")
+	f.AddIndex("gcr.io/foo", "bar", index, []IndexChild{
+		{Digest: amd64, Platform: "linux/amd64"},
+		{Digest: arm64, Platform: "linux/arm64/v8"},
+	}, "v1.0")
+
+	dmap := f.Inventory.Images["gcr.io/foo"]["bar"]
+	if len(dmap) != 3 || len(dmap[index]) != 1 || len(dmap[amd64]) != 1 || len(dmap[arm64]) != 0 {
+		t.Errorf("inventory = %v", dmap)
+	}
+
+	want := map[image.Digest]cr.MediaType{
+		index: cr.OCIImageIndex,
+		amd64: cr.DockerManifestSchema2,
+		arm64: cr.OCIManifestSchema1,
+	}
+	for digest, mediaType := range want {
+		if got := f.Inventory.MediaTypes[digest]; got != mediaType {
+			t.Errorf("MediaTypes[%s] = %s, want %s", digest, got, mediaType)
+		}
+	}
+
+	raw, mediaType, err := f.GetManifest(context.Background(), "gcr.io/foo/bar:v1.0")
+	if err != nil || mediaType != cr.OCIImageIndex {
+		t.Fatalf("GetManifest() = %v, %v", mediaType, err)
+	}
+
+	idx, err := v1.ParseIndexManifest(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parsing index: %v", err)
+	}
+
+	if len(idx.Manifests) != 2 || idx.Manifests[1].Digest.String() != arm64 ||
+		idx.Manifests[1].Platform.String() != "linux/arm64/v8" {
+		t.Errorf("index manifests = %+v", idx.Manifests)
+	}
+}
+
+func TestFakeProviderCopyImageErrs(t *testing.T) {
+	ctx := context.Background()
+
+	f := NewFakeProvider()
+	f.CopyImageErrs = map[string]error{
+		"gcr.io/mirror":         errors.New("denied"),
+		"gcr.io/mirror/allowed": nil,
+	}
+	f.CopyImageErrOnCall = map[int]error{1: errors.New("flake")}
+
+	tests := []struct {
+		dst     string
+		wantErr bool
+	}{
+		{dst: "gcr.io/prod/foo:v1.0"},
+		{dst: "gcr.io/prod/foo:v1.1", wantErr: true},
+		{dst: "gcr.io/mirror/foo:v1.0", wantErr: true},
+		{dst: "gcr.io/mirror/allowed:v1.0"},
+		{dst: "gcr.io/mirror2/foo:v1.0"},
+	}
+
+	for _, tt := range tests {
+		if err := f.CopyImage(ctx, "gcr.io/staging/foo:v1.0", tt.dst); (err != nil) != tt.wantErr {
+			t.Errorf("CopyImage(%s) error = %v, wantErr %v", tt.dst, err, tt.wantErr)
+		}
+	}
+
+	if len(f.CopiedImages) != len(tests) {
+		t.Errorf("len(CopiedImages) = %d, want %d", len(f.CopiedImages), len(tests))
+	}
+}
+
+func TestFakeProviderCalls(t *testing.T) {
+	f := NewFakeProvider()
+	f.AddImage("gcr.io/foo", "bar", "sha256:abc", "v1.0")
+
+	ctx := context.Background()
+
+	if _, err := f.ListTags(ctx, "gcr.io/foo/bar"); err != nil {
+		t.Fatalf("ListTags() error = %v", err)
+	}
+
+	if err := f.CopyImage(ctx, "gcr.io/foo/bar:v1.0", "gcr.io/baz/bar:v1.0"); err != nil {
+		t.Fatalf("CopyImage() error = %v", err)
+	}
+
+	if err := f.DeleteTag(ctx, "gcr.io/foo/bar:v1.0"); err != nil {
+		t.Fatalf("DeleteTag() error = %v", err)
+	}
+
+	want := []Call{
+		{Method: "ListTags", Args: []string{"gcr.io/foo/bar"}},
+		{Method: "CopyImage", Args: []string{"gcr.io/foo/bar:v1.0", "gcr.io/baz/bar:v1.0"}},
+		{Method: "DeleteTag", Args: []string{"gcr.io/foo/bar:v1.0"}},
+	}
+	if !reflect.DeepEqual(f.Calls, want) {
+		t.Errorf("Calls = %v, want %v", f.Calls, want)
+	}
+
+	if got := f.CallsTo("DeleteTag"); len(got) != 1 || got[0][0] != "gcr.io/foo/bar:v1.0" {
+		t.Errorf("CallsTo(DeleteTag) = %v", got)
+	}
+
+	// Operations wait for the latency, or fail when canceled meanwhile
+	f.Latency = time.Hour
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+
+	if err := f.CopyImage(ctx, "gcr.io/foo/bar:v1.0", "gcr.io/baz/bar:v1.0"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CopyImage() error = %v, want deadline exceeded", err)
+	}
+}
+
 func TestSplitByKnownRegistries(t *testing.T) {
 	registries := []RegistryConfig{
 		{Name: "gcr.io/k8s-staging-foo"},
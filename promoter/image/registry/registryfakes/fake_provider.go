@@ -21,7 +21,10 @@ import (
 	"context"
 	"sync"
 
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/types"
 	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
 type FakeProvider struct {
@@ -38,6 +41,89 @@ type FakeProvider struct {
 	copyImageReturnsOnCall map[int]struct {
 		result1 error
 	}
+	DeleteManifestStub        func(context.Context, string) error
+	deleteManifestMutex       sync.RWMutex
+	deleteManifestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteManifestReturns struct {
+		result1 error
+	}
+	deleteManifestReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteTagStub        func(context.Context, string) error
+	deleteTagMutex       sync.RWMutex
+	deleteTagArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	deleteTagReturns struct {
+		result1 error
+	}
+	deleteTagReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetManifestStub        func(context.Context, string) ([]byte, types.MediaType, error)
+	getManifestMutex       sync.RWMutex
+	getManifestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getManifestReturns struct {
+		result1 []byte
+		result2 types.MediaType
+		result3 error
+	}
+	getManifestReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 types.MediaType
+		result3 error
+	}
+	HeadDigestStub        func(context.Context, string) (image.Digest, error)
+	headDigestMutex       sync.RWMutex
+	headDigestArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	headDigestReturns struct {
+		result1 image.Digest
+		result2 error
+	}
+	headDigestReturnsOnCall map[int]struct {
+		result1 image.Digest
+		result2 error
+	}
+	ListReferrersStub        func(context.Context, string, string) ([]v1.Descriptor, error)
+	listReferrersMutex       sync.RWMutex
+	listReferrersArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}
+	listReferrersReturns struct {
+		result1 []v1.Descriptor
+		result2 error
+	}
+	listReferrersReturnsOnCall map[int]struct {
+		result1 []v1.Descriptor
+		result2 error
+	}
+	ListTagsStub        func(context.Context, string) ([]image.Tag, error)
+	listTagsMutex       sync.RWMutex
+	listTagsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	listTagsReturns struct {
+		result1 []image.Tag
+		result2 error
+	}
+	listTagsReturnsOnCall map[int]struct {
+		result1 []image.Tag
+		result2 error
+	}
 	ReadRegistriesStub        func(context.Context, []registry.RegistryConfig, bool, []registry.RegistryConfig) (*registry.Inventory, error)
 	readRegistriesMutex       sync.RWMutex
 	readRegistriesArgsForCall []struct {
@@ -121,6 +207,394 @@ func (fake *FakeProvider) CopyImageReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeProvider) DeleteManifest(arg1 context.Context, arg2 string) error {
+	fake.deleteManifestMutex.Lock()
+	ret, specificReturn := fake.deleteManifestReturnsOnCall[len(fake.deleteManifestArgsForCall)]
+	fake.deleteManifestArgsForCall = append(fake.deleteManifestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteManifestStub
+	fakeReturns := fake.deleteManifestReturns
+	fake.recordInvocation("DeleteManifest", []interface{}{arg1, arg2})
+	fake.deleteManifestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) DeleteManifestCallCount() int {
+	fake.deleteManifestMutex.RLock()
+	defer fake.deleteManifestMutex.RUnlock()
+	return len(fake.deleteManifestArgsForCall)
+}
+
+func (fake *FakeProvider) DeleteManifestCalls(stub func(context.Context, string) error) {
+	fake.deleteManifestMutex.Lock()
+	defer fake.deleteManifestMutex.Unlock()
+	fake.DeleteManifestStub = stub
+}
+
+func (fake *FakeProvider) DeleteManifestArgsForCall(i int) (context.Context, string) {
+	fake.deleteManifestMutex.RLock()
+	defer fake.deleteManifestMutex.RUnlock()
+	argsForCall := fake.deleteManifestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) DeleteManifestReturns(result1 error) {
+	fake.deleteManifestMutex.Lock()
+	defer fake.deleteManifestMutex.Unlock()
+	fake.DeleteManifestStub = nil
+	fake.deleteManifestReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteManifestReturnsOnCall(i int, result1 error) {
+	fake.deleteManifestMutex.Lock()
+	defer fake.deleteManifestMutex.Unlock()
+	fake.DeleteManifestStub = nil
+	if fake.deleteManifestReturnsOnCall == nil {
+		fake.deleteManifestReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteManifestReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteTag(arg1 context.Context, arg2 string) error {
+	fake.deleteTagMutex.Lock()
+	ret, specificReturn := fake.deleteTagReturnsOnCall[len(fake.deleteTagArgsForCall)]
+	fake.deleteTagArgsForCall = append(fake.deleteTagArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeleteTagStub
+	fakeReturns := fake.deleteTagReturns
+	fake.recordInvocation("DeleteTag", []interface{}{arg1, arg2})
+	fake.deleteTagMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProvider) DeleteTagCallCount() int {
+	fake.deleteTagMutex.RLock()
+	defer fake.deleteTagMutex.RUnlock()
+	return len(fake.deleteTagArgsForCall)
+}
+
+func (fake *FakeProvider) DeleteTagCalls(stub func(context.Context, string) error) {
+	fake.deleteTagMutex.Lock()
+	defer fake.deleteTagMutex.Unlock()
+	fake.DeleteTagStub = stub
+}
+
+func (fake *FakeProvider) DeleteTagArgsForCall(i int) (context.Context, string) {
+	fake.deleteTagMutex.RLock()
+	defer fake.deleteTagMutex.RUnlock()
+	argsForCall := fake.deleteTagArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) DeleteTagReturns(result1 error) {
+	fake.deleteTagMutex.Lock()
+	defer fake.deleteTagMutex.Unlock()
+	fake.DeleteTagStub = nil
+	fake.deleteTagReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) DeleteTagReturnsOnCall(i int, result1 error) {
+	fake.deleteTagMutex.Lock()
+	defer fake.deleteTagMutex.Unlock()
+	fake.DeleteTagStub = nil
+	if fake.deleteTagReturnsOnCall == nil {
+		fake.deleteTagReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteTagReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProvider) GetManifest(arg1 context.Context, arg2 string) ([]byte, types.MediaType, error) {
+	fake.getManifestMutex.Lock()
+	ret, specificReturn := fake.getManifestReturnsOnCall[len(fake.getManifestArgsForCall)]
+	fake.getManifestArgsForCall = append(fake.getManifestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetManifestStub
+	fakeReturns := fake.getManifestReturns
+	fake.recordInvocation("GetManifest", []interface{}{arg1, arg2})
+	fake.getManifestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeProvider) GetManifestCallCount() int {
+	fake.getManifestMutex.RLock()
+	defer fake.getManifestMutex.RUnlock()
+	return len(fake.getManifestArgsForCall)
+}
+
+func (fake *FakeProvider) GetManifestCalls(stub func(context.Context, string) ([]byte, types.MediaType, error)) {
+	fake.getManifestMutex.Lock()
+	defer fake.getManifestMutex.Unlock()
+	fake.GetManifestStub = stub
+}
+
+func (fake *FakeProvider) GetManifestArgsForCall(i int) (context.Context, string) {
+	fake.getManifestMutex.RLock()
+	defer fake.getManifestMutex.RUnlock()
+	argsForCall := fake.getManifestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) GetManifestReturns(result1 []byte, result2 types.MediaType, result3 error) {
+	fake.getManifestMutex.Lock()
+	defer fake.getManifestMutex.Unlock()
+	fake.GetManifestStub = nil
+	fake.getManifestReturns = struct {
+		result1 []byte
+		result2 types.MediaType
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeProvider) GetManifestReturnsOnCall(i int, result1 []byte, result2 types.MediaType, result3 error) {
+	fake.getManifestMutex.Lock()
+	defer fake.getManifestMutex.Unlock()
+	fake.GetManifestStub = nil
+	if fake.getManifestReturnsOnCall == nil {
+		fake.getManifestReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 types.MediaType
+			result3 error
+		})
+	}
+	fake.getManifestReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 types.MediaType
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeProvider) HeadDigest(arg1 context.Context, arg2 string) (image.Digest, error) {
+	fake.headDigestMutex.Lock()
+	ret, specificReturn := fake.headDigestReturnsOnCall[len(fake.headDigestArgsForCall)]
+	fake.headDigestArgsForCall = append(fake.headDigestArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.HeadDigestStub
+	fakeReturns := fake.headDigestReturns
+	fake.recordInvocation("HeadDigest", []interface{}{arg1, arg2})
+	fake.headDigestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) HeadDigestCallCount() int {
+	fake.headDigestMutex.RLock()
+	defer fake.headDigestMutex.RUnlock()
+	return len(fake.headDigestArgsForCall)
+}
+
+func (fake *FakeProvider) HeadDigestCalls(stub func(context.Context, string) (image.Digest, error)) {
+	fake.headDigestMutex.Lock()
+	defer fake.headDigestMutex.Unlock()
+	fake.HeadDigestStub = stub
+}
+
+func (fake *FakeProvider) HeadDigestArgsForCall(i int) (context.Context, string) {
+	fake.headDigestMutex.RLock()
+	defer fake.headDigestMutex.RUnlock()
+	argsForCall := fake.headDigestArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) HeadDigestReturns(result1 image.Digest, result2 error) {
+	fake.headDigestMutex.Lock()
+	defer fake.headDigestMutex.Unlock()
+	fake.HeadDigestStub = nil
+	fake.headDigestReturns = struct {
+		result1 image.Digest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) HeadDigestReturnsOnCall(i int, result1 image.Digest, result2 error) {
+	fake.headDigestMutex.Lock()
+	defer fake.headDigestMutex.Unlock()
+	fake.HeadDigestStub = nil
+	if fake.headDigestReturnsOnCall == nil {
+		fake.headDigestReturnsOnCall = make(map[int]struct {
+			result1 image.Digest
+			result2 error
+		})
+	}
+	fake.headDigestReturnsOnCall[i] = struct {
+		result1 image.Digest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListReferrers(arg1 context.Context, arg2 string, arg3 string) ([]v1.Descriptor, error) {
+	fake.listReferrersMutex.Lock()
+	ret, specificReturn := fake.listReferrersReturnsOnCall[len(fake.listReferrersArgsForCall)]
+	fake.listReferrersArgsForCall = append(fake.listReferrersArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ListReferrersStub
+	fakeReturns := fake.listReferrersReturns
+	fake.recordInvocation("ListReferrers", []interface{}{arg1, arg2, arg3})
+	fake.listReferrersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListReferrersCallCount() int {
+	fake.listReferrersMutex.RLock()
+	defer fake.listReferrersMutex.RUnlock()
+	return len(fake.listReferrersArgsForCall)
+}
+
+func (fake *FakeProvider) ListReferrersCalls(stub func(context.Context, string, string) ([]v1.Descriptor, error)) {
+	fake.listReferrersMutex.Lock()
+	defer fake.listReferrersMutex.Unlock()
+	fake.ListReferrersStub = stub
+}
+
+func (fake *FakeProvider) ListReferrersArgsForCall(i int) (context.Context, string, string) {
+	fake.listReferrersMutex.RLock()
+	defer fake.listReferrersMutex.RUnlock()
+	argsForCall := fake.listReferrersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProvider) ListReferrersReturns(result1 []v1.Descriptor, result2 error) {
+	fake.listReferrersMutex.Lock()
+	defer fake.listReferrersMutex.Unlock()
+	fake.ListReferrersStub = nil
+	fake.listReferrersReturns = struct {
+		result1 []v1.Descriptor
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListReferrersReturnsOnCall(i int, result1 []v1.Descriptor, result2 error) {
+	fake.listReferrersMutex.Lock()
+	defer fake.listReferrersMutex.Unlock()
+	fake.ListReferrersStub = nil
+	if fake.listReferrersReturnsOnCall == nil {
+		fake.listReferrersReturnsOnCall = make(map[int]struct {
+			result1 []v1.Descriptor
+			result2 error
+		})
+	}
+	fake.listReferrersReturnsOnCall[i] = struct {
+		result1 []v1.Descriptor
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListTags(arg1 context.Context, arg2 string) ([]image.Tag, error) {
+	fake.listTagsMutex.Lock()
+	ret, specificReturn := fake.listTagsReturnsOnCall[len(fake.listTagsArgsForCall)]
+	fake.listTagsArgsForCall = append(fake.listTagsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ListTagsStub
+	fakeReturns := fake.listTagsReturns
+	fake.recordInvocation("ListTags", []interface{}{arg1, arg2})
+	fake.listTagsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProvider) ListTagsCallCount() int {
+	fake.listTagsMutex.RLock()
+	defer fake.listTagsMutex.RUnlock()
+	return len(fake.listTagsArgsForCall)
+}
+
+func (fake *FakeProvider) ListTagsCalls(stub func(context.Context, string) ([]image.Tag, error)) {
+	fake.listTagsMutex.Lock()
+	defer fake.listTagsMutex.Unlock()
+	fake.ListTagsStub = stub
+}
+
+func (fake *FakeProvider) ListTagsArgsForCall(i int) (context.Context, string) {
+	fake.listTagsMutex.RLock()
+	defer fake.listTagsMutex.RUnlock()
+	argsForCall := fake.listTagsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProvider) ListTagsReturns(result1 []image.Tag, result2 error) {
+	fake.listTagsMutex.Lock()
+	defer fake.listTagsMutex.Unlock()
+	fake.ListTagsStub = nil
+	fake.listTagsReturns = struct {
+		result1 []image.Tag
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProvider) ListTagsReturnsOnCall(i int, result1 []image.Tag, result2 error) {
+	fake.listTagsMutex.Lock()
+	defer fake.listTagsMutex.Unlock()
+	fake.ListTagsStub = nil
+	if fake.listTagsReturnsOnCall == nil {
+		fake.listTagsReturnsOnCall = make(map[int]struct {
+			result1 []image.Tag
+			result2 error
+		})
+	}
+	fake.listTagsReturnsOnCall[i] = struct {
+		result1 []image.Tag
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeProvider) ReadRegistries(arg1 context.Context, arg2 []registry.RegistryConfig, arg3 bool, arg4 []registry.RegistryConfig) (*registry.Inventory, error) {
 	var arg2Copy []registry.RegistryConfig
 	if arg2 != nil {
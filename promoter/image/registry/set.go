@@ -17,6 +17,8 @@ limitations under the License.
 package registry
 
 import (
+	"sort"
+
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
 
@@ -76,3 +78,15 @@ func (a TagSlice) Intersection(b TagSlice) TagSet {
 
 	return result
 }
+
+// ToSorted converts a TagSet to a sorted TagSlice.
+func (s TagSet) ToSorted() TagSlice {
+	tags := make(TagSlice, 0, len(s))
+	for tag := range s {
+		tags = append(tags, tag)
+	}
+
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+
+	return tags
+}
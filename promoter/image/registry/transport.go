@@ -0,0 +1,313 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// TransportConfig configures the connections to the host of a registry,
+// e.g. a private registry with its own certificate authority or a local
+// registry served over plain HTTP. The settings apply to every registry of
+// the same host.
+type TransportConfig struct {
+	// CABundle is the path of a PEM file of certificate authorities
+	// trusted in addition to the system ones.
+	CABundle string `yaml:"caBundle,omitempty"`
+
+	// ClientCert and ClientKey are the paths of the PEM certificate and
+	// key presented to registries requiring mutual TLS.
+	ClientCert string `yaml:"clientCert,omitempty"`
+	ClientKey  string `yaml:"clientKey,omitempty"`
+
+	// Insecure skips the verification of the certificate of the registry.
+	Insecure bool `yaml:"insecure,omitempty"`
+
+	// PlainHTTP talks to the registry over HTTP instead of HTTPS.
+	PlainHTTP bool `yaml:"plainHTTP,omitempty"`
+
+	// Proxy is the URL of the proxy used to reach the registry instead
+	// of the one of the environment.
+	Proxy string `yaml:"proxy,omitempty"`
+}
+
+// Validate checks that the transport settings are consistent.
+func (c *TransportConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if *c == (TransportConfig{}) {
+		return errors.New("no transport settings set")
+	}
+
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return errors.New("clientCert and clientKey have to be set together")
+	}
+
+	if c.PlainHTTP && (c.CABundle != "" || c.ClientCert != "" || c.Insecure) {
+		return errors.New("plainHTTP cannot be combined with TLS settings")
+	}
+
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil {
+			return fmt.Errorf("parsing proxy: %w", err)
+		}
+
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("proxy %q needs an http, https or socks5 scheme", c.Proxy)
+		}
+
+		if u.Host == "" {
+			return fmt.Errorf("proxy %q has no host", c.Proxy)
+		}
+	}
+
+	return nil
+}
+
+// Transports is an HTTP transport routing the requests to a registry host
+// to the transport built from its settings, and the other requests to a
+// base transport. It is meant to be the base of the shared rate-limited
+// transport, so the settings of registries declared in manifests apply to
+// every operation on them.
+//
+// Requests are routed by host only: a redirect to another host, like the
+// blob storage or the token realm of a registry, is sent with the base
+// transport, without the CA bundle, client certificate or proxy of the
+// registry.
+type Transports struct {
+	base http.RoundTripper
+
+	mu    sync.RWMutex
+	hosts map[string]hostTransport
+}
+
+// hostTransport is the transport of a registry host and the settings and
+// registry it was built from.
+type hostTransport struct {
+	transport http.RoundTripper
+	config    TransportConfig
+	registry  image.Registry
+}
+
+var _ http.RoundTripper = &Transports{}
+
+// NewTransports returns Transports sending the requests to hosts without
+// settings to base, or http.DefaultTransport when nil.
+func NewTransports(base http.RoundTripper) *Transports {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transports{
+		base:  base,
+		hosts: map[string]hostTransport{},
+	}
+}
+
+// AddContexts builds the transports of the registries with transport
+// settings. Registering the same settings again is a no-op, registering
+// different settings for the same host is an error.
+func (t *Transports) AddContexts(rcs []Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, rc := range rcs {
+		if rc.Transport == nil {
+			continue
+		}
+
+		host, err := registryHost(rc.Name)
+		if err != nil {
+			return err
+		}
+
+		if existing, ok := t.hosts[host]; ok {
+			if existing.config != *rc.Transport {
+				return fmt.Errorf(
+					"conflicting transport settings for host %s of registries %s and %s",
+					host, existing.registry, rc.Name,
+				)
+			}
+
+			continue
+		}
+
+		transport, err := newHostTransport(rc.Transport)
+		if err != nil {
+			return fmt.Errorf("building transport of registry %s: %w", rc.Name, err)
+		}
+
+		t.hosts[host] = hostTransport{
+			transport: transport,
+			config:    *rc.Transport,
+			registry:  rc.Name,
+		}
+	}
+
+	return nil
+}
+
+// RoundTrip sends the request with the transport of its host.
+func (t *Transports) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	ht, ok := t.hosts[req.URL.Host]
+	t.mu.RUnlock()
+
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+
+	// go-containerregistry talks HTTPS to registries other than localhost,
+	// plain HTTP registries get their requests downgraded. The responses
+	// carry the downgraded request, so the following requests and token
+	// realms use HTTP as well. This relies on go-containerregistry building
+	// the next URLs from the responses; name.Insecure references would not
+	// need it, but the promoter parses its references in too many places.
+	if ht.config.PlainHTTP && req.URL.Scheme == "https" {
+		u := *req.URL
+		u.Scheme = "http"
+
+		req = req.Clone(req.Context())
+		req.URL = &u
+	}
+
+	return ht.transport.RoundTrip(req)
+}
+
+// Insecure reports whether the certificate of a host is not verified, or a
+// host is talked to over plain HTTP.
+func (t *Transports) Insecure() bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, ht := range t.hosts {
+		if ht.config.Insecure || ht.config.PlainHTTP {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InsecureReference reports whether the certificate of the registry host of
+// an image reference is not verified, or the host is talked to over plain
+// HTTP.
+func (t *Transports) InsecureReference(ref string) bool {
+	if t == nil {
+		return false
+	}
+
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		return false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ht, ok := t.hosts[r.Context().RegistryStr()]
+
+	return ok && (ht.config.Insecure || ht.config.PlainHTTP)
+}
+
+// registryHost returns the host of a registry as found in the URLs of the
+// requests to it.
+func registryHost(r image.Registry) (string, error) {
+	host, _, _ := strings.Cut(string(r), "/")
+
+	reg, err := name.NewRegistry(host)
+	if err != nil {
+		return "", fmt.Errorf("parsing host of registry %s: %w", r, err)
+	}
+
+	return reg.RegistryStr(), nil
+}
+
+// newHostTransport builds the HTTP transport of the settings of a host.
+func newHostTransport(c *TransportConfig) (http.RoundTripper, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("default transport is not an *http.Transport")
+	}
+
+	transport := base.Clone()
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.Insecure, //nolint:gosec // opted in per registry
+	}
+
+	if c.CABundle != "" {
+		pem, err := os.ReadFile(c.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", c.CABundle)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if c.Proxy != "" {
+		proxy, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy: %w", err)
+		}
+
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return transport, nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+func TestTransportConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		config  *TransportConfig
+		wantErr string
+	}{
+		{name: "unset"},
+		{name: "empty", config: &TransportConfig{}, wantErr: "no transport settings set"},
+		{name: "plain HTTP", config: &TransportConfig{PlainHTTP: true}},
+		{name: "mutual TLS", config: &TransportConfig{CABundle: "ca.pem", ClientCert: "cert.pem", ClientKey: "key.pem"}},
+		{
+			name:    "client cert without key",
+			config:  &TransportConfig{ClientCert: "cert.pem"},
+			wantErr: "clientCert and clientKey have to be set together",
+		},
+		{
+			name:    "plain HTTP with TLS",
+			config:  &TransportConfig{PlainHTTP: true, CABundle: "ca.pem"},
+			wantErr: "plainHTTP cannot be combined with TLS settings",
+		},
+		{name: "proxy", config: &TransportConfig{Proxy: "http://proxy.example.com:3128"}},
+		{
+			name:    "proxy without scheme",
+			config:  &TransportConfig{Proxy: "proxy.example.com"},
+			wantErr: "needs an http, https or socks5 scheme",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.config.Validate()
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTransports(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: tlsServer.Certificate().Raw,
+	}), 0o600))
+
+	tlsHost := tlsServer.Listener.Addr().String()
+	httpHost := httpServer.Listener.Addr().String()
+
+	get := func(tr http.RoundTripper, host string) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, "https://"+host+"/v2/", http.NoBody)
+		require.NoError(t, err)
+
+		resp, err := tr.RoundTrip(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		return resp, err
+	}
+
+	tr := NewTransports(nil)
+	require.False(t, tr.Insecure())
+
+	// Without settings the certificate of the server is not trusted
+	_, err := get(tr, tlsHost)
+	require.Error(t, err)
+
+	require.NoError(t, tr.AddContexts([]Context{
+		{Name: image.Registry(tlsHost + "/staging"), Src: true, Transport: &TransportConfig{CABundle: caBundle}},
+		{Name: image.Registry(tlsHost + "/prod"), Transport: &TransportConfig{CABundle: caBundle}},
+		{Name: image.Registry(httpHost + "/prod"), Transport: &TransportConfig{PlainHTTP: true}},
+	}))
+	require.True(t, tr.Insecure())
+
+	// Only the images of the plain HTTP registry may be signed insecurely
+	require.True(t, tr.InsecureReference(httpHost+"/prod/foo:v1.0"))
+	require.False(t, tr.InsecureReference(tlsHost+"/prod/foo:v1.0"))
+	require.False(t, tr.InsecureReference("registry.k8s.io/foo:v1.0"))
+
+	resp, err := get(tr, tlsHost)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Requests to plain HTTP registries are downgraded
+	resp, err = get(tr, httpHost)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "http", resp.Request.URL.Scheme)
+
+	require.ErrorContains(t, tr.AddContexts([]Context{
+		{Name: image.Registry(httpHost + "/mirror"), Transport: &TransportConfig{Insecure: true}},
+	}), "conflicting transport settings for host "+httpHost)
+
+	require.ErrorContains(t, tr.AddContexts([]Context{
+		{Name: "registry.example.com/prod", Transport: &TransportConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")}},
+	}), "building transport of registry registry.example.com/prod: reading CA bundle")
+}
+
+func TestTransportsRedirect(t *testing.T) {
+	t.Parallel()
+
+	// Registries redirect blob downloads to a storage host, e.g. a bucket
+	storage := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer storage.Close()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, storage.URL+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0o600))
+
+	host := server.Listener.Addr().String()
+	blob := "https://" + host + "/v2/foo/blobs/sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	// The redirect leaves the registry host, so it is sent with the base
+	// transport, without the settings of the registry
+	base := &countingTransport{base: storage.Client().Transport}
+	tr := NewTransports(base)
+	require.NoError(t, tr.AddContexts([]Context{
+		{Name: image.Registry(host + "/prod"), Transport: &TransportConfig{CABundle: caBundle}},
+	}))
+
+	resp, err := (&http.Client{Transport: tr}).Get(blob)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, storage.Listener.Addr().String(), resp.Request.URL.Host)
+	require.EqualValues(t, 1, base.count.Load())
+
+	// so the CA bundle of the registry does not apply to the storage host
+	tr = NewTransports(nil)
+	require.NoError(t, tr.AddContexts([]Context{
+		{Name: image.Registry(host + "/prod"), Transport: &TransportConfig{CABundle: caBundle}},
+	}))
+
+	_, err = (&http.Client{Transport: tr}).Get(blob) //nolint:bodyclose // the request fails
+	require.ErrorContains(t, err, storage.Listener.Addr().String())
+}
@@ -17,9 +17,12 @@ limitations under the License.
 package registry
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/promo-tools/v4/types/image"
 )
@@ -28,8 +31,8 @@ import (
 // sense, and holds all the information relating to a particular image that we
 // care about.
 type Image struct {
-	Name image.Name `yaml:"name"`
-	Dmap DigestTags `yaml:"dmap,omitempty"`
+	Name image.Name `json:"name"           yaml:"name"`
+	Dmap DigestTags `json:"dmap,omitempty" yaml:"dmap,omitempty"`
 }
 
 // Images is a slice of Image types.
@@ -54,6 +57,11 @@ type DigestTags map[image.Digest]TagSlice
 // TagSlice is a slice of Tags.
 type TagSlice []image.Tag
 
+// CosignTag matches the tags cosign uses to store the signatures,
+// attestations and SBOMs of an image, and captures the hex digest of that
+// image.
+var CosignTag = regexp.MustCompile(`^sha256-([a-f0-9]{64})\.(sig|att|sbom)$`)
+
 // ToYAML displays a RegInvImage as YAML, but with the map items sorted
 // alphabetically.
 func (a *RegInvImage) ToYAML(o YamlMarshalingOpts) string {
@@ -133,6 +141,112 @@ func (a *RegInvImage) ToCSV() string {
 	return b.String()
 }
 
+// ToJSON displays a RegInvImage as a JSON list of images in the same
+// layout as ToYAML, sorted by image name, digest and tag.
+func (a *RegInvImage) ToJSON() (string, error) {
+	images := Images{}
+
+	for _, img := range a.ToSorted() {
+		dmap := DigestTags{}
+
+		for _, digestEntry := range img.Digests {
+			tags := TagSlice{}
+			for _, tag := range digestEntry.Tags {
+				tags = append(tags, image.Tag(tag))
+			}
+
+			dmap[image.Digest(digestEntry.Hash)] = tags
+		}
+
+		images = append(images, Image{Name: image.Name(img.Name), Dmap: dmap})
+	}
+
+	b, err := json.MarshalIndent(images, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling images to JSON: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// SplitByPrefix splits a RegInvImage by the first path element of the
+// image names, e.g. image "foo/bar" becomes image "bar" of project "foo".
+// Images without a path element belong to defaultProject.
+func (a *RegInvImage) SplitByPrefix(defaultProject string) map[string]RegInvImage {
+	projects := map[string]RegInvImage{}
+
+	for name, dmap := range *a {
+		project, imageName, ok := strings.Cut(string(name), "/")
+		if !ok {
+			project, imageName = defaultProject, string(name)
+		}
+
+		if projects[project] == nil {
+			projects[project] = RegInvImage{}
+		}
+
+		projects[project][image.Name(imageName)] = dmap
+	}
+
+	return projects
+}
+
+// DetailedImage is an image of a detailed snapshot, which records the
+// manifest metadata of every digest along with its tags.
+type DetailedImage struct {
+	Name    image.Name       `json:"name"`
+	Digests []DetailedDigest `json:"digests"`
+}
+
+// DetailedDigest is a digest of a detailed snapshot.
+type DetailedDigest struct {
+	Digest    image.Digest `json:"digest"`
+	Tags      TagSlice     `json:"tags"`
+	MediaType string       `json:"mediaType,omitempty"`
+	Size      int64        `json:"size,omitempty"`
+	Created   *time.Time   `json:"created,omitempty"`
+	Uploaded  *time.Time   `json:"uploaded,omitempty"`
+	Platforms []string     `json:"platforms,omitempty"`
+}
+
+// ToDetailed converts a RegInvImage to a sorted list of images with the
+// manifest details of their digests. Digests without details only have
+// their tags set.
+func (a *RegInvImage) ToDetailed(details map[image.Digest]ManifestDetails) []DetailedImage {
+	images := []DetailedImage{}
+
+	for _, img := range a.ToSorted() {
+		detailed := DetailedImage{Name: image.Name(img.Name), Digests: []DetailedDigest{}}
+
+		for _, digestEntry := range img.Digests {
+			d := DetailedDigest{Digest: image.Digest(digestEntry.Hash), Tags: TagSlice{}}
+			for _, tag := range digestEntry.Tags {
+				d.Tags = append(d.Tags, image.Tag(tag))
+			}
+
+			if md, ok := details[d.Digest]; ok {
+				d.MediaType = string(md.MediaType)
+				d.Size = md.Size
+				d.Platforms = md.Platforms
+
+				if !md.Created.IsZero() {
+					d.Created = &md.Created
+				}
+
+				if !md.Uploaded.IsZero() {
+					d.Uploaded = &md.Uploaded
+				}
+			}
+
+			detailed.Digests = append(detailed.Digests, d)
+		}
+
+		images = append(images, detailed)
+	}
+
+	return images
+}
+
 // ToSorted converts a RegInvImage type to a sorted structure.
 func (a *RegInvImage) ToSorted() []ImageWithDigestSlice {
 	images := make([]ImageWithDigestSlice, 0, len(*a))
@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+const (
+	digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func testRegInvImage() registry.RegInvImage {
+	return registry.RegInvImage{
+		"foo/bar":     {digestA: {"v1.1", "v1.0"}},
+		"foo/sub/baz": {digestB: {}},
+		"qux":         {digestB: {"latest"}},
+	}
+}
+
+func TestRegInvImageToJSON(t *testing.T) {
+	rii := testRegInvImage()
+
+	out, err := rii.ToJSON()
+	require.NoError(t, err)
+
+	images := registry.Images{}
+	require.NoError(t, json.Unmarshal([]byte(out), &images))
+	require.Len(t, images, 3)
+	require.Equal(t, registry.Image{
+		Name: "foo/bar",
+		Dmap: registry.DigestTags{digestA: {"v1.0", "v1.1"}},
+	}, images[0])
+	require.Equal(t, registry.TagSlice{}, images[1].Dmap[digestB])
+	require.Equal(t, "qux", string(images[2].Name))
+}
+
+func TestCosignTag(t *testing.T) {
+	for tag, expected := range map[string]bool{
+		"sha256-" + digestA[7:] + ".sig":  true,
+		"sha256-" + digestA[7:] + ".att":  true,
+		"sha256-" + digestA[7:] + ".sbom": true,
+		"sha256-" + digestA[7:]:           false,
+		"sha256-" + digestA[7:] + ".txt":  false,
+		"v1.0.sig":                        false,
+	} {
+		require.Equal(t, expected, registry.CosignTag.MatchString(tag), tag)
+	}
+
+	match := registry.CosignTag.FindStringSubmatch("sha256-" + digestB[7:] + ".sig")
+	require.Equal(t, digestB[7:], match[1])
+}
+
+func TestRegInvImageSplitByPrefix(t *testing.T) {
+	rii := testRegInvImage()
+
+	projects := rii.SplitByPrefix("k8s-staging-qux")
+	require.Equal(t, map[string]registry.RegInvImage{
+		"foo": {
+			"bar":     {digestA: {"v1.1", "v1.0"}},
+			"sub/baz": {digestB: {}},
+		},
+		"k8s-staging-qux": {
+			"qux": {digestB: {"latest"}},
+		},
+	}, projects)
+}
+
+func TestRegInvImageToDetailed(t *testing.T) {
+	rii := testRegInvImage()
+	uploaded := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	detailed := rii.ToDetailed(map[image.Digest]registry.ManifestDetails{
+		digestA: {MediaType: cr.OCIImageIndex, Size: 42, Uploaded: uploaded, Platforms: []string{"linux/amd64"}},
+	})
+	require.Len(t, detailed, 3)
+
+	require.Equal(t, registry.DetailedDigest{
+		Digest:    digestA,
+		Tags:      registry.TagSlice{"v1.0", "v1.1"},
+		MediaType: string(cr.OCIImageIndex),
+		Size:      42,
+		Uploaded:  &uploaded,
+		Platforms: []string{"linux/amd64"},
+	}, detailed[0].Digests[0])
+
+	// Digests without details only have their tags
+	require.Equal(t, registry.DetailedDigest{Digest: digestB, Tags: registry.TagSlice{}}, detailed[1].Digests[0])
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package report writes the reports of the promoter checks.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Report output formats.
+const (
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatMarkdown = "markdown"
+)
+
+// Formats lists the report output formats. FormatMarkdown is only supported
+// by the reports implementing Markdowner.
+var Formats = []string{FormatJSON, FormatYAML, FormatMarkdown}
+
+// Markdowner is implemented by the reports that can be rendered as markdown.
+type Markdowner interface {
+	Markdown() string
+}
+
+// SupportedFormats returns the formats a report can be written in, which
+// include FormatMarkdown only if markdown is set.
+func SupportedFormats(markdown bool) []string {
+	if markdown {
+		return Formats
+	}
+
+	return slices.DeleteFunc(slices.Clone(Formats), func(f string) bool {
+		return f == FormatMarkdown
+	})
+}
+
+// ValidateFormat checks that format is one of the SupportedFormats.
+func ValidateFormat(format string, markdown bool) error {
+	if formats := SupportedFormats(markdown); !slices.Contains(formats, strings.ToLower(format)) {
+		return fmt.Errorf("invalid report format %q (must be one of %s)", format, strings.Join(formats, ", "))
+	}
+
+	return nil
+}
+
+// Write renders the report r to w in format.
+func Write(w io.Writer, r any, format string) error {
+	md, markdown := r.(Markdowner)
+	if err := ValidateFormat(format, markdown); err != nil {
+		return err
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		data, err = json.MarshalIndent(r, "", "  ")
+		data = append(data, '\n')
+	case FormatYAML:
+		data, err = yaml.Marshal(r)
+	case FormatMarkdown:
+		data = []byte(md.Markdown())
+	}
+
+	if err != nil {
+		return fmt.Errorf("marshaling report: %w", err)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/report"
+)
+
+type testReport struct {
+	Images []string `json:"images"`
+}
+
+type testMarkdownReport struct {
+	testReport
+}
+
+func (r testMarkdownReport) Markdown() string {
+	return "# images\n"
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	r := testReport{Images: []string{"foo", "bar"}}
+	md := testMarkdownReport{r}
+
+	for _, tc := range []struct {
+		name      string
+		report    any
+		format    string
+		expected  string
+		shouldErr bool
+	}{
+		{name: "json", report: r, format: report.FormatJSON, expected: "{\n  \"images\": [\n    \"foo\",\n    \"bar\"\n  ]\n}\n"},
+		{name: "yaml", report: r, format: "YAML", expected: "images:\n- foo\n- bar\n"},
+		{name: "markdown", report: md, format: report.FormatMarkdown, expected: "# images\n"},
+		{name: "markdown unsupported", report: r, format: report.FormatMarkdown, shouldErr: true},
+		{name: "unknown format", report: md, format: "csv", shouldErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			err := report.Write(&buf, tc.report, tc.format)
+			if tc.shouldErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+// Policy decides which digests of a staging registry are kept.
+type Policy struct {
+	// KeepTags is the number of most recently uploaded tags kept per
+	// image, along with their digests.
+	KeepTags int `json:"keepTags"`
+
+	// KeepDays keeps the digests uploaded less than this many days ago.
+	KeepDays int `json:"keepDays"`
+}
+
+// Report lists the digests of a staging registry the policy does not keep.
+type Report struct {
+	Registry image.Registry `json:"registry"`
+	Policy   Policy         `json:"policy"`
+
+	// Kept is the number of digests kept.
+	Kept int `json:"kept"`
+
+	// Deletable lists the digests to delete, sorted by image and digest.
+	Deletable []Digest `json:"deletable"`
+}
+
+// Digest is a digest of an image in the registry.
+type Digest struct {
+	Image  image.Name        `json:"image"`
+	Digest image.Digest      `json:"digest"`
+	Tags   registry.TagSlice `json:"tags,omitempty"`
+
+	// MediaType is the media type of the manifest.
+	MediaType cr.MediaType `json:"mediaType,omitempty"`
+
+	// Uploaded is the time the manifest was pushed to the registry.
+	Uploaded *time.Time `json:"uploaded,omitempty"`
+
+	// Deleted is true once the tags and the manifest have been deleted.
+	Deleted bool `json:"deleted,omitempty"`
+
+	// Error is set when deleting failed.
+	Error string `json:"error,omitempty"`
+}
+
+// IsIndex returns true if the digest is an image index.
+func (d *Digest) IsIndex() bool {
+	return d.MediaType == cr.DockerManifestList || d.MediaType == cr.OCIImageIndex
+}
+
+// Plan applies the policy to the images of a registry. A digest is kept if
+// it is promoted, if it is younger than KeepDays or has an unknown upload
+// time, or if it has one of the KeepTags most recent tags of its image.
+// The children of kept image indexes and the cosign signatures,
+// attestations and SBOMs of kept digests are kept along with them.
+// children maps the image indexes of the registry to their children.
+func Plan(
+	policy Policy,
+	registryName image.Registry,
+	rii registry.RegInvImage,
+	details map[image.Digest]registry.ManifestDetails,
+	promoted map[image.Digest]bool,
+	children map[image.Digest][]image.Digest,
+	now time.Time,
+) *Report {
+	report := &Report{
+		Registry:  registryName,
+		Policy:    policy,
+		Deletable: []Digest{},
+	}
+
+	for name, dmap := range rii {
+		kept := keptDigests(policy, dmap, details, promoted, now)
+
+		for digest := range dmap {
+			if kept[digest] {
+				for _, child := range children[digest] {
+					kept[child] = true
+				}
+			}
+		}
+
+		for digest, tags := range dmap {
+			for _, tag := range tags {
+				match := registry.CosignTag.FindStringSubmatch(string(tag))
+				if match != nil && kept[image.Digest("sha256:"+match[1])] {
+					kept[digest] = true
+				}
+			}
+		}
+
+		for digest, tags := range dmap {
+			if kept[digest] {
+				report.Kept++
+
+				continue
+			}
+
+			d := Digest{
+				Image:     name,
+				Digest:    digest,
+				Tags:      tags.ToTagSet().ToSorted(),
+				MediaType: details[digest].MediaType,
+			}
+
+			if uploaded := details[digest].Uploaded; !uploaded.IsZero() {
+				d.Uploaded = &uploaded
+			}
+
+			report.Deletable = append(report.Deletable, d)
+		}
+	}
+
+	sort.Slice(report.Deletable, func(i, j int) bool {
+		a, b := report.Deletable[i], report.Deletable[j]
+		if a.Image != b.Image {
+			return a.Image < b.Image
+		}
+
+		return a.Digest < b.Digest
+	})
+
+	return report
+}
+
+// keptDigests returns the digests of an image kept by the policy, without
+// the children and cosign artifacts of the kept ones.
+func keptDigests(
+	policy Policy,
+	dmap registry.DigestTags,
+	details map[image.Digest]registry.ManifestDetails,
+	promoted map[image.Digest]bool,
+	now time.Time,
+) map[image.Digest]bool {
+	kept := map[image.Digest]bool{}
+	cutoff := now.AddDate(0, 0, -policy.KeepDays)
+
+	type taggedDigest struct {
+		tag      image.Tag
+		digest   image.Digest
+		uploaded time.Time
+	}
+
+	tagged := []taggedDigest{}
+
+	for digest, tags := range dmap {
+		uploaded := details[digest].Uploaded
+
+		if promoted[digest] || uploaded.IsZero() || (policy.KeepDays > 0 && uploaded.After(cutoff)) {
+			kept[digest] = true
+		}
+
+		for _, tag := range tags {
+			if !registry.CosignTag.MatchString(string(tag)) {
+				tagged = append(tagged, taggedDigest{tag: tag, digest: digest, uploaded: uploaded})
+			}
+		}
+	}
+
+	sort.Slice(tagged, func(i, j int) bool {
+		if !tagged[i].uploaded.Equal(tagged[j].uploaded) {
+			return tagged[i].uploaded.After(tagged[j].uploaded)
+		}
+
+		return tagged[i].tag > tagged[j].tag
+	})
+
+	for i := 0; i < policy.KeepTags && i < len(tagged); i++ {
+		kept[tagged[i].digest] = true
+	}
+
+	return kept
+}
+
+// Failed returns the digests that could not be deleted.
+func (r *Report) Failed() []Digest {
+	failed := []Digest{}
+
+	for i := range r.Deletable {
+		if r.Deletable[i].Error != "" {
+			failed = append(failed, r.Deletable[i])
+		}
+	}
+
+	return failed
+}
+
+// Err returns an error listing the digests that could not be deleted, or
+// nil if there are none.
+func (r *Report) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	refs := make([]string, 0, len(failed))
+	for i := range failed {
+		refs = append(refs, fmt.Sprintf("%s/%s@%s", r.Registry, failed[i].Image, failed[i].Digest))
+	}
+
+	return fmt.Errorf(
+		"%d of %d digests could not be deleted: %s",
+		len(failed), len(r.Deletable), strings.Join(refs, ", "),
+	)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention_test
+
+import (
+	"testing"
+	"time"
+
+	cr "github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
+	"sigs.k8s.io/promo-tools/v4/promoter/image/retention"
+	"sigs.k8s.io/promo-tools/v4/types/image"
+)
+
+const (
+	digestA = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	digestB = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	digestC = "sha256:cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc"
+	digestD = "sha256:dddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddddd"
+	digestE = "sha256:eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	digestF = "sha256:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	digest0 = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	digest1 = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+)
+
+var now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) registry.ManifestDetails {
+	return registry.ManifestDetails{Uploaded: now.AddDate(0, 0, -days)}
+}
+
+func testReport() *retention.Report {
+	index := daysAgo(200)
+	index.MediaType = cr.OCIImageIndex
+
+	return retention.Plan(
+		retention.Policy{KeepTags: 1, KeepDays: 30},
+		"gcr.io/staging",
+		registry.RegInvImage{
+			"foo": {
+				digestA: {"v1.0"}, // promoted
+				digestB: {"v1.1"}, // newest tag
+				digestC: {"v0.9"}, // old tag
+				digestD: {},       // recent
+				digestE: {},       // old
+				digestF: {},       // unknown upload time
+				digest0: {"v0.1"}, // old index of digest1
+				digest1: {},       // child of the old index
+				"sha256:2222222222222222222222222222222222222222222222222222222222222222": {
+					"sha256-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa.sig",
+				},
+			},
+		},
+		map[image.Digest]registry.ManifestDetails{
+			digestA: daysAgo(400),
+			digestB: daysAgo(100),
+			digestC: daysAgo(300),
+			digestD: daysAgo(10),
+			digestE: daysAgo(60),
+			digest0: index,
+			digest1: daysAgo(200),
+			"sha256:2222222222222222222222222222222222222222222222222222222222222222": daysAgo(400),
+		},
+		map[image.Digest]bool{digestA: true},
+		map[image.Digest][]image.Digest{digest0: {digest1}},
+		now,
+	)
+}
+
+func TestPlan(t *testing.T) {
+	report := testReport()
+
+	deletable := []image.Digest{}
+	for i := range report.Deletable {
+		deletable = append(deletable, report.Deletable[i].Digest)
+	}
+
+	require.Equal(t, []image.Digest{digest0, digest1, digestC, digestE}, deletable)
+	require.Equal(t, 5, report.Kept)
+	require.True(t, report.Deletable[0].IsIndex())
+	require.Equal(t, registry.TagSlice{"v0.1"}, report.Deletable[0].Tags)
+	require.NotNil(t, report.Deletable[1].Uploaded)
+	require.NoError(t, report.Err())
+}
+
+func TestPlanKeepsIndexChildren(t *testing.T) {
+	report := retention.Plan(
+		retention.Policy{},
+		"gcr.io/staging",
+		registry.RegInvImage{"foo": {digest0: {"v1.0"}, digest1: {}}},
+		map[image.Digest]registry.ManifestDetails{digest0: daysAgo(10), digest1: daysAgo(10)},
+		map[image.Digest]bool{digest0: true},
+		map[image.Digest][]image.Digest{digest0: {digest1}},
+		now,
+	)
+
+	require.Empty(t, report.Deletable)
+	require.Equal(t, 2, report.Kept)
+}
+
+func TestErr(t *testing.T) {
+	report := testReport()
+	report.Deletable[0].Error = "denied"
+
+	require.ErrorContains(t, report.Err(), "1 of 4 digests could not be deleted: gcr.io/staging/foo@"+digest0)
+	require.Len(t, report.Failed(), 1)
+}
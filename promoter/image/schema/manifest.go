@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -41,6 +42,11 @@ type Manifest struct {
 	Registries []registry.Context `yaml:"registries,omitempty"`
 	Images     []registry.Image   `yaml:"images,omitempty"`
 
+	// StagingSignatures declares the identities expected to have signed
+	// the images in the source registry. When nil, the promoter verifies
+	// staging signatures against its globally configured identity.
+	StagingSignatures *SignaturePolicy `yaml:"stagingSignatures,omitempty"`
+
 	// Hidden fields; these are data structure optimizations that are populated
 	// from the fields above. As they are redundant, there is no point in
 	// storing this information in YAML.
@@ -56,6 +62,11 @@ type Manifest struct {
 // src/destination repos or the credentials tied to them.
 type ThinManifest struct {
 	Registries []registry.Context `yaml:"registries,omitempty"`
+
+	// StagingSignatures declares the identities expected to have signed
+	// the staging images of this subproject.
+	StagingSignatures *SignaturePolicy `yaml:"stagingSignatures,omitempty"`
+
 	// Store actual image data somewhere else.
 	//
 	// NOTE: "ImagesPath" is deprecated. It does nothing and will be
@@ -110,6 +121,10 @@ func (m *Manifest) Validate() error {
 		return err
 	}
 
+	if err := m.StagingSignatures.Validate(); err != nil {
+		return fmt.Errorf("validating staging signature policy: %w", err)
+	}
+
 	return validateImages(m.Images)
 }
 
@@ -130,13 +145,37 @@ func validateRequiredComponents(m *Manifest) error {
 		errs = append(errs, "'registries' field cannot be empty")
 	}
 
-	for _, registry := range m.Registries {
-		if len(registry.Name) == 0 {
+	for _, rc := range m.Registries {
+		if len(rc.Name) == 0 {
 			errs = append(
 				errs,
 				"registries: 'name' field cannot be empty",
 			)
 		}
+
+		if rc.API != "" && !slices.Contains(registry.APIs, rc.API) {
+			errs = append(
+				errs,
+				fmt.Sprintf(
+					"registries: invalid 'api' %q of %s, must be one of: %s",
+					rc.API, rc.Name, strings.Join(registry.APIs, ", "),
+				),
+			)
+		}
+
+		if err := rc.Credentials.Validate(); err != nil {
+			errs = append(
+				errs,
+				fmt.Sprintf("registries: invalid 'credentials' of %s: %v", rc.Name, err),
+			)
+		}
+
+		if err := rc.Transport.Validate(); err != nil {
+			errs = append(
+				errs,
+				fmt.Sprintf("registries: invalid 'transport' of %s: %v", rc.Name, err),
+			)
+		}
 	}
 
 	for _, img := range m.Images {
@@ -548,6 +587,7 @@ func ParseThinManifestFromFile(filePath string, digestsToCheck []string) (Manife
 	mfest.Filepath = filePath
 	mfest.Images = images
 	mfest.Registries = thinManifest.Registries
+	mfest.StagingSignatures = thinManifest.StagingSignatures
 
 	err = mfest.Finalize()
 	if err != nil {
@@ -579,6 +619,10 @@ func ParseThinManifestYAML(b []byte) (ThinManifest, error) {
 		return m, fmt.Errorf("unmarshalling thin manifest YAML: %w", err)
 	}
 
+	if err := m.StagingSignatures.Validate(); err != nil {
+		return m, fmt.Errorf("validating staging signature policy: %w", err)
+	}
+
 	return m, nil
 }
 
@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/release-utils/command"
+
+	"sigs.k8s.io/promo-tools/v4/promoter/image/registry"
 )
 
 func TestParseThinManifestsFromDirPostsubmit(t *testing.T) {
@@ -139,3 +141,81 @@ func TestDiffSinceFilesNoChanges(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, digests)
 }
+
+func TestValidateRegistryAPI(t *testing.T) {
+	t.Parallel()
+
+	m := Manifest{
+		Registries: []registry.Context{
+			{Name: "ghcr.io/staging", Src: true, API: registry.APIOCI},
+			{Name: "ghcr.io/prod", API: "docker"},
+		},
+		Images: []registry.Image{{
+			Name: "foo",
+			Dmap: registry.DigestTags{
+				"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": {"v1.0"},
+			},
+		}},
+	}
+
+	require.ErrorContains(t, m.Validate(), `invalid 'api' "docker" of ghcr.io/prod`)
+
+	m.Registries[1].API = registry.APIGoogle
+	require.NoError(t, m.Validate())
+}
+
+func TestValidateManifestCredentials(t *testing.T) {
+	t.Parallel()
+
+	m := Manifest{
+		Registries: []registry.Context{
+			{Name: "ghcr.io/staging", Src: true},
+			{Name: "ghcr.io/prod", Credentials: &registry.Credentials{
+				TokenEnv:     "GHCR_TOKEN",
+				PasswordFile: "/var/run/secrets/ghcr",
+			}},
+		},
+		Images: []registry.Image{{
+			Name: "foo",
+			Dmap: registry.DigestTags{
+				"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": {"v1.0"},
+			},
+		}},
+	}
+
+	require.ErrorContains(t, m.Validate(), "invalid 'credentials' of ghcr.io/prod")
+
+	m.Registries[1].Credentials.PasswordFile = ""
+	require.NoError(t, m.Validate())
+}
+
+func TestValidateManifestTransport(t *testing.T) {
+	t.Parallel()
+
+	m := Manifest{
+		Registries: []registry.Context{
+			{Name: "localhost:5000/staging", Src: true, Transport: &registry.TransportConfig{
+				PlainHTTP: true,
+				Insecure:  true,
+			}},
+			{Name: "registry.example.com/prod", Transport: &registry.TransportConfig{
+				CABundle:   "/etc/ssl/example.pem",
+				ClientCert: "/etc/ssl/client.pem",
+			}},
+		},
+		Images: []registry.Image{{
+			Name: "foo",
+			Dmap: registry.DigestTags{
+				"sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa": {"v1.0"},
+			},
+		}},
+	}
+
+	err := m.Validate()
+	require.ErrorContains(t, err, "invalid 'transport' of localhost:5000/staging: plainHTTP cannot be combined")
+	require.ErrorContains(t, err, "invalid 'transport' of registry.example.com/prod: clientCert and clientKey")
+
+	m.Registries[0].Transport.Insecure = false
+	m.Registries[1].Transport.ClientKey = "/etc/ssl/client-key.pem"
+	require.NoError(t, m.Validate())
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// SignatureMode controls how the promoter treats staging images that do not
+// carry a signature from one of the trusted signers of a manifest.
+type SignatureMode string
+
+const (
+	// SignatureModeOptional verifies staging signatures when present, but
+	// lets unsigned images through. This is the default.
+	SignatureModeOptional SignatureMode = "optional"
+
+	// SignatureModeRequired blocks the promotion of any staging image that
+	// is not signed by one of the trusted signers.
+	SignatureModeRequired SignatureMode = "required"
+)
+
+// SignaturePolicy describes who is expected to sign the staging images of a
+// manifest. Subprojects build their images with different CI identities, so
+// each thin manifest can declare its own trusted signers. When a manifest
+// does not define any signers, the global identity configured in the
+// promoter options is used.
+type SignaturePolicy struct {
+	// Mode is either "optional" (default) or "required".
+	Mode SignatureMode `yaml:"mode,omitempty"`
+
+	// Signers lists the identities trusted to sign the staging images.
+	// A signature from any of them is accepted.
+	Signers []Signer `yaml:"signers,omitempty"`
+}
+
+// Signer is a keyless signing identity as recorded in the Fulcio
+// certificate of a signature.
+type Signer struct {
+	// Identity is the expected certificate subject (SAN), usually the
+	// e-mail of a service account or a CI workflow URL.
+	Identity string `yaml:"identity,omitempty"`
+
+	// IdentityRegexp is a regular expression alternative to Identity.
+	IdentityRegexp string `yaml:"identityRegexp,omitempty"`
+
+	// Issuer is the OIDC issuer of the token used to obtain the certificate.
+	Issuer string `yaml:"issuer,omitempty"`
+
+	// IssuerRegexp is a regular expression alternative to Issuer.
+	IssuerRegexp string `yaml:"issuerRegexp,omitempty"`
+}
+
+// IsRequired returns true if unsigned staging images must be rejected.
+func (p *SignaturePolicy) IsRequired() bool {
+	return p != nil && p.Mode == SignatureModeRequired
+}
+
+// Validate checks the policy for semantic errors.
+func (p *SignaturePolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+
+	switch p.Mode {
+	case "", SignatureModeOptional, SignatureModeRequired:
+	default:
+		return fmt.Errorf(
+			"invalid signature mode %q (must be %q or %q)",
+			p.Mode, SignatureModeOptional, SignatureModeRequired,
+		)
+	}
+
+	for i := range p.Signers {
+		if err := p.Signers[i].Validate(); err != nil {
+			return fmt.Errorf("signer #%d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the signer defines both an identity and an issuer
+// and that any regular expressions compile.
+func (s *Signer) Validate() error {
+	if s.Identity == "" && s.IdentityRegexp == "" {
+		return errors.New("either identity or identityRegexp must be set")
+	}
+
+	if s.Issuer == "" && s.IssuerRegexp == "" {
+		return errors.New("either issuer or issuerRegexp must be set")
+	}
+
+	for _, re := range []string{s.IdentityRegexp, s.IssuerRegexp} {
+		if re == "" {
+			continue
+		}
+
+		if _, err := regexp.Compile(re); err != nil {
+			return fmt.Errorf("compiling %q: %w", re, err)
+		}
+	}
+
+	return nil
+}
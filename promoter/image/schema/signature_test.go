@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThinManifestYAMLSignaturePolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		yaml      string
+		shouldErr bool
+		assert    func(*testing.T, ThinManifest)
+	}{
+		{
+			name: "no policy",
+			yaml: `registries:
+- name: gcr.io/staging
+  src: true
+- name: gcr.io/prod
+`,
+			assert: func(t *testing.T, m ThinManifest) {
+				t.Helper()
+				require.Nil(t, m.StagingSignatures)
+				require.False(t, m.StagingSignatures.IsRequired())
+			},
+		},
+		{
+			name: "required policy with signers",
+			yaml: `registries:
+- name: gcr.io/staging
+  src: true
+- name: gcr.io/prod
+stagingSignatures:
+  mode: required
+  signers:
+  - identity: builder@staging.iam.gserviceaccount.com
+    issuer: https://accounts.google.com
+  - identityRegexp: ^https://github.com/kubernetes-sigs/.*$
+    issuer: https://token.actions.githubusercontent.com
+`,
+			assert: func(t *testing.T, m ThinManifest) {
+				t.Helper()
+				require.True(t, m.StagingSignatures.IsRequired())
+				require.Len(t, m.StagingSignatures.Signers, 2)
+				require.Equal(t, "builder@staging.iam.gserviceaccount.com", m.StagingSignatures.Signers[0].Identity)
+				require.Equal(t, "^https://github.com/kubernetes-sigs/.*$", m.StagingSignatures.Signers[1].IdentityRegexp)
+			},
+		},
+		{
+			name: "invalid mode",
+			yaml: `stagingSignatures:
+  mode: sometimes
+`,
+			shouldErr: true,
+		},
+		{
+			name: "signer without issuer",
+			yaml: `stagingSignatures:
+  signers:
+  - identity: builder@staging.iam.gserviceaccount.com
+`,
+			shouldErr: true,
+		},
+		{
+			name: "signer with invalid regexp",
+			yaml: `stagingSignatures:
+  signers:
+  - identityRegexp: "("
+    issuer: https://accounts.google.com
+`,
+			shouldErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, err := ParseThinManifestYAML([]byte(tc.yaml))
+			if tc.shouldErr {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			tc.assert(t, m)
+		})
+	}
+}
@@ -0,0 +1,381 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"sigs.k8s.io/yaml"
+)
+
+// Exception accepts a triaged vulnerability in a set of images until it
+// expires.
+type Exception struct {
+	// ID is the vulnerability identifier, e.g. "CVE-2024-1234".
+	ID string `json:"id"`
+
+	// Images are the image repositories the exception applies to, e.g.
+	// "gcr.io/k8s-staging-foo/bar". A repository path without registry,
+	// e.g. "bar" or "foo/bar", matches that path in any registry, and
+	// "*" matches all images.
+	Images []string `json:"images,omitempty"`
+
+	// Digests are the image digests the exception applies to.
+	Digests []string `json:"digests,omitempty"`
+
+	// Justification explains why the vulnerability is accepted.
+	Justification string `json:"justification"`
+
+	// Expires is the last day the exception applies, as YYYY-MM-DD (UTC)
+	// or an RFC 3339 timestamp. Exceptions read from OpenVEX documents
+	// do not expire.
+	Expires string `json:"expires,omitempty"`
+
+	// Source is the file the exception was read from.
+	Source string `json:"-"`
+
+	expiry time.Time
+}
+
+// ExceptionsFile is the format of a vulnerability exceptions file.
+type ExceptionsFile struct {
+	Exceptions []Exception `json:"exceptions"`
+}
+
+// Exceptions is a list of vulnerability exceptions.
+type Exceptions []Exception
+
+// AppliedException records a vulnerability ignored because of an exception.
+type AppliedException struct {
+	Vulnerability Vulnerability `json:"vulnerability"`
+	Exception     Exception     `json:"exception"`
+}
+
+// LoadExceptions reads vulnerability exceptions from a list of files. Each
+// file is either an exceptions file in YAML or JSON, or an OpenVEX document.
+func LoadExceptions(paths ...string) (Exceptions, error) {
+	exceptions := Exceptions{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading exceptions file: %w", err)
+		}
+
+		parsed, err := ParseExceptions(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing exceptions file %s: %w", path, err)
+		}
+
+		for i := range parsed {
+			parsed[i].Source = path
+		}
+
+		exceptions = append(exceptions, parsed...)
+	}
+
+	return exceptions, nil
+}
+
+// ParseExceptions parses an exceptions file or an OpenVEX document.
+func ParseExceptions(data []byte) (Exceptions, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("converting to JSON: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(jsonData, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshaling exceptions: %w", err)
+	}
+
+	if _, ok := fields["@context"]; ok {
+		return parseOpenVEX(jsonData)
+	}
+
+	file := &ExceptionsFile{}
+	if err := yaml.UnmarshalStrict(data, file); err != nil {
+		return nil, fmt.Errorf("unmarshaling exceptions: %w", err)
+	}
+
+	for i := range file.Exceptions {
+		if err := file.Exceptions[i].validate(); err != nil {
+			return nil, fmt.Errorf("exception #%d: %w", i, err)
+		}
+	}
+
+	return file.Exceptions, nil
+}
+
+// validate checks the exception and parses its expiry date.
+func (e *Exception) validate() error {
+	if e.ID == "" {
+		return errors.New("id must be set")
+	}
+
+	if len(e.Images) == 0 && len(e.Digests) == 0 {
+		return fmt.Errorf("%s: images or digests must be set", e.ID)
+	}
+
+	if e.Justification == "" {
+		return fmt.Errorf("%s: justification must be set", e.ID)
+	}
+
+	if e.Expires == "" {
+		return fmt.Errorf("%s: expires must be set", e.ID)
+	}
+
+	if t, err := time.Parse(time.DateOnly, e.Expires); err == nil {
+		// Date-only expiries include the whole day
+		e.expiry = t.AddDate(0, 0, 1)
+
+		return nil
+	}
+
+	t, err := time.Parse(time.RFC3339, e.Expires)
+	if err != nil {
+		return fmt.Errorf("%s: invalid expiry %q, expected YYYY-MM-DD or RFC 3339", e.ID, e.Expires)
+	}
+
+	e.expiry = t
+
+	return nil
+}
+
+// Expired returns true if the exception no longer applies at the given time.
+func (e *Exception) Expired(now time.Time) bool {
+	return !e.expiry.IsZero() && !now.Before(e.expiry)
+}
+
+// Matches returns true if the exception covers the vulnerability in the
+// image, regardless of its expiry.
+func (e *Exception) Matches(ref, vulnID string) bool {
+	if !strings.EqualFold(e.ID, vulnID) {
+		return false
+	}
+
+	repo, digest, _ := strings.Cut(ref, "@")
+
+	if digest != "" && slices.Contains(e.Digests, digest) {
+		return true
+	}
+
+	for _, img := range e.Images {
+		if img == "*" || repositoryMatches(repo, img) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// repositoryMatches checks if an image repository matches a repository
+// from an exception, which may omit the registry.
+func repositoryMatches(repo, pattern string) bool {
+	pattern = strings.TrimRight(pattern, "/")
+
+	return repo == pattern || strings.HasSuffix(repo, "/"+pattern)
+}
+
+// Apply removes the vulnerabilities covered by an active exception from a
+// scan result and records them in its Exceptions. It returns the filtered
+// result and the expired exceptions that would have matched a
+// vulnerability.
+func (l Exceptions) Apply(result *ScanResult, now time.Time) (filtered *ScanResult, expired []AppliedException) {
+	filtered = &ScanResult{Reference: result.Reference, Exceptions: result.Exceptions}
+
+	for _, v := range result.Vulnerabilities {
+		var match, expiredMatch *Exception
+
+		for i := range l {
+			if !l[i].Matches(result.Reference, v.ID) {
+				continue
+			}
+
+			if l[i].Expired(now) {
+				expiredMatch = &l[i]
+
+				continue
+			}
+
+			match = &l[i]
+
+			break
+		}
+
+		if match != nil {
+			filtered.Exceptions = append(filtered.Exceptions, AppliedException{Vulnerability: v, Exception: *match})
+
+			continue
+		}
+
+		if expiredMatch != nil {
+			expired = append(expired, AppliedException{Vulnerability: v, Exception: *expiredMatch})
+		}
+
+		filtered.Vulnerabilities = append(filtered.Vulnerabilities, v)
+		if v.Severity > filtered.HighestSeverity {
+			filtered.HighestSeverity = v.Severity
+		}
+	}
+
+	return filtered, expired
+}
+
+// openVEXDocument is the subset of the OpenVEX format we use. The
+// vulnerability is a string in OpenVEX v0.0.x and an object in v0.2.
+type openVEXDocument struct {
+	Statements []struct {
+		Vulnerability json.RawMessage   `json:"vulnerability"`
+		Products      []json.RawMessage `json:"products"`
+		Status        string            `json:"status"`
+		Justification string            `json:"justification"`
+		Impact        string            `json:"impact_statement"`
+	} `json:"statements"`
+}
+
+// parseOpenVEX turns the not_affected and fixed statements of an OpenVEX
+// document into exceptions.
+func parseOpenVEX(data []byte) (Exceptions, error) {
+	doc := &openVEXDocument{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("unmarshaling OpenVEX document: %w", err)
+	}
+
+	exceptions := Exceptions{}
+
+	for i, st := range doc.Statements {
+		if st.Status != "not_affected" && st.Status != "fixed" {
+			continue
+		}
+
+		id, err := openVEXVulnerabilityID(st.Vulnerability)
+		if err != nil {
+			return nil, fmt.Errorf("statement #%d: %w", i, err)
+		}
+
+		e := Exception{ID: id, Justification: st.Status}
+		for _, detail := range []string{st.Justification, st.Impact} {
+			if detail != "" {
+				e.Justification += ": " + detail
+			}
+		}
+
+		for _, p := range st.Products {
+			product, err := openVEXProductID(p)
+			if err != nil {
+				return nil, fmt.Errorf("statement #%d: %w", i, err)
+			}
+
+			repo, digest := parseProduct(product)
+			if repo != "" {
+				e.Images = append(e.Images, repo)
+			}
+
+			if digest != "" {
+				e.Digests = append(e.Digests, digest)
+			}
+		}
+
+		if len(e.Images) == 0 && len(e.Digests) == 0 {
+			return nil, fmt.Errorf("statement #%d: %s: no products", i, id)
+		}
+
+		// A product with a digest only applies to that digest
+		if len(e.Digests) > 0 {
+			e.Images = nil
+		}
+
+		exceptions = append(exceptions, e)
+	}
+
+	return exceptions, nil
+}
+
+func openVEXVulnerabilityID(raw json.RawMessage) (string, error) {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil && id != "" {
+		return id, nil
+	}
+
+	var v struct {
+		Name string `json:"name"`
+		ID   string `json:"@id"`
+	}
+	if err := json.Unmarshal(raw, &v); err == nil {
+		if v.Name != "" {
+			return v.Name, nil
+		}
+
+		if v.ID != "" {
+			return v.ID, nil
+		}
+	}
+
+	return "", errors.New("statement without vulnerability")
+}
+
+func openVEXProductID(raw json.RawMessage) (string, error) {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil && id != "" {
+		return id, nil
+	}
+
+	var p struct {
+		ID string `json:"@id"`
+	}
+	if err := json.Unmarshal(raw, &p); err == nil && p.ID != "" {
+		return p.ID, nil
+	}
+
+	return "", errors.New("product without @id")
+}
+
+// parseProduct returns the repository and digest of an OpenVEX product,
+// either an OCI package URL or an image reference.
+func parseProduct(product string) (repo, digest string) {
+	if rest, ok := strings.CutPrefix(product, "pkg:oci/"); ok {
+		rest, query, _ := strings.Cut(rest, "?")
+		pkgName, version, _ := strings.Cut(rest, "@")
+
+		if v, err := url.PathUnescape(version); err == nil {
+			digest = v
+		}
+
+		repo = pkgName
+
+		if q, err := url.ParseQuery(query); err == nil && q.Get("repository_url") != "" {
+			repo = q.Get("repository_url")
+		}
+
+		return repo, digest
+	}
+
+	if d, err := name.NewDigest(product, name.WeakValidation); err == nil {
+		return d.Context().Name(), d.DigestStr()
+	}
+
+	return product, ""
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testDigest  = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	otherDigest = "sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+
+	testExceptions = `exceptions:
+- id: CVE-2024-0001
+  images: ["k8s-staging-foo/bar"]
+  justification: openssl is not used at runtime
+  expires: "2026-06-30"
+- id: CVE-2024-0002
+  digests: ["` + testDigest + `"]
+  justification: fixed in the next release
+  expires: "2026-01-31T12:00:00Z"
+`
+
+	testOpenVEX = `{
+  "@context": "https://openvex.dev/ns/v0.2.0",
+  "@id": "https://example.com/vex/1",
+  "author": "SIG Foo",
+  "timestamp": "2026-01-01T00:00:00Z",
+  "version": 1,
+  "statements": [
+    {
+      "vulnerability": {"name": "CVE-2024-0003"},
+      "products": [{"@id": "pkg:oci/bar@sha256%3Aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa?repository_url=gcr.io/k8s-staging-foo/bar"}],
+      "status": "not_affected",
+      "justification": "vulnerable_code_not_in_execute_path"
+    },
+    {
+      "vulnerability": "CVE-2024-0004",
+      "products": ["gcr.io/k8s-staging-foo/baz"],
+      "status": "fixed"
+    },
+    {
+      "vulnerability": {"name": "CVE-2024-0005"},
+      "products": [{"@id": "gcr.io/k8s-staging-foo/bar"}],
+      "status": "affected"
+    }
+  ]
+}`
+)
+
+func TestParseExceptions(t *testing.T) {
+	exceptions, err := ParseExceptions([]byte(testExceptions))
+	require.NoError(t, err)
+	require.Len(t, exceptions, 2)
+	require.Equal(t, "CVE-2024-0001", exceptions[0].ID)
+	require.Equal(t, []string{"k8s-staging-foo/bar"}, exceptions[0].Images)
+
+	// Date-only expiries include the whole day
+	require.False(t, exceptions[0].Expired(time.Date(2026, 6, 30, 23, 59, 0, 0, time.UTC)))
+	require.True(t, exceptions[0].Expired(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)))
+	require.True(t, exceptions[1].Expired(time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)))
+
+	for _, invalid := range []string{
+		`exceptions: [{id: CVE-1, images: [bar], justification: j}]`,
+		`exceptions: [{id: CVE-1, images: [bar], expires: "2026-01-01"}]`,
+		`exceptions: [{id: CVE-1, justification: j, expires: "2026-01-01"}]`,
+		`exceptions: [{images: [bar], justification: j, expires: "2026-01-01"}]`,
+		`exceptions: [{id: CVE-1, images: [bar], justification: j, expires: "next week"}]`,
+		`exceptions: [{id: CVE-1, image: bar, justification: j, expires: "2026-01-01"}]`,
+	} {
+		_, err := ParseExceptions([]byte(invalid))
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestParseOpenVEX(t *testing.T) {
+	exceptions, err := ParseExceptions([]byte(testOpenVEX))
+	require.NoError(t, err)
+	require.Equal(t, Exceptions{
+		{
+			ID:            "CVE-2024-0003",
+			Digests:       []string{testDigest},
+			Justification: "not_affected: vulnerable_code_not_in_execute_path",
+		},
+		{
+			ID:            "CVE-2024-0004",
+			Images:        []string{"gcr.io/k8s-staging-foo/baz"},
+			Justification: "fixed",
+		},
+	}, exceptions)
+
+	// OpenVEX statements do not expire
+	require.False(t, exceptions[0].Expired(time.Now().AddDate(100, 0, 0)))
+}
+
+func TestExceptionMatches(t *testing.T) {
+	e := &Exception{ID: "CVE-2024-0001", Images: []string{"k8s-staging-foo/bar"}, Digests: []string{otherDigest}}
+
+	require.True(t, e.Matches("gcr.io/k8s-staging-foo/bar@"+testDigest, "CVE-2024-0001"))
+	require.True(t, e.Matches("gcr.io/k8s-staging-foo/bar@"+testDigest, "cve-2024-0001"))
+	require.True(t, e.Matches("gcr.io/k8s-staging-qux/baz@"+otherDigest, "CVE-2024-0001"))
+	require.False(t, e.Matches("gcr.io/k8s-staging-foo/bar@"+testDigest, "CVE-2024-0002"))
+	require.False(t, e.Matches("gcr.io/k8s-staging-foo/foobar@"+testDigest, "CVE-2024-0001"))
+	require.False(t, e.Matches("gcr.io/k8s-staging-foo/bar/sub@"+testDigest, "CVE-2024-0001"))
+
+	all := &Exception{ID: "CVE-2024-0001", Images: []string{"*"}}
+	require.True(t, all.Matches("gcr.io/anything/else@"+testDigest, "CVE-2024-0001"))
+}
+
+func TestExceptionsApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exceptions.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testExceptions), 0o600))
+
+	exceptions, err := LoadExceptions(path)
+	require.NoError(t, err)
+
+	result := &ScanResult{
+		Reference: "gcr.io/k8s-staging-foo/bar@" + testDigest,
+		Vulnerabilities: []Vulnerability{
+			{ID: "CVE-2024-0001", Severity: SeverityCritical},
+			{ID: "CVE-2024-0002", Severity: SeverityHigh},
+			{ID: "CVE-2024-0009", Severity: SeverityLow},
+		},
+		HighestSeverity: SeverityCritical,
+	}
+
+	filtered, expired := exceptions.Apply(result, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	// CVE-2024-0001 is accepted, the exception for CVE-2024-0002 expired
+	require.Equal(t, []Vulnerability{
+		{ID: "CVE-2024-0002", Severity: SeverityHigh},
+		{ID: "CVE-2024-0009", Severity: SeverityLow},
+	}, filtered.Vulnerabilities)
+	require.Equal(t, SeverityHigh, filtered.HighestSeverity)
+
+	require.Len(t, filtered.Exceptions, 1)
+	require.Equal(t, "CVE-2024-0001", filtered.Exceptions[0].Vulnerability.ID)
+	require.Equal(t, path, filtered.Exceptions[0].Exception.Source)
+
+	require.Len(t, expired, 1)
+	require.Equal(t, "CVE-2024-0002", expired[0].Vulnerability.ID)
+
+	// The scan result itself is not modified
+	require.Len(t, result.Vulnerabilities, 3)
+}
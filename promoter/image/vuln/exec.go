@@ -0,0 +1,201 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// ExecReferenceEnv is the environment variable holding the image
+	// reference passed to exec scanner plugins.
+	ExecReferenceEnv = "KPROMO_SCAN_REFERENCE"
+
+	// ExecSchemaVersion is the version of the JSON document exec scanner
+	// plugins write to stdout.
+	ExecSchemaVersion = 1
+
+	// DefaultExecTimeout is the time an exec scanner plugin gets to scan
+	// an image when ExecScanner.Timeout is not set.
+	DefaultExecTimeout = 10 * time.Minute
+
+	execWaitDelay = 5 * time.Second
+)
+
+// ExecScanner implements Scanner by running an external command, the
+// plugin, for each image. The plugin receives the image reference as its
+// last argument and in the KPROMO_SCAN_REFERENCE environment variable, and
+// must write an ExecResult JSON document to stdout and exit with status 0.
+// A non-zero exit status is a failed scan, not a vulnerable image.
+type ExecScanner struct {
+	// Command is the plugin executable.
+	Command string
+
+	// Args are passed to the plugin before the image reference.
+	Args []string
+
+	// Timeout is the maximum duration of a single scan. Defaults to
+	// DefaultExecTimeout.
+	Timeout time.Duration
+
+	// MaxConcurrent limits the number of plugin processes running at the
+	// same time. Zero or less means unlimited.
+	MaxConcurrent int
+
+	once sync.Once
+	sem  chan struct{}
+}
+
+// ExecResult is the document exec scanner plugins write to stdout.
+type ExecResult struct {
+	// SchemaVersion is the version of the document, currently 1. It may
+	// be omitted.
+	SchemaVersion int `json:"schemaVersion,omitempty"`
+
+	// Vulnerabilities lists the vulnerabilities found in the image.
+	Vulnerabilities []ExecVulnerability `json:"vulnerabilities"`
+}
+
+// ExecVulnerability is a vulnerability reported by an exec scanner plugin.
+type ExecVulnerability struct {
+	ID string `json:"id"`
+
+	// Severity is one of UNSPECIFIED, MINIMAL, LOW, MEDIUM, HIGH or
+	// CRITICAL (case insensitive). The Trivy and Grype names are also
+	// accepted, anything else maps to SeverityUnspecified.
+	Severity         string `json:"severity"`
+	Package          string `json:"package,omitempty"`
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	FixedVersion     string `json:"fixedVersion,omitempty"`
+	Description      string `json:"description,omitempty"`
+}
+
+// Scan runs the plugin for ref and parses its output.
+func (s *ExecScanner) Scan(ctx context.Context, ref string) (*ScanResult, error) {
+	if s.Command == "" {
+		return nil, errors.New("no scanner command configured")
+	}
+
+	if err := s.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("waiting to scan %s: %w", ref, err)
+	}
+	defer s.release()
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExecTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	args := append(append([]string{}, s.Args...), ref)
+
+	//nolint:gosec // running the configured plugin is the purpose of this scanner
+	cmd := exec.CommandContext(ctx, s.Command, args...)
+	cmd.Env = append(os.Environ(), ExecReferenceEnv+"="+ref)
+
+	// Do not wait for processes started by the plugin which keep the
+	// output pipes open after the plugin itself was killed.
+	cmd.WaitDelay = execWaitDelay
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("scanner command timed out after %s", timeout)
+		}
+
+		return nil, fmt.Errorf(
+			"running scanner command: %w: %s", err, strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	return ParseExecResult(ref, stdout.Bytes())
+}
+
+// ParseExecResult parses the output of an exec scanner plugin.
+func ParseExecResult(ref string, data []byte) (*ScanResult, error) {
+	res := &ExecResult{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("parsing scanner output: %w", err)
+	}
+
+	if res.SchemaVersion != 0 && res.SchemaVersion != ExecSchemaVersion {
+		return nil, fmt.Errorf("unsupported scanner output schema version %d", res.SchemaVersion)
+	}
+
+	result := &ScanResult{Reference: ref}
+
+	for _, v := range res.Vulnerabilities {
+		severity := ParseSeverity(v.Severity)
+
+		result.Vulnerabilities = append(result.Vulnerabilities, Vulnerability{
+			ID:               v.ID,
+			Severity:         severity,
+			Package:          v.Package,
+			InstalledVersion: v.InstalledVersion,
+			FixedVersion:     v.FixedVersion,
+			Description:      v.Description,
+		})
+
+		if severity > result.HighestSeverity {
+			result.HighestSeverity = severity
+		}
+	}
+
+	return result, nil
+}
+
+// acquire blocks until a plugin process can be started.
+func (s *ExecScanner) acquire(ctx context.Context) error {
+	s.once.Do(func() {
+		if s.MaxConcurrent > 0 {
+			s.sem = make(chan struct{}, s.MaxConcurrent)
+		}
+	})
+
+	if s.sem == nil {
+		return nil
+	}
+
+	select {
+	case s.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot taken by acquire.
+func (s *ExecScanner) release() {
+	if s.sem != nil {
+		<-s.sem
+	}
+}
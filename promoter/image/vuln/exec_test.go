@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+const testRef = "gcr.io/k8s-staging-foo/bar@sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+// shellScanner returns an ExecScanner running script with sh. The image
+// reference is available to the script as $1.
+func shellScanner(script string) *ExecScanner {
+	return &ExecScanner{Command: "sh", Args: []string{"-c", script, "scanner"}}
+}
+
+func TestExecScanner(t *testing.T) {
+	s := shellScanner(`test "$1" = "$KPROMO_SCAN_REFERENCE" || exit 3
+echo '{"schemaVersion": 1, "vulnerabilities": [
+  {"id": "CVE-2024-0001", "severity": "high", "package": "openssl", "installedVersion": "3.0.1", "fixedVersion": "3.0.2"},
+  {"id": "CVE-2024-0002", "severity": "Low", "package": "bash"}
+]}'`)
+
+	result, err := s.Scan(context.Background(), testRef)
+	require.NoError(t, err)
+	require.Equal(t, &ScanResult{
+		Reference: testRef,
+		Vulnerabilities: []Vulnerability{
+			{ID: "CVE-2024-0001", Severity: SeverityHigh, Package: "openssl", InstalledVersion: "3.0.1", FixedVersion: "3.0.2"},
+			{ID: "CVE-2024-0002", Severity: SeverityLow, Package: "bash"},
+		},
+		HighestSeverity: SeverityHigh,
+	}, result)
+}
+
+func TestExecScannerErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		scanner *ExecScanner
+		wantErr string
+	}{
+		{
+			name:    "no command",
+			scanner: &ExecScanner{},
+			wantErr: "no scanner command configured",
+		},
+		{
+			name:    "non-zero exit",
+			scanner: shellScanner(`echo "registry unreachable" >&2; exit 1`),
+			wantErr: "registry unreachable",
+		},
+		{
+			name:    "invalid output",
+			scanner: shellScanner(`echo "no vulnerabilities"`),
+			wantErr: "parsing scanner output",
+		},
+		{
+			name:    "unsupported schema version",
+			scanner: shellScanner(`echo '{"schemaVersion": 2, "vulnerabilities": []}'`),
+			wantErr: "unsupported scanner output schema version 2",
+		},
+		{
+			name: "timeout",
+			scanner: &ExecScanner{
+				Command: "sh", Args: []string{"-c", "exec sleep 5"}, Timeout: 100 * time.Millisecond,
+			},
+			wantErr: "timed out",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.scanner.Scan(context.Background(), testRef)
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestExecScannerMaxConcurrent(t *testing.T) {
+	// The script fails if another instance holds the lock directory
+	s := shellScanner(`mkdir "$LOCK" || exit 1
+sleep 0.05
+rmdir "$LOCK"
+echo '{"vulnerabilities": []}'`)
+	s.MaxConcurrent = 1
+
+	t.Setenv("LOCK", filepath.Join(t.TempDir(), "lock"))
+
+	g := new(errgroup.Group)
+	for range 4 {
+		g.Go(func() error {
+			_, err := s.Scan(context.Background(), testRef)
+
+			return err
+		})
+	}
+
+	require.NoError(t, g.Wait())
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// Artifact types of the vulnerability reports looked up as OCI referrers
+// when ReportScanner.ArtifactTypes is empty.
+const (
+	TrivyReportArtifactType = "application/vnd.aquasec.trivy.report+json"
+	GrypeReportArtifactType = "application/vnd.anchore.grype.report+json"
+)
+
+// ReportScanner implements Scanner by reading pre-generated Trivy or Grype
+// JSON reports instead of scanning the images itself. Reports are looked
+// up by the digest of the scanned image, first in Dir and then, if enabled,
+// among the OCI referrers of the image.
+type ReportScanner struct {
+	// Dir is a directory holding one report per image, named after the
+	// image digest with the colon replaced by a dash, e.g.
+	// "sha256-<hex>.json".
+	Dir string
+
+	// Referrers when true looks for reports attached to the scanned image
+	// as OCI referrers.
+	Referrers bool
+
+	// ArtifactTypes are the artifact types of the referrers holding
+	// reports. Defaults to the Trivy and Grype report artifact types.
+	ArtifactTypes []string
+
+	// RemoteOptions are used to fetch the referrers.
+	RemoteOptions []remote.Option
+}
+
+// Scan returns the vulnerabilities listed in the report of the image.
+// Images without a report are an error, so that a missing report never
+// lets an image through the vulnerability check.
+func (s *ReportScanner) Scan(ctx context.Context, ref string) (*ScanResult, error) {
+	digest, err := name.NewDigest(ref)
+	if err != nil {
+		return nil, fmt.Errorf("parsing digest reference %q: %w", ref, err)
+	}
+
+	data, err := s.readReport(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	vulns, err := ParseReport(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing vulnerability report of %s: %w", ref, err)
+	}
+
+	result := &ScanResult{Reference: ref, Vulnerabilities: vulns}
+	for _, v := range vulns {
+		if v.Severity > result.HighestSeverity {
+			result.HighestSeverity = v.Severity
+		}
+	}
+
+	return result, nil
+}
+
+// readReport returns the raw report of an image.
+func (s *ReportScanner) readReport(ctx context.Context, digest name.Digest) ([]byte, error) {
+	if s.Dir != "" {
+		path := filepath.Join(s.Dir, strings.ReplaceAll(digest.DigestStr(), ":", "-")+".json")
+
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return data, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading vulnerability report: %w", err)
+		}
+	}
+
+	if s.Referrers {
+		data, err := s.readReferrerReport(ctx, digest)
+		if err != nil {
+			return nil, fmt.Errorf("reading vulnerability report referrer: %w", err)
+		}
+
+		if data != nil {
+			return data, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no vulnerability report found for %s", digest)
+}
+
+// readReferrerReport returns the first layer of the first referrer of the
+// image with a report artifact type, or nil if there is none.
+func (s *ReportScanner) readReferrerReport(ctx context.Context, digest name.Digest) ([]byte, error) {
+	artifactTypes := s.ArtifactTypes
+	if len(artifactTypes) == 0 {
+		artifactTypes = []string{TrivyReportArtifactType, GrypeReportArtifactType}
+	}
+
+	opts := append(slices.Clone(s.RemoteOptions), remote.WithContext(ctx))
+
+	idx, err := remote.Referrers(digest, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("listing referrers: %w", err)
+	}
+
+	manifest, err := idx.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("reading referrers index: %w", err)
+	}
+
+	for i := range manifest.Manifests {
+		desc := &manifest.Manifests[i]
+		if !slices.Contains(artifactTypes, desc.ArtifactType) {
+			continue
+		}
+
+		img, err := remote.Image(digest.Context().Digest(desc.Digest.String()), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("fetching report %s: %w", desc.Digest, err)
+		}
+
+		layers, err := img.Layers()
+		if err != nil {
+			return nil, fmt.Errorf("reading report layers: %w", err)
+		}
+
+		if len(layers) == 0 {
+			continue
+		}
+
+		rc, err := layers[0].Uncompressed()
+		if err != nil {
+			return nil, fmt.Errorf("reading report layer: %w", err)
+		}
+
+		data, err := io.ReadAll(rc)
+		rc.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("reading report layer: %w", err)
+		}
+
+		return data, nil
+	}
+
+	return nil, nil
+}
+
+// trivyReport is the subset of the Trivy JSON report format we use.
+type trivyReport struct {
+	SchemaVersion int `json:"SchemaVersion"`
+	Results       []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+			Title            string `json:"Title"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// grypeReport is the subset of the Grype JSON report format we use.
+type grypeReport struct {
+	Matches []struct {
+		Vulnerability struct {
+			ID          string `json:"id"`
+			Severity    string `json:"severity"`
+			Description string `json:"description"`
+			Fix         struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+		} `json:"vulnerability"`
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifact"`
+	} `json:"matches"`
+}
+
+// ParseReport parses a Trivy or Grype JSON report. The format is detected
+// from the top level fields of the document.
+func ParseReport(data []byte) ([]Vulnerability, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unmarshaling report: %w", err)
+	}
+
+	if _, ok := fields["SchemaVersion"]; ok {
+		return parseTrivyReport(data)
+	}
+
+	if _, ok := fields["matches"]; ok {
+		return parseGrypeReport(data)
+	}
+
+	return nil, errors.New("unknown report format, expected a Trivy or Grype JSON report")
+}
+
+func parseTrivyReport(data []byte) ([]Vulnerability, error) {
+	report := &trivyReport{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("unmarshaling Trivy report: %w", err)
+	}
+
+	vulns := []Vulnerability{}
+
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			vulns = append(vulns, Vulnerability{
+				ID:               v.VulnerabilityID,
+				Severity:         ParseSeverity(v.Severity),
+				Package:          v.PkgName,
+				InstalledVersion: v.InstalledVersion,
+				FixedVersion:     v.FixedVersion,
+				Description:      v.Title,
+			})
+		}
+	}
+
+	return vulns, nil
+}
+
+func parseGrypeReport(data []byte) ([]Vulnerability, error) {
+	report := &grypeReport{}
+	if err := json.Unmarshal(data, report); err != nil {
+		return nil, fmt.Errorf("unmarshaling Grype report: %w", err)
+	}
+
+	vulns := make([]Vulnerability, 0, len(report.Matches))
+
+	for _, m := range report.Matches {
+		vulns = append(vulns, Vulnerability{
+			ID:               m.Vulnerability.ID,
+			Severity:         ParseSeverity(m.Vulnerability.Severity),
+			Package:          m.Artifact.Name,
+			InstalledVersion: m.Artifact.Version,
+			FixedVersion:     strings.Join(m.Vulnerability.Fix.Versions, ", "),
+			Description:      m.Vulnerability.Description,
+		})
+	}
+
+	return vulns, nil
+}
+
+// ParseSeverity maps the severity names used by Trivy and Grype onto
+// Severity. Unknown names map to SeverityUnspecified.
+func ParseSeverity(s string) Severity {
+	switch strings.ToUpper(s) {
+	case "NEGLIGIBLE", "MINIMAL":
+		return SeverityMinimal
+	case "LOW":
+		return SeverityLow
+	case "MEDIUM":
+		return SeverityMedium
+	case "HIGH":
+		return SeverityHigh
+	case "CRITICAL":
+		return SeverityCritical
+	default:
+		return SeverityUnspecified
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTrivyReport = `{
+  "SchemaVersion": 2,
+  "ArtifactName": "gcr.io/k8s-staging-foo/bar@sha256:abc",
+  "Results": [
+    {
+      "Target": "debian 12",
+      "Vulnerabilities": [
+        {
+          "VulnerabilityID": "CVE-2024-0001",
+          "PkgName": "openssl",
+          "InstalledVersion": "3.0.1",
+          "FixedVersion": "3.0.2",
+          "Severity": "HIGH",
+          "Title": "openssl: bad things"
+        }
+      ]
+    },
+    {
+      "Target": "app/go.mod",
+      "Vulnerabilities": [
+        {
+          "VulnerabilityID": "GHSA-xxxx-yyyy-zzzz",
+          "PkgName": "golang.org/x/net",
+          "InstalledVersion": "0.1.0",
+          "Severity": "MEDIUM",
+          "Title": "x/net: other things"
+        }
+      ]
+    },
+    {
+      "Target": "clean layer"
+    }
+  ]
+}`
+
+	testGrypeReport = `{
+  "matches": [
+    {
+      "vulnerability": {
+        "id": "CVE-2024-0002",
+        "severity": "Critical",
+        "description": "zlib: overflow",
+        "fix": {"versions": ["1.3.1", "1.2.14"], "state": "fixed"}
+      },
+      "artifact": {"name": "zlib", "version": "1.2.13"}
+    },
+    {
+      "vulnerability": {
+        "id": "CVE-2024-0003",
+        "severity": "Negligible",
+        "fix": {"versions": [], "state": "not-fixed"}
+      },
+      "artifact": {"name": "bash", "version": "5.2"}
+    }
+  ]
+}`
+)
+
+func TestParseReport(t *testing.T) {
+	vulns, err := ParseReport([]byte(testTrivyReport))
+	require.NoError(t, err)
+	require.Equal(t, []Vulnerability{
+		{
+			ID:               "CVE-2024-0001",
+			Severity:         SeverityHigh,
+			Package:          "openssl",
+			InstalledVersion: "3.0.1",
+			FixedVersion:     "3.0.2",
+			Description:      "openssl: bad things",
+		},
+		{
+			ID:               "GHSA-xxxx-yyyy-zzzz",
+			Severity:         SeverityMedium,
+			Package:          "golang.org/x/net",
+			InstalledVersion: "0.1.0",
+			Description:      "x/net: other things",
+		},
+	}, vulns)
+
+	vulns, err = ParseReport([]byte(testGrypeReport))
+	require.NoError(t, err)
+	require.Equal(t, []Vulnerability{
+		{
+			ID:               "CVE-2024-0002",
+			Severity:         SeverityCritical,
+			Package:          "zlib",
+			InstalledVersion: "1.2.13",
+			FixedVersion:     "1.3.1, 1.2.14",
+			Description:      "zlib: overflow",
+		},
+		{
+			ID:               "CVE-2024-0003",
+			Severity:         SeverityMinimal,
+			Package:          "bash",
+			InstalledVersion: "5.2",
+		},
+	}, vulns)
+
+	_, err = ParseReport([]byte(`{"foo": "bar"}`))
+	require.Error(t, err)
+
+	_, err = ParseReport([]byte(`not json`))
+	require.Error(t, err)
+}
+
+func TestParseSeverity(t *testing.T) {
+	for s, want := range map[string]Severity{
+		"UNKNOWN":    SeverityUnspecified,
+		"Negligible": SeverityMinimal,
+		"LOW":        SeverityLow,
+		"Medium":     SeverityMedium,
+		"HIGH":       SeverityHigh,
+		"Critical":   SeverityCritical,
+		"":           SeverityUnspecified,
+	} {
+		require.Equal(t, want, ParseSeverity(s), s)
+	}
+}
+
+func TestReportScannerDir(t *testing.T) {
+	const digest = "sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dir, strings.ReplaceAll(digest, ":", "-")+".json"),
+		[]byte(testTrivyReport), 0o600,
+	))
+
+	s := &ReportScanner{Dir: dir}
+
+	result, err := s.Scan(context.Background(), "gcr.io/k8s-staging-foo/bar@"+digest)
+	require.NoError(t, err)
+	require.Equal(t, SeverityHigh, result.HighestSeverity)
+	require.Len(t, result.Vulnerabilities, 2)
+
+	// Images without a report must not pass the scan
+	_, err = s.Scan(context.Background(),
+		"gcr.io/k8s-staging-foo/bar@sha256:bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+	)
+	require.Error(t, err)
+
+	// Scanned references need a digest
+	_, err = s.Scan(context.Background(), "gcr.io/k8s-staging-foo/bar:v1")
+	require.Error(t, err)
+}
+
+func TestReportScannerReferrers(t *testing.T) {
+	s := httptest.NewServer(registry.New(registry.WithReferrersSupport(true)))
+	t.Cleanup(s.Close)
+
+	repo, err := name.NewRepository(strings.TrimPrefix(s.URL, "http://") + "/staging/bar")
+	require.NoError(t, err)
+
+	img, err := random.Image(1024, 1)
+	require.NoError(t, err)
+
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(repo.Tag("v1"), img))
+
+	subject, err := partial.Descriptor(img)
+	require.NoError(t, err)
+
+	// Attach the Grype report as an OCI referrer artifact
+	report, err := mutate.AppendLayers(
+		mutate.MediaType(empty.Image, types.OCIManifestSchema1),
+		static.NewLayer([]byte(testGrypeReport), "application/json"),
+	)
+	require.NoError(t, err)
+
+	artifact, ok := mutate.Subject(
+		mutate.ConfigMediaType(report, GrypeReportArtifactType), *subject,
+	).(v1.Image)
+	require.True(t, ok)
+
+	artifactDigest, err := artifact.Digest()
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(repo.Digest(artifactDigest.String()), artifact))
+
+	scanner := &ReportScanner{Referrers: true}
+
+	result, err := scanner.Scan(context.Background(), repo.Digest(imgDigest.String()).String())
+	require.NoError(t, err)
+	require.Equal(t, SeverityCritical, result.HighestSeverity)
+	require.Len(t, result.Vulnerabilities, 2)
+
+	// Reports with other artifact types are ignored
+	scanner.ArtifactTypes = []string{TrivyReportArtifactType}
+	_, err = scanner.Scan(context.Background(), repo.Digest(imgDigest.String()).String())
+	require.Error(t, err)
+}
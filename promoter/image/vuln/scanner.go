@@ -22,6 +22,7 @@ package vuln
 
 import (
 	"context"
+	"fmt"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -38,6 +39,29 @@ const (
 	SeverityCritical
 )
 
+var severityNames = []string{"UNSPECIFIED", "MINIMAL", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// String returns the name of the severity, e.g. "HIGH".
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+
+	return severityNames[s]
+}
+
+// MarshalText encodes the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity name.
+func (s *Severity) UnmarshalText(text []byte) error {
+	*s = ParseSeverity(string(text))
+
+	return nil
+}
+
 // Scanner checks container images for known vulnerabilities.
 //
 //counterfeiter:generate . Scanner
@@ -51,34 +75,37 @@ type Scanner interface {
 // ScanResult holds the outcome of a vulnerability scan.
 type ScanResult struct {
 	// Reference is the image that was scanned.
-	Reference string
+	Reference string `json:"reference"`
 
 	// Vulnerabilities is the list of found vulnerabilities.
-	Vulnerabilities []Vulnerability
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
 
 	// HighestSeverity is the highest severity found across all vulnerabilities.
-	HighestSeverity Severity
+	HighestSeverity Severity `json:"highestSeverity"`
+
+	// Exceptions lists the vulnerabilities ignored because of an exception.
+	Exceptions []AppliedException `json:"exceptions,omitempty"`
 }
 
 // Vulnerability represents a single CVE or security issue.
 type Vulnerability struct {
 	// ID is the vulnerability identifier (e.g., "CVE-2024-1234").
-	ID string
+	ID string `json:"id"`
 
 	// Severity is the severity level.
-	Severity Severity
+	Severity Severity `json:"severity"`
 
 	// Package is the affected package name.
-	Package string
+	Package string `json:"package,omitempty"`
 
 	// InstalledVersion is the currently installed version.
-	InstalledVersion string
+	InstalledVersion string `json:"installedVersion,omitempty"`
 
 	// FixedVersion is the version that fixes this vulnerability, if available.
-	FixedVersion string
+	FixedVersion string `json:"fixedVersion,omitempty"`
 
 	// Description is a short description of the vulnerability.
-	Description string
+	Description string `json:"description,omitempty"`
 }
 
 // ExceedsSeverity returns true if the scan result contains any vulnerability
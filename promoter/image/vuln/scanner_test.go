@@ -107,4 +107,6 @@ func TestMapGrafeasSeverity(t *testing.T) {
 var (
 	_ Scanner = &NoopScanner{}
 	_ Scanner = &GrafeasScanner{}
+	_ Scanner = &ReportScanner{}
+	_ Scanner = &ExecScanner{}
 )
@@ -0,0 +1,395 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// ScanReport is the outcome of scanning a set of images against a severity
+// threshold.
+type ScanReport struct {
+	// Threshold is the severity at or above which an image fails.
+	Threshold Severity `json:"threshold"`
+
+	// Passed is true if no image failed.
+	Passed bool `json:"passed"`
+
+	// Images lists the scanned images, sorted by reference.
+	Images []ImageScan `json:"images"`
+}
+
+// ImageScan is the scan outcome of a single image.
+type ImageScan struct {
+	// Reference is the scanned image, by digest.
+	Reference string `json:"reference"`
+
+	// Digest is the digest of the image.
+	Digest string `json:"digest"`
+
+	// Destinations are the references the image is promoted to.
+	Destinations []string `json:"destinations,omitempty"`
+
+	// Passed is true if the image was scanned and has no vulnerability at
+	// or above the threshold.
+	Passed bool `json:"passed"`
+
+	// Error is set when the image could not be scanned.
+	Error string `json:"error,omitempty"`
+
+	// HighestSeverity is the highest severity of the vulnerabilities left
+	// after applying the exceptions.
+	HighestSeverity Severity `json:"highestSeverity"`
+
+	// Vulnerabilities are the vulnerabilities left after applying the
+	// exceptions.
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+
+	// Exceptions lists the vulnerabilities ignored because of an exception.
+	Exceptions []AppliedException `json:"exceptions,omitempty"`
+}
+
+// NewScanReport returns an empty report for the threshold.
+func NewScanReport(threshold Severity) *ScanReport {
+	return &ScanReport{Threshold: threshold, Passed: true, Images: []ImageScan{}}
+}
+
+// Add records the scan result of an image, or the error that prevented
+// scanning it, and keeps the images sorted.
+func (r *ScanReport) Add(ref, digest string, destinations []string, result *ScanResult, scanErr error) {
+	img := ImageScan{
+		Reference:       ref,
+		Digest:          digest,
+		Destinations:    destinations,
+		Vulnerabilities: []Vulnerability{},
+	}
+
+	switch {
+	case scanErr != nil:
+		img.Error = scanErr.Error()
+	case result != nil:
+		img.HighestSeverity = result.HighestSeverity
+		img.Exceptions = result.Exceptions
+		img.Passed = !result.ExceedsSeverity(r.Threshold)
+
+		if result.Vulnerabilities != nil {
+			img.Vulnerabilities = result.Vulnerabilities
+		}
+	}
+
+	if !img.Passed {
+		r.Passed = false
+	}
+
+	i := sort.Search(len(r.Images), func(i int) bool { return r.Images[i].Reference >= ref })
+	r.Images = append(r.Images, ImageScan{})
+	copy(r.Images[i+1:], r.Images[i:])
+	r.Images[i] = img
+}
+
+// Failed returns the images that could not be scanned or have
+// vulnerabilities at or above the threshold.
+func (r *ScanReport) Failed() []ImageScan {
+	failed := []ImageScan{}
+
+	for i := range r.Images {
+		if !r.Images[i].Passed {
+			failed = append(failed, r.Images[i])
+		}
+	}
+
+	return failed
+}
+
+// Err returns an error listing the failed images, or nil if all images
+// passed.
+func (r *ScanReport) Err() error {
+	failed := r.Failed()
+	if len(failed) == 0 {
+		return nil
+	}
+
+	refs := make([]string, 0, len(failed))
+	for i := range failed {
+		refs = append(refs, failed[i].Reference)
+	}
+
+	return fmt.Errorf(
+		"%d of %d images failed the vulnerability check at threshold %s: %s",
+		len(failed), len(r.Images), r.Threshold, strings.Join(refs, ", "),
+	)
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *ScanReport) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling scan report: %w", err)
+	}
+
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing scan report: %w", err)
+	}
+
+	return nil
+}
+
+const (
+	sarifSchema   = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion  = "2.1.0"
+	sarifToolName = "kpromo"
+	sarifToolURI  = "https://github.com/kubernetes-sigs/promo-tools"
+)
+
+// The subset of SARIF 2.1.0 written by WriteSARIF.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool        sarifTool         `json:"tool"`
+		Invocations []sarifInvocation `json:"invocations"`
+		Results     []sarifResult     `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID                   string             `json:"id"`
+		ShortDescription     sarifMessage       `json:"shortDescription"`
+		FullDescription      *sarifMessage      `json:"fullDescription,omitempty"`
+		DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+		Properties           sarifProperties    `json:"properties"`
+	}
+
+	sarifConfiguration struct {
+		Level string `json:"level"`
+	}
+
+	sarifProperties struct {
+		SecuritySeverity string   `json:"security-severity,omitempty"`
+		Tags             []string `json:"tags,omitempty"`
+	}
+
+	sarifInvocation struct {
+		ExecutionSuccessful bool                `json:"executionSuccessful"`
+		Notifications       []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+	}
+
+	sarifNotification struct {
+		Level   string       `json:"level"`
+		Message sarifMessage `json:"message"`
+	}
+
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+		LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+
+	sarifLogicalLocation struct {
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+	}
+)
+
+// WriteSARIF writes the report as a SARIF 2.1.0 log, with one rule per
+// vulnerability and one result per vulnerability and image. Results at or
+// above the threshold have level "error", the others "warning". Images that
+// could not be scanned are reported as tool execution errors.
+func (r *ScanReport) WriteSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           sarifToolName,
+			InformationURI: sarifToolURI,
+			Rules:          []sarifRule{},
+		}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+		Results:     []sarifResult{},
+	}
+
+	ruleIndex := map[string]int{}
+
+	for i := range r.Images {
+		img := &r.Images[i]
+
+		if img.Error != "" {
+			run.Invocations[0].ExecutionSuccessful = false
+			run.Invocations[0].Notifications = append(run.Invocations[0].Notifications, sarifNotification{
+				Level:   "error",
+				Message: sarifMessage{Text: fmt.Sprintf("scanning %s: %s", img.Reference, img.Error)},
+			})
+
+			continue
+		}
+
+		for _, v := range img.Vulnerabilities {
+			idx, ok := ruleIndex[v.ID]
+			if !ok {
+				idx = len(run.Tool.Driver.Rules)
+				ruleIndex[v.ID] = idx
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleFor(&v))
+			}
+
+			level := "warning"
+			if v.Severity >= r.Threshold {
+				level = "error"
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    v.ID,
+				RuleIndex: idx,
+				Level:     level,
+				Message:   sarifMessage{Text: sarifResultText(img.Reference, &v)},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: repositoryPath(img.Reference)},
+						Region:           sarifRegion{StartLine: 1},
+					},
+					LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: img.Reference}},
+				}},
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(&sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling SARIF report: %w", err)
+	}
+
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing SARIF report: %w", err)
+	}
+
+	return nil
+}
+
+func sarifRuleFor(v *Vulnerability) sarifRule {
+	rule := sarifRule{
+		ID:                   v.ID,
+		ShortDescription:     sarifMessage{Text: fmt.Sprintf("%s (%s)", v.ID, v.Severity)},
+		DefaultConfiguration: sarifConfiguration{Level: "warning"},
+		Properties: sarifProperties{
+			SecuritySeverity: securitySeverity(v.Severity),
+			Tags:             []string{"security", "vulnerability", strings.ToLower(v.Severity.String())},
+		},
+	}
+
+	if v.Description != "" {
+		rule.FullDescription = &sarifMessage{Text: v.Description}
+	}
+
+	return rule
+}
+
+func sarifResultText(ref string, v *Vulnerability) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s (%s) in image %s", v.ID, v.Severity, ref)
+
+	if v.Package != "" {
+		fmt.Fprintf(&b, ", package %s", v.Package)
+
+		if v.InstalledVersion != "" {
+			fmt.Fprintf(&b, " %s", v.InstalledVersion)
+		}
+	}
+
+	if v.FixedVersion != "" {
+		fmt.Fprintf(&b, ", fixed in %s", v.FixedVersion)
+	} else {
+		b.WriteString(", no fix available")
+	}
+
+	return b.String()
+}
+
+// securitySeverity maps a severity onto the CVSS-like score GitHub code
+// scanning uses to rank security alerts.
+func securitySeverity(s Severity) string {
+	switch s {
+	case SeverityCritical:
+		return "9.5"
+	case SeverityHigh:
+		return "8.0"
+	case SeverityMedium:
+		return "5.5"
+	case SeverityLow:
+		return "2.0"
+	case SeverityMinimal:
+		return "0.1"
+	default:
+		return ""
+	}
+}
+
+// repositoryPath returns the repository of an image reference without the
+// registry, which is a valid relative URI unlike registry hosts with ports.
+func repositoryPath(ref string) string {
+	r, err := name.ParseReference(ref)
+	if err != nil {
+		repo, _, _ := strings.Cut(ref, "@")
+
+		return repo
+	}
+
+	return r.Context().RepositoryStr()
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vuln
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testScanReport() *ScanReport {
+	report := NewScanReport(SeverityHigh)
+
+	report.Add("localhost:5000/staging/b@"+otherDigest, otherDigest, nil, nil, errors.New("no report"))
+	report.Add(
+		"localhost:5000/staging/a@"+testDigest, testDigest,
+		[]string{"localhost:5000/prod/a@" + testDigest},
+		&ScanResult{
+			Vulnerabilities: []Vulnerability{
+				{ID: "CVE-2024-0001", Severity: SeverityCritical, Package: "openssl", InstalledVersion: "3.0.1", FixedVersion: "3.0.2"},
+				{ID: "CVE-2024-0002", Severity: SeverityLow, Package: "zlib", InstalledVersion: "1.2"},
+			},
+			HighestSeverity: SeverityCritical,
+		},
+		nil,
+	)
+
+	return report
+}
+
+func TestScanReportAdd(t *testing.T) {
+	report := NewScanReport(SeverityHigh)
+	report.Add("gcr.io/staging/b@"+otherDigest, otherDigest, nil, &ScanResult{HighestSeverity: SeverityMedium}, nil)
+	require.True(t, report.Passed)
+
+	report = testScanReport()
+	require.False(t, report.Passed)
+	require.Len(t, report.Images, 2)
+	require.Equal(t, "localhost:5000/staging/a@"+testDigest, report.Images[0].Reference)
+	require.False(t, report.Images[0].Passed)
+	require.Equal(t, "no report", report.Images[1].Error)
+	require.Len(t, report.Failed(), 2)
+}
+
+func TestScanReportWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testScanReport().WriteJSON(&buf))
+
+	decoded := &ScanReport{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	require.Equal(t, SeverityHigh, decoded.Threshold)
+	require.Equal(t, SeverityCritical, decoded.Images[0].HighestSeverity)
+	require.Equal(t, "3.0.2", decoded.Images[0].Vulnerabilities[0].FixedVersion)
+	require.Contains(t, buf.String(), `"severity": "CRITICAL"`)
+}
+
+func TestScanReportWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testScanReport().WriteSARIF(&buf))
+
+	log := &sarifLog{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	require.Equal(t, "9.5", run.Tool.Driver.Rules[0].Properties.SecuritySeverity)
+	require.False(t, run.Invocations[0].ExecutionSuccessful)
+	require.Len(t, run.Invocations[0].Notifications, 1)
+
+	require.Len(t, run.Results, 2)
+	require.Equal(t, "error", run.Results[0].Level)
+	require.Equal(t, "warning", run.Results[1].Level)
+	require.Equal(t, 1, run.Results[1].RuleIndex)
+	require.Contains(t, run.Results[0].Message.Text, "fixed in 3.0.2")
+	require.Contains(t, run.Results[1].Message.Text, "no fix available")
+	require.Equal(t, "staging/a", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+}
+
+func TestSeverityText(t *testing.T) {
+	for s := SeverityUnspecified; s <= SeverityCritical; s++ {
+		text, err := s.MarshalText()
+		require.NoError(t, err)
+
+		var decoded Severity
+		require.NoError(t, decoded.UnmarshalText(text))
+		require.Equal(t, s, decoded)
+	}
+
+	require.Equal(t, "Severity(9)", Severity(9).String())
+}